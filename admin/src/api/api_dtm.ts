@@ -36,3 +36,11 @@ export function getDtmVersion(): Promise<AxiosResponse<any>> {
         method: 'get',
     })
 }
+
+export function getTransactionStats<T>(payload: { top_urls?: number }): Promise<AxiosResponse<T>> {
+    return request({
+        url: '/api/dtmsvr/stats',
+        method: 'get',
+        params: payload
+    })
+}
@@ -31,6 +31,13 @@ const (
 	// ResultOngoing for result of a trans/trans branch
 	ResultOngoing = dtmimp.ResultOngoing
 
+	// RetryPolicyFixed retries the failed branches every retry interval
+	RetryPolicyFixed = dtmimp.RetryPolicyFixed
+	// RetryPolicyExponential doubles the retry interval after each failure, up to the max retry interval
+	RetryPolicyExponential = dtmimp.RetryPolicyExponential
+	// RetryPolicyExponentialJitter is RetryPolicyExponential, with the retry time randomized
+	RetryPolicyExponentialJitter = dtmimp.RetryPolicyExponentialJitter
+
 	// DBTypeMysql const for driver mysql
 	DBTypeMysql = dtmimp.DBTypeMysql
 	// DBTypePostgres const for driver postgres
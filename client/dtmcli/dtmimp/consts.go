@@ -56,6 +56,13 @@ const (
 	// XaBarrier1 const for xa barrier id
 	XaBarrier1 = "01"
 
+	// RetryPolicyFixed retries the failed branches every retry interval
+	RetryPolicyFixed = "fixed"
+	// RetryPolicyExponential doubles the retry interval after each failure, up to the max retry interval
+	RetryPolicyExponential = "exponential"
+	// RetryPolicyExponentialJitter is RetryPolicyExponential, with the retry time randomized in [interval/2, interval]
+	RetryPolicyExponentialJitter = "exponential_jitter"
+
 	// ProtocolGRPC const for protocol grpc
 	ProtocolGRPC = "grpc"
 	// ProtocolHTTP const for protocol http
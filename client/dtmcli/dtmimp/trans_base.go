@@ -51,6 +51,13 @@ type TransOptions struct {
 	Concurrent     bool              `json:"concurrent" gorm:"-"`                // for trans type: saga msg
 	RetryLimit     int64             `json:"retry_limit,omitempty" gorm:"-"`     // for trans type: saga
 	RetryCount     int64             `json:"retry_count,omitempty" gorm:"-"`     // for trans type: saga
+	NotifyURL      string            `json:"notify_url,omitempty" gorm:"-"`      // http or grpc url, called when global trans is finished
+
+	RetryPolicy      string `json:"retry_policy,omitempty" gorm:"-"`       // fixed | exponential | exponential_jitter, default to the config of dtm server
+	MaxRetryInterval int64  `json:"max_retry_interval,omitempty" gorm:"-"` // the max retry interval of the exponential policies, unit: second
+
+	ScheduleAt *time.Time `json:"schedule_at,omitempty" gorm:"-"` // the global trans is not processed until this time
+	Delay      int64      `json:"delay,omitempty" gorm:"-"`       // the global trans is not processed until delay seconds after its creation, ignored if ScheduleAt is set
 }
 
 // TransBase base for all trans
@@ -94,6 +101,27 @@ func (t *TransBase) WithRetryLimit(retryLimit int64) {
 	t.RetryLimit = retryLimit
 }
 
+// WithNotifyURL defines the url to be called when global trans is succeed or failed
+func (t *TransBase) WithNotifyURL(notifyURL string) {
+	t.NotifyURL = notifyURL
+}
+
+// WithRetryPolicy defines how the retry interval grows after a failure, and the max retry interval of the exponential policies
+func (t *TransBase) WithRetryPolicy(policy string, maxRetryInterval int64) {
+	t.RetryPolicy = policy
+	t.MaxRetryInterval = maxRetryInterval
+}
+
+// WithScheduleAt defines the time when the global trans is processed
+func (t *TransBase) WithScheduleAt(at time.Time) {
+	t.ScheduleAt = &at
+}
+
+// WithDelay defines the global trans is processed delay seconds after its creation
+func (t *TransBase) WithDelay(delay int64) {
+	t.Delay = delay
+}
+
 // TransBaseFromQuery construct transaction info from request
 func TransBaseFromQuery(qs url.Values) *TransBase {
 	return NewTransBase(EscapeGet(qs, "gid"), EscapeGet(qs, "trans_type"), EscapeGet(qs, "dtm"), EscapeGet(qs, "branch_id"))
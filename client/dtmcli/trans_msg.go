@@ -16,7 +16,6 @@ import (
 // Msg reliable msg type
 type Msg struct {
 	dtmimp.TransBase
-	delay uint64 // delay call branch, unit second
 }
 
 // NewMsg create new msg
@@ -33,7 +32,7 @@ func (s *Msg) Add(action string, postData interface{}) *Msg {
 
 // SetDelay delay call branch, unit second
 func (s *Msg) SetDelay(delay uint64) *Msg {
-	s.delay = delay
+	s.WithDelay(int64(delay))
 	return s
 }
 
@@ -45,10 +44,15 @@ func (s *Msg) Prepare(queryPrepared string) error {
 
 // Submit submit the msg
 func (s *Msg) Submit() error {
-	s.BuildCustomOptions()
 	return dtmimp.TransCallDtm(&s.TransBase, "submit")
 }
 
+// Abort abort a prepared msg with the rollbackReason. a submitted msg can not be aborted, since it has no compensation
+func (s *Msg) Abort(rollbackReason string) error {
+	s.RollbackReason = rollbackReason
+	return dtmimp.TransCallDtm(&s.TransBase, "abort")
+}
+
 // DoAndSubmitDB short method for Do on db type. please see DoAndSubmit
 func (s *Msg) DoAndSubmitDB(queryPrepared string, db *sql.DB, busiCall BarrierBusiFunc) error {
 	return s.DoAndSubmit(queryPrepared, func(bb *BranchBarrier) error {
@@ -83,9 +87,6 @@ func (s *Msg) DoAndSubmit(queryPrepared string, busiCall func(bb *BranchBarrier)
 	return err
 }
 
-// BuildCustomOptions add custom options to the request context
+// BuildCustomOptions is kept for compatibility, the delay is sent by TransOptions.Delay now
 func (s *Msg) BuildCustomOptions() {
-	if s.delay > 0 {
-		s.CustomData = dtmimp.MustMarshalString(map[string]interface{}{"delay": s.delay})
-	}
 }
@@ -46,6 +46,12 @@ func (s *Saga) Submit() error {
 	return dtmimp.TransCallDtm(&s.TransBase, "submit")
 }
 
+// Abort abort a submitted saga with the rollbackReason. the started actions will be compensated
+func (s *Saga) Abort(rollbackReason string) error {
+	s.RollbackReason = rollbackReason
+	return dtmimp.TransCallDtm(&s.TransBase, "abort")
+}
+
 // BuildCustomOptions add custom options to the request context
 func (s *Saga) BuildCustomOptions() {
 	if s.Concurrent {
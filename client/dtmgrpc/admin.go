@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package dtmgrpc
+
+import (
+	context "context"
+
+	"github.com/dtm-labs/dtm/client/dtmgrpc/dtmgimp"
+	"github.com/dtm-labs/dtm/client/dtmgrpc/dtmgpb"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// QueryTrans query the global transaction and its branches from dtm server
+// the Transaction of the reply is nil if the gid is not found
+func QueryTrans(grpcServer string, gid string) (*dtmgpb.DtmQueryReply, error) {
+	r, err := dtmgimp.MustGetDtmClient(grpcServer).Query(context.Background(), &dtmgpb.DtmQueryRequest{Gid: gid})
+	return r, GrpcError2DtmError(err)
+}
+
+// QueryAllTrans scan the global transactions from position. pass "" as position to start from the beginning
+// the returned NextPosition is "" if there are no more transactions
+func QueryAllTrans(grpcServer string, position string, limit int64) (*dtmgpb.DtmAllReply, error) {
+	return SearchTrans(grpcServer, &dtmgpb.DtmAllRequest{Position: position, Limit: limit})
+}
+
+// SearchTrans scan the global transactions matching the filters in req, in the order specified by req
+func SearchTrans(grpcServer string, req *dtmgpb.DtmAllRequest) (*dtmgpb.DtmAllReply, error) {
+	r, err := dtmgimp.MustGetDtmClient(grpcServer).All(context.Background(), req)
+	return r, GrpcError2DtmError(err)
+}
+
+// ForceStop change the status of an unfinished global transaction to failed, so that dtm will not process it any more
+// Use with caution in production environment
+func ForceStop(grpcServer string, gid string) error {
+	_, err := dtmgimp.MustGetDtmClient(grpcServer).ForceStop(context.Background(), &dtmgpb.DtmRequest{Gid: gid})
+	return GrpcError2DtmError(err)
+}
+
+// ResetCronTime make the unfinished transactions, whose next_cron_time is after now + timeout seconds, to be retried immediately
+func ResetCronTime(grpcServer string, timeout int64, limit int64) (*dtmgpb.DtmResetCronTimeReply, error) {
+	r, err := dtmgimp.MustGetDtmClient(grpcServer).ResetCronTime(context.Background(), &dtmgpb.DtmResetCronTimeRequest{Timeout: timeout, Limit: limit})
+	return r, GrpcError2DtmError(err)
+}
+
+// WatchTrans receives the status changes of the transactions matching req, until ctx is done.
+// if req.Gid is specified, the stream ends with io.EOF after the transaction is finished
+func WatchTrans(ctx context.Context, grpcServer string, req *dtmgpb.DtmWatchRequest) (dtmgpb.Dtm_WatchClient, error) {
+	stream, err := dtmgimp.MustGetDtmClient(grpcServer).Watch(ctx, req)
+	return stream, GrpcError2DtmError(err)
+}
+
+// GetStats get the statistics of the transactions, with at most topURLs failing urls
+func GetStats(grpcServer string, topURLs int64) (*dtmgpb.DtmStatsReply, error) {
+	r, err := dtmgimp.MustGetDtmClient(grpcServer).Stats(context.Background(), &dtmgpb.DtmStatsRequest{TopURLs: topURLs})
+	return r, GrpcError2DtmError(err)
+}
+
+// GetVersion get the version of dtm server
+func GetVersion(grpcServer string) (string, error) {
+	r, err := dtmgimp.MustGetDtmClient(grpcServer).Version(context.Background(), &emptypb.Empty{})
+	if err != nil {
+		return "", GrpcError2DtmError(err)
+	}
+	return r.Version, nil
+}
@@ -8,6 +8,7 @@ package dtmgimp
 
 import (
 	context "context"
+	"time"
 
 	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
 	"github.com/dtm-labs/dtm/client/dtmgrpc/dtmgpb"
@@ -15,6 +16,7 @@ import (
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/proto"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // MustProtoMarshal must version of proto.Marshal
@@ -36,12 +38,17 @@ func GetDtmRequest(s *dtmimp.TransBase) *dtmgpb.DtmRequest {
 		Gid:       s.Gid,
 		TransType: s.TransType,
 		TransOptions: &dtmgpb.DtmTransOptions{
-			WaitResult:     s.WaitResult,
-			TimeoutToFail:  s.TimeoutToFail,
-			RetryInterval:  s.RetryInterval,
-			BranchHeaders:  s.BranchHeaders,
-			RequestTimeout: s.RequestTimeout,
-			RetryLimit:     s.RetryLimit,
+			WaitResult:       s.WaitResult,
+			TimeoutToFail:    s.TimeoutToFail,
+			RetryInterval:    s.RetryInterval,
+			BranchHeaders:    s.BranchHeaders,
+			RequestTimeout:   s.RequestTimeout,
+			RetryLimit:       s.RetryLimit,
+			NotifyURL:        s.NotifyURL,
+			RetryPolicy:      s.RetryPolicy,
+			MaxRetryInterval: s.MaxRetryInterval,
+			ScheduleAt:       time2Pb(s.ScheduleAt),
+			Delay:            s.Delay,
 		},
 		QueryPrepared:  s.QueryPrepared,
 		CustomedData:   s.CustomData,
@@ -51,6 +58,13 @@ func GetDtmRequest(s *dtmimp.TransBase) *dtmgpb.DtmRequest {
 	}
 }
 
+func time2Pb(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
 // DtmGrpcCall make a convenient call to dtm
 func DtmGrpcCall(s *dtmimp.TransBase, operation string) error {
 	reply := emptypb.Empty{}
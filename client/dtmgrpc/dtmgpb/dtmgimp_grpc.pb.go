@@ -29,6 +29,13 @@ type DtmClient interface {
 	Abort(ctx context.Context, in *DtmRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	RegisterBranch(ctx context.Context, in *DtmBranchRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	PrepareWorkflow(ctx context.Context, in *DtmRequest, opts ...grpc.CallOption) (*DtmProgressesReply, error)
+	Query(ctx context.Context, in *DtmQueryRequest, opts ...grpc.CallOption) (*DtmQueryReply, error)
+	All(ctx context.Context, in *DtmAllRequest, opts ...grpc.CallOption) (*DtmAllReply, error)
+	ForceStop(ctx context.Context, in *DtmRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ResetCronTime(ctx context.Context, in *DtmResetCronTimeRequest, opts ...grpc.CallOption) (*DtmResetCronTimeReply, error)
+	Version(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*DtmVersionReply, error)
+	Watch(ctx context.Context, in *DtmWatchRequest, opts ...grpc.CallOption) (Dtm_WatchClient, error)
+	Stats(ctx context.Context, in *DtmStatsRequest, opts ...grpc.CallOption) (*DtmStatsReply, error)
 }
 
 type dtmClient struct {
@@ -93,6 +100,92 @@ func (c *dtmClient) PrepareWorkflow(ctx context.Context, in *DtmRequest, opts ..
 	return out, nil
 }
 
+func (c *dtmClient) Query(ctx context.Context, in *DtmQueryRequest, opts ...grpc.CallOption) (*DtmQueryReply, error) {
+	out := new(DtmQueryReply)
+	err := c.cc.Invoke(ctx, "/dtmgimp.Dtm/Query", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dtmClient) All(ctx context.Context, in *DtmAllRequest, opts ...grpc.CallOption) (*DtmAllReply, error) {
+	out := new(DtmAllReply)
+	err := c.cc.Invoke(ctx, "/dtmgimp.Dtm/All", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dtmClient) ForceStop(ctx context.Context, in *DtmRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/dtmgimp.Dtm/ForceStop", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dtmClient) ResetCronTime(ctx context.Context, in *DtmResetCronTimeRequest, opts ...grpc.CallOption) (*DtmResetCronTimeReply, error) {
+	out := new(DtmResetCronTimeReply)
+	err := c.cc.Invoke(ctx, "/dtmgimp.Dtm/ResetCronTime", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dtmClient) Version(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*DtmVersionReply, error) {
+	out := new(DtmVersionReply)
+	err := c.cc.Invoke(ctx, "/dtmgimp.Dtm/Version", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dtmClient) Watch(ctx context.Context, in *DtmWatchRequest, opts ...grpc.CallOption) (Dtm_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Dtm_ServiceDesc.Streams[0], "/dtmgimp.Dtm/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dtmWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Dtm_WatchClient interface {
+	Recv() (*DtmTransEvent, error)
+	grpc.ClientStream
+}
+
+type dtmWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *dtmWatchClient) Recv() (*DtmTransEvent, error) {
+	m := new(DtmTransEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *dtmClient) Stats(ctx context.Context, in *DtmStatsRequest, opts ...grpc.CallOption) (*DtmStatsReply, error) {
+	out := new(DtmStatsReply)
+	err := c.cc.Invoke(ctx, "/dtmgimp.Dtm/Stats", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // DtmServer is the server API for Dtm service.
 // All implementations must embed UnimplementedDtmServer
 // for forward compatibility
@@ -103,6 +196,13 @@ type DtmServer interface {
 	Abort(context.Context, *DtmRequest) (*emptypb.Empty, error)
 	RegisterBranch(context.Context, *DtmBranchRequest) (*emptypb.Empty, error)
 	PrepareWorkflow(context.Context, *DtmRequest) (*DtmProgressesReply, error)
+	Query(context.Context, *DtmQueryRequest) (*DtmQueryReply, error)
+	All(context.Context, *DtmAllRequest) (*DtmAllReply, error)
+	ForceStop(context.Context, *DtmRequest) (*emptypb.Empty, error)
+	ResetCronTime(context.Context, *DtmResetCronTimeRequest) (*DtmResetCronTimeReply, error)
+	Version(context.Context, *emptypb.Empty) (*DtmVersionReply, error)
+	Watch(*DtmWatchRequest, Dtm_WatchServer) error
+	Stats(context.Context, *DtmStatsRequest) (*DtmStatsReply, error)
 	mustEmbedUnimplementedDtmServer()
 }
 
@@ -128,6 +228,27 @@ func (UnimplementedDtmServer) RegisterBranch(context.Context, *DtmBranchRequest)
 func (UnimplementedDtmServer) PrepareWorkflow(context.Context, *DtmRequest) (*DtmProgressesReply, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PrepareWorkflow not implemented")
 }
+func (UnimplementedDtmServer) Query(context.Context, *DtmQueryRequest) (*DtmQueryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Query not implemented")
+}
+func (UnimplementedDtmServer) All(context.Context, *DtmAllRequest) (*DtmAllReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method All not implemented")
+}
+func (UnimplementedDtmServer) ForceStop(context.Context, *DtmRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ForceStop not implemented")
+}
+func (UnimplementedDtmServer) ResetCronTime(context.Context, *DtmResetCronTimeRequest) (*DtmResetCronTimeReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResetCronTime not implemented")
+}
+func (UnimplementedDtmServer) Version(context.Context, *emptypb.Empty) (*DtmVersionReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Version not implemented")
+}
+func (UnimplementedDtmServer) Watch(*DtmWatchRequest, Dtm_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedDtmServer) Stats(context.Context, *DtmStatsRequest) (*DtmStatsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
 func (UnimplementedDtmServer) mustEmbedUnimplementedDtmServer() {}
 
 // UnsafeDtmServer may be embedded to opt out of forward compatibility for this service.
@@ -249,6 +370,135 @@ func _Dtm_PrepareWorkflow_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Dtm_Query_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DtmQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DtmServer).Query(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dtmgimp.Dtm/Query",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DtmServer).Query(ctx, req.(*DtmQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dtm_All_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DtmAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DtmServer).All(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dtmgimp.Dtm/All",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DtmServer).All(ctx, req.(*DtmAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dtm_ForceStop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DtmRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DtmServer).ForceStop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dtmgimp.Dtm/ForceStop",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DtmServer).ForceStop(ctx, req.(*DtmRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dtm_ResetCronTime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DtmResetCronTimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DtmServer).ResetCronTime(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dtmgimp.Dtm/ResetCronTime",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DtmServer).ResetCronTime(ctx, req.(*DtmResetCronTimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dtm_Version_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DtmServer).Version(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dtmgimp.Dtm/Version",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DtmServer).Version(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dtm_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DtmWatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DtmServer).Watch(m, &dtmWatchServer{stream})
+}
+
+type Dtm_WatchServer interface {
+	Send(*DtmTransEvent) error
+	grpc.ServerStream
+}
+
+type dtmWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *dtmWatchServer) Send(m *DtmTransEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Dtm_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DtmStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DtmServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dtmgimp.Dtm/Stats",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DtmServer).Stats(ctx, req.(*DtmStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Dtm_ServiceDesc is the grpc.ServiceDesc for Dtm service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -280,7 +530,37 @@ var Dtm_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PrepareWorkflow",
 			Handler:    _Dtm_PrepareWorkflow_Handler,
 		},
+		{
+			MethodName: "Query",
+			Handler:    _Dtm_Query_Handler,
+		},
+		{
+			MethodName: "All",
+			Handler:    _Dtm_All_Handler,
+		},
+		{
+			MethodName: "ForceStop",
+			Handler:    _Dtm_ForceStop_Handler,
+		},
+		{
+			MethodName: "ResetCronTime",
+			Handler:    _Dtm_ResetCronTime_Handler,
+		},
+		{
+			MethodName: "Version",
+			Handler:    _Dtm_Version_Handler,
+		},
+		{
+			MethodName: "Stats",
+			Handler:    _Dtm_Stats_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Dtm_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "client/dtmgrpc/dtmgpb/dtmgimp.proto",
 }
@@ -48,10 +48,15 @@ func (s *MsgGrpc) Prepare(queryPrepared string) error {
 
 // Submit submit the msg
 func (s *MsgGrpc) Submit() error {
-	s.Msg.BuildCustomOptions()
 	return dtmgimp.DtmGrpcCall(&s.TransBase, "Submit")
 }
 
+// Abort abort a prepared msg with the rollbackReason. a submitted msg can not be aborted, since it has no compensation
+func (s *MsgGrpc) Abort(rollbackReason string) error {
+	s.RollbackReason = rollbackReason
+	return dtmgimp.DtmGrpcCall(&s.TransBase, "Abort")
+}
+
 // DoAndSubmitDB short method for Do on db type. please see DoAndSubmit
 func (s *MsgGrpc) DoAndSubmitDB(queryPrepared string, db *sql.DB, busiCall dtmcli.BarrierBusiFunc) error {
 	return s.DoAndSubmit(queryPrepared, func(bb *dtmcli.BranchBarrier) error {
@@ -46,3 +46,9 @@ func (s *SagaGrpc) Submit() error {
 	s.Saga.BuildCustomOptions()
 	return dtmgimp.DtmGrpcCall(&s.Saga.TransBase, "Submit")
 }
+
+// Abort abort a submitted saga with the rollbackReason. the started actions will be compensated
+func (s *SagaGrpc) Abort(rollbackReason string) error {
+	s.Saga.RollbackReason = rollbackReason
+	return dtmgimp.DtmGrpcCall(&s.Saga.TransBase, "Abort")
+}
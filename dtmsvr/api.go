@@ -7,6 +7,8 @@
 package dtmsvr
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -22,20 +24,28 @@ var Version = ""
 func svcSubmit(t *TransGlobal) interface{} {
 	if t.TransType == "workflow" {
 		t.Status = dtmcli.StatusPrepared
-		t.changeStatus(t.ReqExtra["status"], withRollbackReason(t.ReqExtra["rollback_reason"]), withResult(t.ReqExtra["result"]))
-		return nil
+		return t.changeStatus(t.ReqExtra["status"], withRollbackReason(t.ReqExtra["rollback_reason"]), withResult(t.ReqExtra["result"]))
 	}
 	t.Status = dtmcli.StatusSubmitted
 	branches, err := t.saveNew()
 
 	if err == storage.ErrUniqueConflict {
-		dbt := GetTransGlobal(t.Gid)
+		dbt, err := GetTransGlobal(t.getContext(), t.Gid)
+		if err != nil {
+			return err
+		}
 		if dbt.Status == dtmcli.StatusPrepared {
-			dbt.changeStatus(t.Status)
-			branches = GetStore().FindBranches(t.Gid)
+			if err := dbt.changeStatus(t.Status); err != nil {
+				return err
+			}
+			if branches, err = GetStore().FindBranches(t.getContext(), t.Gid); err != nil {
+				return err
+			}
 		} else if dbt.Status != dtmcli.StatusSubmitted {
 			return fmt.Errorf("current status '%s', cannot sumbmit. %w", dbt.Status, dtmcli.ErrFailure)
 		}
+	} else if err != nil {
+		return err
 	}
 	return t.Process(branches)
 }
@@ -44,7 +54,10 @@ func svcPrepare(t *TransGlobal) interface{} {
 	t.Status = dtmcli.StatusPrepared
 	_, err := t.saveNew()
 	if err == storage.ErrUniqueConflict {
-		dbt := GetTransGlobal(t.Gid)
+		dbt, err := GetTransGlobal(t.getContext(), t.Gid)
+		if err != nil {
+			return err
+		}
 		if dbt.Status != dtmcli.StatusPrepared {
 			return fmt.Errorf("current status '%s', cannot prepare. %w", dbt.Status, dtmcli.ErrFailure)
 		}
@@ -57,37 +70,121 @@ func svcPrepareWorkflow(t *TransGlobal) (*storage.TransGlobalStore, []TransBranc
 	t.Status = dtmcli.StatusPrepared
 	_, err := t.saveNew()
 	if err == storage.ErrUniqueConflict { // transaction exists, query the branches
-		st := GetStore()
-		return st.FindTransGlobalStore(t.Gid), st.FindBranches(t.Gid), nil
+		return findTransAndBranches(t.getContext(), t.Gid)
 	}
 	return &t.TransGlobalStore, []TransBranch{}, err
 }
 
 func svcAbort(t *TransGlobal) interface{} {
-	dbt := GetTransGlobal(t.Gid)
+	dbt, err := GetTransGlobal(t.getContext(), t.Gid)
+	if err != nil {
+		return err
+	}
 	if dbt.TransType == "msg" && dbt.Status == dtmcli.StatusPrepared {
-		dbt.changeStatus(dtmcli.StatusFailed)
-		return nil
+		return dbt.changeStatus(dtmcli.StatusFailed)
+	}
+	if dbt.TransType == "saga" && (dbt.Status == dtmcli.StatusSubmitted || dbt.Status == dtmcli.StatusAborting) {
+		if dbt.Status == dtmcli.StatusSubmitted { // the actions may be running on other servers, so all of them are compensated
+			if err := dbt.changeStatus(dtmcli.StatusAborting, withRollbackReason(t.RollbackReason)); err != nil {
+				return err
+			}
+			dbt.compensateAll = true
+		}
+		return dbt.processStored()
 	}
 	if t.TransType != "xa" && t.TransType != "tcc" || dbt.Status != dtmcli.StatusPrepared && dbt.Status != dtmcli.StatusAborting {
 		return fmt.Errorf("trans type: '%s' current status '%s', cannot abort. %w", dbt.TransType, dbt.Status, dtmcli.ErrFailure)
 	}
-	dbt.changeStatus(dtmcli.StatusAborting, withRollbackReason(t.RollbackReason))
-	branches := GetStore().FindBranches(t.Gid)
-	return dbt.Process(branches)
+	if err := dbt.changeStatus(dtmcli.StatusAborting, withRollbackReason(t.RollbackReason)); err != nil {
+		return err
+	}
+	return dbt.processStored()
 }
 
 func svcForceStop(t *TransGlobal) interface{} {
-	dbt := GetTransGlobal(t.Gid)
+	dbt, err := GetTransGlobal(t.getContext(), t.Gid)
+	if err != nil {
+		return err
+	}
 	if dbt.Status == dtmcli.StatusSucceed || dbt.Status == dtmcli.StatusFailed {
 		return fmt.Errorf("global transaction force stop error. status: %s. error: %w", dbt.Status, dtmcli.ErrFailure)
 	}
-	dbt.changeStatus(dtmcli.StatusFailed)
-	return nil
+	return dbt.changeStatus(dtmcli.StatusFailed, withSource(eventSourceForceStop))
+}
+
+// svcQuery returns a nil transaction if the gid is not found
+func svcQuery(ctx context.Context, gid string) (*storage.TransGlobalStore, []TransBranch, error) {
+	if gid == "" {
+		return nil, nil, errors.New("no gid specified")
+	}
+	trans, branches, err := findTransAndBranches(ctx, gid)
+	if err == storage.ErrNotFound {
+		return nil, []TransBranch{}, nil
+	}
+	return trans, branches, err
+}
+
+func findTransAndBranches(ctx context.Context, gid string) (*storage.TransGlobalStore, []TransBranch, error) {
+	trans, err := GetStore().FindTransGlobalStore(ctx, gid)
+	if err != nil {
+		return nil, nil, err
+	}
+	branches, err := GetStore().FindBranches(ctx, gid)
+	if err != nil {
+		return nil, nil, err
+	}
+	return trans, branches, nil
+}
+
+func svcAll(ctx context.Context, position string, limit int64, condition storage.TransGlobalScanCondition) ([]storage.TransGlobalStore, string, error) {
+	if err := condition.Validate(); err != nil {
+		return nil, "", err
+	}
+	if position != "" {
+		if _, _, err := storage.DecodePosition(position); err != nil {
+			return nil, "", err
+		}
+	}
+	globals, err := GetStore().ScanTransGlobalStores(ctx, &position, limit, condition)
+	return globals, position, err
+}
+
+func svcHistory(ctx context.Context, gid string) ([]TransEvent, error) {
+	if gid == "" {
+		return nil, errors.New("no gid specified")
+	}
+	return GetStore().FindTransEvents(ctx, gid)
+}
+
+// StatsResult is the statistics of transactions, with the age of the oldest unfinished transaction
+type StatsResult struct {
+	storage.TransStats
+	OldestUnfinishedAge int64 `json:"oldest_unfinished_age"` // unit: second. 0 if there are no unfinished transactions
+}
+
+func svcStats(ctx context.Context, topURLs int) (*StatsResult, error) {
+	if topURLs <= 0 || topURLs > 1000 {
+		return nil, fmt.Errorf("top urls should be in [1, 1000], but got: %d", topURLs)
+	}
+	stats, err := GetStore().GetTransStats(ctx, topURLs)
+	if err != nil {
+		return nil, err
+	}
+	result := &StatsResult{TransStats: *stats}
+	if result.OldestUnfinishedTime != nil {
+		result.OldestUnfinishedAge = int64(time.Since(*result.OldestUnfinishedTime) / time.Second)
+	}
+	return result, nil
+}
+
+// unfinished transactions need to be retried as soon as possible after business downtime is recovered
+func svcResetCronTime(ctx context.Context, timeout time.Duration, limit int64) (succeedCount int64, hasRemaining bool, err error) {
+	return GetStore().ResetCronTime(ctx, timeout, limit)
 }
 
-func svcRegisterBranch(transType string, branch *TransBranch, data map[string]string) error {
+func svcRegisterBranch(ctx context.Context, transType string, branch *TransBranch, data map[string]string) error {
 	branches := []TransBranch{*branch, *branch}
+	events := []TransEvent{}
 	if transType == "tcc" {
 		for i, b := range []string{dtmimp.OpCancel, dtmimp.OpConfirm} {
 			branches[i].Op = b
@@ -99,6 +196,7 @@ func svcRegisterBranch(transType string, branch *TransBranch, data map[string]st
 		branches[1].Op = dtmimp.OpCommit
 		branches[1].URL = data["url"]
 	} else if transType == "workflow" {
+		event := newEvent(branch.Gid, branch.BranchID, data["op"], "", data["status"], eventSourceAPI, "")
 		if data["sync"] == "" && conf.UpdateBranchSync == 0 {
 			now := time.Now()
 			updateBranchAsyncChan <- branchStatus{
@@ -107,9 +205,12 @@ func svcRegisterBranch(transType string, branch *TransBranch, data map[string]st
 				op:         data["op"],
 				status:     data["status"],
 				finishTime: &now,
+				event:      event,
 			}
+			publishEvent(transType, event)
 			return nil
 		}
+		events = append(events, event)
 		branches = []TransBranch{*branch}
 		branches[0].Status = data["status"]
 		branches[0].Op = data["op"]
@@ -117,9 +218,7 @@ func svcRegisterBranch(transType string, branch *TransBranch, data map[string]st
 		return fmt.Errorf("unknow trans type: %s", transType)
 	}
 
-	err := dtmimp.CatchP(func() {
-		GetStore().LockGlobalSaveBranches(branch.Gid, dtmcli.StatusPrepared, branches, -1)
-	})
+	err := GetStore().LockGlobalSaveBranches(ctx, branch.Gid, dtmcli.StatusPrepared, branches, -1)
 	if err == storage.ErrNotFound {
 		msg := fmt.Sprintf("no trans with gid: %s status: %s found", branch.Gid, dtmcli.StatusPrepared)
 		logger.Errorf(msg)
@@ -127,5 +226,11 @@ func svcRegisterBranch(transType string, branch *TransBranch, data map[string]st
 	}
 	logger.Infof("LockGlobalSaveBranches result: %v: gid: %s old status: %s branches: %s",
 		err, branch.Gid, dtmcli.StatusPrepared, dtmimp.MustMarshalString(branches))
+	if err == nil {
+		saveEvents(ctx, events)
+		for _, event := range events {
+			publishEvent(transType, event)
+		}
+	}
 	return err
 }
@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package dtmsvr
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dtm-labs/dtm/client/dtmcli"
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/logger"
+)
+
+// BranchOperation is a manual operation of an operator on a prepared branch, used to repair the stuck transactions
+type BranchOperation struct {
+	Gid      string  `json:"gid"`
+	BranchID string  `json:"branch_id"`
+	Op       string  `json:"op"`
+	Operator string  `json:"operator"`           // required. the identity of the operator, recorded in the events
+	Status   string  `json:"status,omitempty"`   // for resolveBranch. succeed | failed
+	URL      string  `json:"url,omitempty"`      // for updateBranch. the new url, unchanged if empty
+	Data     *string `json:"data,omitempty"`     // for updateBranch. the new payload, unchanged if nil
+	BinData  []byte  `json:"bin_data,omitempty"` // for updateBranch. the new binary payload of grpc, encoded in base64
+}
+
+// svcRetryBranch calls the branch immediately, and continues processing the global transaction if the call returns
+func svcRetryBranch(ctx context.Context, o *BranchOperation) error {
+	dbt, branches, pos, err := o.findBranch(ctx, true)
+	if err != nil {
+		return err
+	}
+	b := &branches[pos]
+	saveEvents(ctx, []TransEvent{o.newEvent(b, b.Status, eventSourceRetryBranch)})
+	dbt.parseOptions()
+	dbt.eventSource = eventSourceRetryBranch
+	dbt.updateBranchSync = true
+	dbt.lastTouched = time.Now()
+	err = dbt.execBranch(b, pos)
+	if err != nil {
+		return err
+	}
+	dbt.WaitResult = false
+	return dbt.Process(branches)
+}
+
+// svcResolveBranch marks the branch as succeed or failed without calling it
+func svcResolveBranch(ctx context.Context, o *BranchOperation) error {
+	if o.Status != dtmcli.StatusSucceed && o.Status != dtmcli.StatusFailed {
+		return fmt.Errorf("status should be %s or %s. %w", dtmcli.StatusSucceed, dtmcli.StatusFailed, dtmcli.ErrFailure)
+	}
+	dbt, branches, pos, err := o.findBranch(ctx, true)
+	if err != nil {
+		return err
+	}
+	b := &branches[pos]
+	if o.Status == dtmcli.StatusFailed && (dbt.TransType != "saga" || b.Op != dtmimp.OpAction) {
+		return fmt.Errorf("only the action of saga can be resolved to failed. %w", dtmcli.ErrFailure)
+	}
+	event := o.newEvent(b, o.Status, eventSourceResolveBranch)
+	now := time.Now()
+	b.Status = o.Status
+	b.FinishTime = &now
+	b.UpdateTime = &now
+	err = o.saveBranch(dbt, b, pos, event)
+	if err != nil {
+		return err
+	}
+	publishEvent(dbt.TransType, event)
+	dbt.eventSource = eventSourceResolveBranch
+	dbt.WaitResult = false
+	return dbt.Process(branches)
+}
+
+// svcUpdateBranch changes the url or payload of the branch, which will be used by the following calls
+func svcUpdateBranch(ctx context.Context, o *BranchOperation) error {
+	if o.URL == "" && o.Data == nil && o.BinData == nil {
+		return fmt.Errorf("url, data or bin_data should be specified. %w", dtmcli.ErrFailure)
+	}
+	dbt, branches, pos, err := o.findBranch(ctx, false)
+	if err != nil {
+		return err
+	}
+	b := &branches[pos]
+	if o.URL != "" {
+		b.URL = o.URL
+	}
+	if o.Data != nil {
+		b.BinData = []byte(*o.Data)
+	} else if o.BinData != nil {
+		b.BinData = o.BinData
+	}
+	now := time.Now()
+	b.UpdateTime = &now
+	return o.saveBranch(dbt, b, pos, o.newEvent(b, b.Status, eventSourceUpdateBranch))
+}
+
+// findBranch finds the prepared branch in an unfinished global transaction.
+// if runnable is true, the branch should be the one that dtm will call in current global status
+func (o *BranchOperation) findBranch(ctx context.Context, runnable bool) (*TransGlobal, []TransBranch, int, error) {
+	if o.Operator == "" {
+		return nil, nil, 0, fmt.Errorf("operator should be specified. %w", dtmcli.ErrFailure)
+	}
+	dbt, err := GetTransGlobal(ctx, o.Gid)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	if dbt.IsFinished() || dbt.TransType == "workflow" {
+		return nil, nil, 0, fmt.Errorf("trans type: '%s' current status '%s', cannot operate branch. %w", dbt.TransType, dbt.Status, dtmcli.ErrFailure)
+	}
+	branches, err := GetStore().FindBranches(ctx, o.Gid)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	for i, b := range branches {
+		if b.BranchID != o.BranchID || b.Op != o.Op {
+			continue
+		}
+		if b.Status != dtmcli.StatusPrepared {
+			return nil, nil, 0, fmt.Errorf("branch %s %s current status '%s', cannot operate branch. %w", b.BranchID, b.Op, b.Status, dtmcli.ErrFailure)
+		}
+		if runnable && !isBranchOpRunnable(dbt.Status, b.Op) {
+			return nil, nil, 0, fmt.Errorf("branch op '%s' is not called in global status '%s'. %w", b.Op, dbt.Status, dtmcli.ErrFailure)
+		}
+		return dbt, branches, i, nil
+	}
+	return nil, nil, 0, fmt.Errorf("branch %s %s of gid %s not found. %w", o.BranchID, o.Op, o.Gid, dtmcli.ErrFailure)
+}
+
+// saveBranch saves the branch if the global status is not changed since it is read
+func (o *BranchOperation) saveBranch(dbt *TransGlobal, b *TransBranch, pos int, event TransEvent) error {
+	err := GetStore().LockGlobalSaveBranches(dbt.getContext(), dbt.Gid, dbt.Status, []TransBranch{*b}, pos)
+	if err == storage.ErrNotFound {
+		return fmt.Errorf("status of gid %s has been changed, please query and retry. %w", dbt.Gid, dtmcli.ErrFailure)
+	}
+	logger.Infof("%s by %s result: %v: branch: %s", event.Source, o.Operator, err, b.String())
+	if err == nil {
+		saveEvents(dbt.getContext(), []TransEvent{event})
+	}
+	return err
+}
+
+func (o *BranchOperation) newEvent(b *TransBranch, newStatus string, source string) TransEvent {
+	event := newEvent(b.Gid, b.BranchID, b.Op, b.Status, newStatus, source, "")
+	event.Operator = o.Operator
+	return event
+}
+
+// isBranchOpRunnable returns true if the branch op will be called by dtm in the global status
+func isBranchOpRunnable(globalStatus string, op string) bool {
+	if globalStatus == dtmcli.StatusSubmitted {
+		return op == dtmimp.OpAction || op == dtmimp.OpConfirm || op == dtmimp.OpCommit
+	} else if globalStatus == dtmcli.StatusAborting {
+		return op == dtmimp.OpCompensate || op == dtmimp.OpCancel || op == dtmimp.OpRollback
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package dtmsvr
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dtm-labs/dtm/client/dtmcli"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/logger"
+)
+
+// the operations supported by bulk api
+const (
+	bulkForceStop = "forceStop" // change the status to failed, like forceStop
+	bulkRetryNow  = "retryNow"  // retry by cron as soon as possible, the backoff of retry interval is kept
+	bulkResume    = "resume"    // retry by cron as soon as possible, and the retry interval is reset to the normal one
+)
+
+// BulkRequest selects the unfinished transactions for a bulk operation
+type BulkRequest struct {
+	Condition   storage.TransGlobalScanCondition
+	URLContains string // if specified, only the transactions having a branch url containing it are selected
+	DryRun      bool   // if true, only the matched gids are returned
+	BatchSize   int64  // the number of transactions scanned from store in a batch
+	Limit       int64  // the max number of transactions operated in a request
+}
+
+// BulkResult is the result of a bulk operation
+type BulkResult struct {
+	DryRun       bool              `json:"dry_run"`
+	Gids         []string          `json:"gids"`             // the matched gids
+	Errors       map[string]string `json:"errors,omitempty"` // gid => error, for the gids failed to operate
+	HasRemaining bool              `json:"has_remaining"`    // true if more transactions are matched than limit
+}
+
+func svcBulk(ctx context.Context, operation string, req *BulkRequest) (*BulkResult, error) {
+	if operation != bulkForceStop && operation != bulkRetryNow && operation != bulkResume {
+		return nil, fmt.Errorf("unknown bulk operation: %s", operation)
+	}
+	if req.BatchSize <= 0 || req.BatchSize > 1000 {
+		return nil, fmt.Errorf("batch size should be in [1, 1000], but got: %d", req.BatchSize)
+	}
+	if req.Limit <= 0 {
+		return nil, fmt.Errorf("limit should be positive, but got: %d", req.Limit)
+	}
+	if err := req.Condition.Validate(); err != nil {
+		return nil, err
+	}
+	// scan in the order of creation, so that the operated transactions will not be scanned again
+	req.Condition.SortBy = storage.SortByCreateTime
+	req.Condition.SortAsc = true
+	req.Condition.Unfinished = true // the finished transactions are not counted in BatchSize
+	result := &BulkResult{DryRun: req.DryRun, Gids: []string{}, Errors: map[string]string{}}
+	for position := ""; ; {
+		globals, err := GetStore().ScanTransGlobalStores(ctx, &position, req.BatchSize, req.Condition)
+		if err != nil {
+			return nil, err
+		}
+		for i := range globals {
+			g := &globals[i]
+			matched, err := req.matchURL(ctx, g.Gid)
+			if err != nil {
+				return nil, err
+			} else if !matched {
+				continue
+			}
+			if int64(len(result.Gids)) == req.Limit {
+				result.HasRemaining = true
+				return result, nil
+			}
+			result.Gids = append(result.Gids, g.Gid)
+			if req.DryRun {
+				continue
+			}
+			err = bulkOperate(operation, &TransGlobal{TransGlobalStore: *g, Context: ctx})
+			logger.Infof("bulk %s gid: %s result: %v", operation, g.Gid, err)
+			if err != nil {
+				result.Errors[g.Gid] = err.Error()
+			}
+		}
+		if position == "" {
+			return result, nil
+		}
+	}
+}
+
+func (req *BulkRequest) matchURL(ctx context.Context, gid string) (bool, error) {
+	if req.URLContains == "" {
+		return true, nil
+	}
+	branches, err := GetStore().FindBranches(ctx, gid)
+	if err != nil {
+		return false, err
+	}
+	for _, b := range branches {
+		if strings.Contains(b.URL, req.URLContains) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// bulkOperate operates the trans. the status of trans is checked by store, so the trans changed after scanning will fail
+func bulkOperate(operation string, t *TransGlobal) error {
+	t.parseOptions()
+	now := time.Now()
+	switch operation {
+	case bulkForceStop:
+		return t.changeStatus(dtmcli.StatusFailed, withSource(eventSourceForceStop))
+	case bulkRetryNow:
+		return GetStore().TouchCronTime(t.getContext(), &t.TransGlobalStore, t.NextCronInterval, &now)
+	case bulkResume:
+		return GetStore().TouchCronTime(t.getContext(), &t.TransGlobalStore, t.getNextCronInterval(cronReset), &now)
+	}
+	return nil
+}
@@ -8,11 +8,14 @@ package dtmsvr
 
 import (
 	"context"
+	"time"
 
 	"github.com/dtm-labs/dtm/client/dtmcli"
 	"github.com/dtm-labs/dtm/client/dtmgrpc"
 	pb "github.com/dtm-labs/dtm/client/dtmgrpc/dtmgpb"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // dtmServer is used to implement dtmgimp.DtmServer.
@@ -40,7 +43,7 @@ func (s *dtmServer) Abort(ctx context.Context, in *pb.DtmRequest) (*emptypb.Empt
 }
 
 func (s *dtmServer) RegisterBranch(ctx context.Context, in *pb.DtmBranchRequest) (*emptypb.Empty, error) {
-	r := svcRegisterBranch(in.TransType, &TransBranch{
+	r := svcRegisterBranch(ctx, in.TransType, &TransBranch{
 		Gid:      in.Gid,
 		BranchID: in.BranchID,
 		Status:   dtmcli.StatusPrepared,
@@ -69,3 +72,177 @@ func (s *dtmServer) PrepareWorkflow(ctx context.Context, in *pb.DtmRequest) (*pb
 	}
 	return reply, dtmgrpc.DtmError2GrpcError(err)
 }
+
+func (s *dtmServer) ForceStop(ctx context.Context, in *pb.DtmRequest) (*emptypb.Empty, error) {
+	r := svcForceStop(TransFromDtmRequest(ctx, in))
+	return &emptypb.Empty{}, dtmgrpc.DtmError2GrpcError(r)
+}
+
+func (s *dtmServer) Query(ctx context.Context, in *pb.DtmQueryRequest) (*pb.DtmQueryReply, error) {
+	trans, branches, err := svcQuery(ctx, in.Gid)
+	if err != nil {
+		return nil, dtmgrpc.DtmError2GrpcError(err)
+	}
+	reply := &pb.DtmQueryReply{Branches: []*pb.DtmTransBranch{}}
+	if trans != nil {
+		reply.Transaction = transGlobal2Pb(trans)
+	}
+	for i := range branches {
+		reply.Branches = append(reply.Branches, transBranch2Pb(&branches[i]))
+	}
+	return reply, nil
+}
+
+func (s *dtmServer) All(ctx context.Context, in *pb.DtmAllRequest) (*pb.DtmAllReply, error) {
+	limit := in.Limit
+	if limit == 0 {
+		limit = 100
+	}
+	globals, nextPosition, err := svcAll(ctx, in.Position, limit, storage.TransGlobalScanCondition{
+		Status:          in.Status,
+		TransType:       in.TransType,
+		GidPrefix:       in.GidPrefix,
+		CreateTimeStart: pb2Time(in.CreateTimeStart),
+		CreateTimeEnd:   pb2Time(in.CreateTimeEnd),
+		UpdateTimeStart: pb2Time(in.UpdateTimeStart),
+		UpdateTimeEnd:   pb2Time(in.UpdateTimeEnd),
+		StuckSeconds:    in.StuckSeconds,
+		SortBy:          in.SortBy,
+		SortAsc:         in.SortAsc,
+	})
+	if err != nil {
+		return nil, dtmgrpc.DtmError2GrpcError(err)
+	}
+	reply := &pb.DtmAllReply{Transactions: []*pb.DtmTransGlobal{}, NextPosition: nextPosition}
+	for i := range globals {
+		reply.Transactions = append(reply.Transactions, transGlobal2Pb(&globals[i]))
+	}
+	return reply, nil
+}
+
+func (s *dtmServer) ResetCronTime(ctx context.Context, in *pb.DtmResetCronTimeRequest) (*pb.DtmResetCronTimeReply, error) {
+	timeout := in.Timeout
+	if timeout == 0 {
+		timeout = 3 * conf.TimeoutToFail
+	}
+	limit := in.Limit
+	if limit == 0 {
+		limit = 100
+	}
+	succeedCount, hasRemaining, err := svcResetCronTime(ctx, time.Duration(timeout)*time.Second, limit)
+	if err != nil {
+		return nil, dtmgrpc.DtmError2GrpcError(err)
+	}
+	return &pb.DtmResetCronTimeReply{SucceedCount: succeedCount, HasRemaining: hasRemaining}, nil
+}
+
+func (s *dtmServer) Watch(in *pb.DtmWatchRequest, stream pb.Dtm_WatchServer) error {
+	condition := WatchCondition{Gid: in.Gid, GidPrefix: in.GidPrefix, TransType: in.TransType}
+	err := watchTrans(stream.Context(), condition, func(event *TransEvent) error {
+		return stream.Send(transEvent2Pb(event))
+	})
+	return dtmgrpc.DtmError2GrpcError(err)
+}
+
+func (s *dtmServer) Stats(ctx context.Context, in *pb.DtmStatsRequest) (*pb.DtmStatsReply, error) {
+	topURLs := in.TopURLs
+	if topURLs == 0 {
+		topURLs = 10
+	}
+	result, err := svcStats(ctx, int(topURLs))
+	if err != nil {
+		return nil, dtmgrpc.DtmError2GrpcError(err)
+	}
+	reply := &pb.DtmStatsReply{
+		Counts:               []*pb.DtmTransCount{},
+		OldestUnfinishedTime: time2Pb(result.OldestUnfinishedTime),
+		OldestUnfinishedAge:  result.OldestUnfinishedAge,
+		CronLagCount:         result.CronLagCount,
+		FailingURLs:          []*pb.DtmURLCount{},
+	}
+	for _, c := range result.Counts {
+		reply.Counts = append(reply.Counts, &pb.DtmTransCount{Status: c.Status, TransType: c.TransType, Count: c.Count})
+	}
+	for _, u := range result.FailingURLs {
+		reply.FailingURLs = append(reply.FailingURLs, &pb.DtmURLCount{URL: u.URL, Count: u.Count})
+	}
+	return reply, nil
+}
+
+func (s *dtmServer) Version(ctx context.Context, in *emptypb.Empty) (*pb.DtmVersionReply, error) {
+	return &pb.DtmVersionReply{Version: Version}, nil
+}
+
+func time2Pb(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func pb2Time(t *timestamppb.Timestamp) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return t.AsTime()
+}
+
+func transGlobal2Pb(g *storage.TransGlobalStore) *pb.DtmTransGlobal {
+	return &pb.DtmTransGlobal{
+		ID:               g.ID,
+		Gid:              g.Gid,
+		TransType:        g.TransType,
+		Status:           g.Status,
+		QueryPrepared:    g.QueryPrepared,
+		Protocol:         g.Protocol,
+		CreateTime:       time2Pb(g.CreateTime),
+		UpdateTime:       time2Pb(g.UpdateTime),
+		FinishTime:       time2Pb(g.FinishTime),
+		RollbackTime:     time2Pb(g.RollbackTime),
+		Result:           g.Result,
+		RollbackReason:   g.RollbackReason,
+		Options:          g.Options,
+		CustomData:       g.CustomData,
+		NextCronInterval: g.NextCronInterval,
+		NextCronTime:     time2Pb(g.NextCronTime),
+		Owner:            g.Owner,
+		ExtData:          g.ExtData,
+		NotifyStatus:     g.NotifyStatus,
+	}
+}
+
+func transBranch2Pb(b *TransBranch) *pb.DtmTransBranch {
+	return &pb.DtmTransBranch{
+		ID:             b.ID,
+		Gid:            b.Gid,
+		URL:            b.URL,
+		BinData:        b.BinData,
+		BranchID:       b.BranchID,
+		Op:             b.Op,
+		Status:         b.Status,
+		CreateTime:     time2Pb(b.CreateTime),
+		UpdateTime:     time2Pb(b.UpdateTime),
+		FinishTime:     time2Pb(b.FinishTime),
+		RollbackTime:   time2Pb(b.RollbackTime),
+		Attempts:       b.Attempts,
+		LastError:      b.LastError,
+		LastStatusCode: int64(b.LastStatusCode),
+		LastResponse:   b.LastResponse,
+	}
+}
+
+func transEvent2Pb(e *TransEvent) *pb.DtmTransEvent {
+	return &pb.DtmTransEvent{
+		ID:         e.ID,
+		Gid:        e.Gid,
+		BranchID:   e.BranchID,
+		Op:         e.Op,
+		OldStatus:  e.OldStatus,
+		NewStatus:  e.NewStatus,
+		Source:     e.Source,
+		Owner:      e.Owner,
+		Operator:   e.Operator,
+		Error:      e.Error,
+		CreateTime: time2Pb(e.CreateTime),
+	}
+}
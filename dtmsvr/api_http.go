@@ -7,13 +7,15 @@
 package dtmsvr
 
 import (
-	"errors"
+	"fmt"
 	"strconv"
 	"time"
 
 	"github.com/dtm-labs/dtm/client/dtmcli"
 	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
 	"github.com/dtm-labs/dtm/dtmutil"
+	"github.com/dtm-labs/logger"
 	"github.com/gin-gonic/gin"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
@@ -34,6 +36,18 @@ func addRoute(engine *gin.Engine) {
 	engine.GET("/api/dtmsvr/query", dtmutil.WrapHandler2(query))
 	engine.GET("/api/dtmsvr/all", dtmutil.WrapHandler2(all))
 	engine.GET("/api/dtmsvr/resetCronTime", dtmutil.WrapHandler2(resetCronTime))
+	engine.GET("/api/dtmsvr/history", dtmutil.WrapHandler2(history))
+	engine.GET("/api/dtmsvr/stats", dtmutil.WrapHandler2(stats))
+	engine.GET("/api/dtmsvr/cluster", dtmutil.WrapHandler2(cluster))
+	engine.POST("/api/dtmsvr/retryBranch", dtmutil.WrapHandler2(retryBranch))     // call a stuck branch immediately
+	engine.POST("/api/dtmsvr/resolveBranch", dtmutil.WrapHandler2(resolveBranch)) // mark a branch as succeed or failed manually
+	engine.POST("/api/dtmsvr/updateBranch", dtmutil.WrapHandler2(updateBranch))   // change the url or payload of a stuck branch
+	// bulk operations on the unfinished transactions selected by the filters of all
+	engine.POST("/api/dtmsvr/bulkForceStop", dtmutil.WrapHandler2(bulk(bulkForceStop)))
+	engine.POST("/api/dtmsvr/bulkRetryNow", dtmutil.WrapHandler2(bulk(bulkRetryNow)))
+	engine.POST("/api/dtmsvr/bulkResume", dtmutil.WrapHandler2(bulk(bulkResume)))
+	// server-sent events is a stream response, which can not be wrapped
+	engine.GET("/api/dtmsvr/watch", watch)
 
 	// add prometheus exporter
 	h := promhttp.Handler()
@@ -73,16 +87,14 @@ func registerBranch(c *gin.Context) interface{} {
 		Status:   dtmcli.StatusPrepared,
 		BinData:  []byte(data["data"]),
 	}
-	return svcRegisterBranch(data["trans_type"], &branch, data)
+	return svcRegisterBranch(c.Request.Context(), data["trans_type"], &branch, data)
 }
 
 func query(c *gin.Context) interface{} {
-	gid := c.Query("gid")
-	if gid == "" {
-		return errors.New("no gid specified")
+	trans, branches, err := svcQuery(c.Request.Context(), c.Query("gid"))
+	if err != nil {
+		return err
 	}
-	trans := GetStore().FindTransGlobalStore(gid)
-	branches := GetStore().FindBranches(gid)
 	return map[string]interface{}{"transaction": trans, "branches": branches}
 }
 
@@ -97,8 +109,131 @@ func prepareWorkflow(c *gin.Context) interface{} {
 func all(c *gin.Context) interface{} {
 	position := c.Query("position")
 	sLimit := dtmimp.OrString(c.Query("limit"), "100")
-	globals := GetStore().ScanTransGlobalStores(&position, int64(dtmimp.MustAtoi(sLimit)))
-	return map[string]interface{}{"transactions": globals, "next_position": position}
+	condition, err := scanConditionFromContext(c)
+	if err != nil {
+		return err
+	}
+	globals, nextPosition, err := svcAll(c.Request.Context(), position, int64(dtmimp.MustAtoi(sLimit)), condition)
+	if err != nil {
+		return err
+	}
+	return map[string]interface{}{"transactions": globals, "next_position": nextPosition}
+}
+
+// scanConditionFromContext parses the filters of all. times are in RFC3339 format, like 2006-01-02T15:04:05+08:00
+func scanConditionFromContext(c *gin.Context) (storage.TransGlobalScanCondition, error) {
+	condition := storage.TransGlobalScanCondition{
+		Status:       c.Query("status"),
+		TransType:    c.Query("trans_type"),
+		GidPrefix:    c.Query("gid_prefix"),
+		StuckSeconds: int64(dtmimp.MustAtoi(dtmimp.OrString(c.Query("stuck_seconds"), "0"))),
+		SortBy:       c.Query("sort_by"),
+	}
+	sortOrder := c.Query("sort_order")
+	if sortOrder != "" && sortOrder != "asc" && sortOrder != "desc" {
+		return condition, fmt.Errorf("sort order should be asc or desc, but got: %s", sortOrder)
+	}
+	condition.SortAsc = sortOrder == "asc"
+	for key, t := range map[string]*time.Time{
+		"create_time_start": &condition.CreateTimeStart,
+		"create_time_end":   &condition.CreateTimeEnd,
+		"update_time_start": &condition.UpdateTimeStart,
+		"update_time_end":   &condition.UpdateTimeEnd,
+	} {
+		if v := c.Query(key); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return condition, fmt.Errorf("bad %s: %w", key, err)
+			}
+			*t = parsed
+		}
+	}
+	return condition, nil
+}
+
+// history returns the status transitions of the global transaction and its branches
+func history(c *gin.Context) interface{} {
+	events, err := svcHistory(c.Request.Context(), c.Query("gid"))
+	if err != nil {
+		return err
+	}
+	return map[string]interface{}{"events": events}
+}
+
+func stats(c *gin.Context) interface{} {
+	result, err := svcStats(c.Request.Context(), dtmimp.MustAtoi(dtmimp.OrString(c.Query("top_urls"), "10")))
+	if err != nil {
+		return err
+	}
+	return result
+}
+
+// cluster returns the dtm servers sharing the store, with their version and load
+func cluster(c *gin.Context) interface{} {
+	nodes, err := svcCluster(c.Request.Context())
+	if err != nil {
+		return err
+	}
+	return map[string]interface{}{"nodes": nodes}
+}
+
+// watch pushes the status changes as server-sent events, until the watched gid is finished or the client disconnects
+func watch(c *gin.Context) {
+	condition := WatchCondition{
+		Gid:       c.Query("gid"),
+		GidPrefix: c.Query("gid_prefix"),
+		TransType: c.Query("trans_type"),
+	}
+	err := watchTrans(c.Request.Context(), condition, func(event *TransEvent) error {
+		c.SSEvent("change", event)
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil && !c.Writer.Written() { // the error before the stream starts is returned as normal response
+		c.JSON(dtmcli.Result2HttpJSON(err))
+	} else if err != nil {
+		logger.Errorf("watch %v error: %v", condition, err)
+	}
+}
+
+func bulk(operation string) func(c *gin.Context) interface{} {
+	return func(c *gin.Context) interface{} {
+		condition, err := scanConditionFromContext(c)
+		if err != nil {
+			return err
+		}
+		result, err := svcBulk(c.Request.Context(), operation, &BulkRequest{
+			Condition:   condition,
+			URLContains: c.Query("url_contains"),
+			DryRun:      c.Query("dry_run") == "true",
+			BatchSize:   int64(dtmimp.MustAtoi(dtmimp.OrString(c.Query("batch_size"), "100"))),
+			Limit:       int64(dtmimp.MustAtoi(dtmimp.OrString(c.Query("limit"), "1000"))),
+		})
+		if err != nil {
+			return err
+		}
+		return result
+	}
+}
+
+func retryBranch(c *gin.Context) interface{} {
+	return svcRetryBranch(c.Request.Context(), branchOperationFromContext(c))
+}
+
+func resolveBranch(c *gin.Context) interface{} {
+	return svcResolveBranch(c.Request.Context(), branchOperationFromContext(c))
+}
+
+func updateBranch(c *gin.Context) interface{} {
+	return svcUpdateBranch(c.Request.Context(), branchOperationFromContext(c))
+}
+
+func branchOperationFromContext(c *gin.Context) *BranchOperation {
+	o := BranchOperation{}
+	err := c.BindJSON(&o)
+	e2p(err)
+	o.Gid = dtmimp.Escape(o.Gid)
+	return &o
 }
 
 // unfinished transactions need to be retried as soon as possible after business downtime is recovered
@@ -107,7 +242,7 @@ func resetCronTime(c *gin.Context) interface{} {
 	sLimit := dtmimp.OrString(c.Query("limit"), "100")
 	timeout := time.Duration(dtmimp.MustAtoi(sTimeoutSecond)) * time.Second
 
-	succeedCount, hasRemaining, err := GetStore().ResetCronTime(timeout, int64(dtmimp.MustAtoi(sLimit)))
+	succeedCount, hasRemaining, err := svcResetCronTime(c.Request.Context(), timeout, int64(dtmimp.MustAtoi(sLimit)))
 	if err != nil {
 		return err
 	}
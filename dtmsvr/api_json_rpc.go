@@ -1,6 +1,7 @@
 package dtmsvr
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -20,7 +21,7 @@ type jrpcReq struct {
 }
 
 func addJrpcRouter(engine *gin.Engine) {
-	type jrpcFunc = func(interface{}) interface{}
+	type jrpcFunc = func(context.Context, interface{}) interface{}
 	handlers := map[string]jrpcFunc{
 		"newGid":         jrpcNewGid,
 		"prepare":        jrpcPrepare,
@@ -52,7 +53,7 @@ func addJrpcRouter(engine *gin.Engine) {
 					"message": fmt.Sprintf("Method not found: %s", req.Method),
 				}
 			} else if handlers[req.Method] != nil {
-				return handlers[req.Method](req.Params)
+				return handlers[req.Method](c.Request.Context(), req.Params)
 			}
 			return nil
 		}()
@@ -100,30 +101,31 @@ func addJrpcRouter(engine *gin.Engine) {
 }
 
 // TransFromJrpcParams construct TransGlobal from jrpc params
-func TransFromJrpcParams(params interface{}) *TransGlobal {
+func TransFromJrpcParams(ctx context.Context, params interface{}) *TransGlobal {
 	t := TransGlobal{}
 	dtmimp.MustRemarshal(params, &t)
 	t.setupPayloads()
+	t.Context = ctx
 	return &t
 }
 
-func jrpcNewGid(interface{}) interface{} {
+func jrpcNewGid(context.Context, interface{}) interface{} {
 	return map[string]interface{}{"gid": GenGid()}
 }
 
-func jrpcPrepare(params interface{}) interface{} {
-	return svcPrepare(TransFromJrpcParams(params))
+func jrpcPrepare(ctx context.Context, params interface{}) interface{} {
+	return svcPrepare(TransFromJrpcParams(ctx, params))
 }
 
-func jrpcSubmit(params interface{}) interface{} {
-	return svcSubmit(TransFromJrpcParams(params))
+func jrpcSubmit(ctx context.Context, params interface{}) interface{} {
+	return svcSubmit(TransFromJrpcParams(ctx, params))
 }
 
-func jrpcAbort(params interface{}) interface{} {
-	return svcAbort(TransFromJrpcParams(params))
+func jrpcAbort(ctx context.Context, params interface{}) interface{} {
+	return svcAbort(TransFromJrpcParams(ctx, params))
 }
 
-func jrpcRegisterBranch(params interface{}) interface{} {
+func jrpcRegisterBranch(ctx context.Context, params interface{}) interface{} {
 	data := map[string]string{}
 	dtmimp.MustRemarshal(params, &data)
 	branch := TransBranch{
@@ -132,5 +134,5 @@ func jrpcRegisterBranch(params interface{}) interface{} {
 		Status:   dtmcli.StatusPrepared,
 		BinData:  []byte(data["data"]),
 	}
-	return svcRegisterBranch(data["trans_type"], &branch, data)
+	return svcRegisterBranch(ctx, data["trans_type"], &branch, data)
 }
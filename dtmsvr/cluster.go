@@ -0,0 +1,108 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package dtmsvr
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/logger"
+)
+
+// nodeStartTime is the start time of this dtm server
+var nodeStartTime = time.Now()
+
+// processingCount is the num of the running processInner, reported as the load of this dtm server
+var processingCount int64
+
+// ClusterNode is a dtm server sharing the store
+type ClusterNode struct {
+	storage.NodeStore
+	Alive bool `json:"alive"` // false if the heartbeat timeout, the node will be removed by the next heartbeat of other nodes
+	Self  bool `json:"self"`  // true if the node is the dtm server serving the request
+}
+
+func currentNode() *storage.NodeStore {
+	now := time.Now()
+	return &storage.NodeStore{
+		ID:            serverOwner,
+		Version:       Version,
+		Load:          atomic.LoadInt64(&processingCount),
+		StartTime:     &nodeStartTime,
+		HeartbeatTime: &now,
+	}
+}
+
+func isNodeAlive(node *storage.NodeStore) bool {
+	return node.ID == serverOwner ||
+		node.HeartbeatTime != nil && time.Since(*node.HeartbeatTime) < time.Duration(conf.HeartbeatTimeout)*time.Second
+}
+
+// CronHeartbeat saves the heartbeat of this dtm server every HeartbeatInterval, num == -1 indicate for ever
+func CronHeartbeat(num int) {
+	if _, ok := GetStore().(storage.NodeRegistry); !ok {
+		logger.Infof("node heartbeat is ignored, because the nodes can not be kept by %s", conf.Store.Driver)
+		return
+	}
+	for i := 0; (i < num || num == -1) && !isStopping(); i++ {
+		if err := HeartbeatOnce(); err != nil {
+			logger.Errorf("node heartbeat error: %v", err)
+		}
+		if num != 1 {
+			sleepUnlessStopping(time.Duration(conf.HeartbeatInterval) * time.Second)
+		}
+	}
+}
+
+// HeartbeatOnce saves the heartbeat of this dtm server, then finds the nodes whose heartbeat timeout.
+// the trans locked by the dead nodes are reclaimed to be due now, instead of waiting for RetryInterval, then the dead nodes are removed
+func HeartbeatOnce() (rerr error) {
+	defer handlePanic(&rerr)
+	ctx := context.Background()
+	registry := GetStore().(storage.NodeRegistry)
+	if err := registry.SaveNode(ctx, currentNode()); err != nil {
+		return err
+	}
+	nodes, err := registry.FindNodes(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range nodes {
+		node := &nodes[i]
+		if isNodeAlive(node) {
+			continue
+		}
+		reclaimed, err := registry.ReclaimTrans(ctx, node.ID)
+		if err != nil {
+			return err
+		}
+		logger.Warnf("dtm node %s is dead, last heartbeat at %v. %d trans locked by it are reclaimed", node.ID, node.HeartbeatTime, reclaimed)
+		if err := registry.RemoveNode(ctx, node.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// svcCluster returns the dtm servers sharing the store. only this dtm server is returned if the store can not keep the nodes
+func svcCluster(ctx context.Context) ([]ClusterNode, error) {
+	registry, ok := GetStore().(storage.NodeRegistry)
+	if !ok {
+		return []ClusterNode{{NodeStore: *currentNode(), Alive: true, Self: true}}, nil
+	}
+	nodes, err := registry.FindNodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := []ClusterNode{}
+	for i := range nodes {
+		result = append(result, ClusterNode{NodeStore: nodes[i], Alive: isNodeAlive(&nodes[i]), Self: nodes[i].ID == serverOwner})
+	}
+	return result, nil
+}
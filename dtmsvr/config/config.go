@@ -2,7 +2,11 @@ package config
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"net"
+	"strconv"
+	"strings"
 
 	"github.com/dtm-labs/dtm/client/dtmcli"
 	"github.com/dtm-labs/logger"
@@ -20,6 +24,10 @@ const (
 	BoltDb = "boltdb"
 	// Postgres is postgres driver
 	Postgres = "postgres"
+	// Memory is memory driver, for development and tests
+	Memory = "memory"
+	// Mongo is mongo driver
+	Mongo = "mongo"
 )
 
 // MicroService config type for microservice based grpc
@@ -39,6 +47,20 @@ type HTTPMicroService struct {
 	EndPoint        string `yaml:"EndPoint"`
 }
 
+// Retention config for purging the finished transactions. only for mysql/postgres, the other stores expire the data by themselves
+type Retention struct {
+	Mode      string `yaml:"Mode"`                    // archive: move to the history tables; delete: delete them. empty to disable
+	Age       int64  `yaml:"Age" default:"604800"`    // the finished transactions not updated for Age seconds are purged
+	BatchSize int64  `yaml:"BatchSize" default:"100"` // the number of transactions purged in a db transaction
+	Interval  int64  `yaml:"Interval" default:"60"`   // the interval in seconds between two rounds of purging
+}
+
+// the modes of Retention
+const (
+	RetentionArchive = "archive"
+	RetentionDelete  = "delete"
+)
+
 // Log config customize log
 type Log struct {
 	Outputs            string `yaml:"Outputs" default:"stderr"`
@@ -48,18 +70,23 @@ type Log struct {
 
 // Store defines storage relevant info
 type Store struct {
-	Driver             string `yaml:"Driver" default:"boltdb"`
-	Host               string `yaml:"Host"`
-	Port               int64  `yaml:"Port"`
-	User               string `yaml:"User"`
-	Password           string `yaml:"Password"`
-	Db                 string `yaml:"Db" default:"dtm"`
-	MaxOpenConns       int64  `yaml:"MaxOpenConns" default:"500"`
-	MaxIdleConns       int64  `yaml:"MaxIdleConns" default:"500"`
-	ConnMaxLifeTime    int64  `yaml:"ConnMaxLifeTime" default:"5"`
-	DataExpire         int64  `yaml:"DataExpire" default:"604800"`        // Trans data will expire in 7 days. only for redis/boltdb.
-	FinishedDataExpire int64  `yaml:"FinishedDataExpire" default:"86400"` // finished Trans data will expire in 1 days. only for redis.
-	RedisPrefix        string `yaml:"RedisPrefix" default:"{a}"`          // Redis storage prefix. store data to only one slot in cluster
+	Driver              string `yaml:"Driver" default:"boltdb"`
+	Host                string `yaml:"Host"`
+	Port                int64  `yaml:"Port"`
+	User                string `yaml:"User"`
+	Password            string `yaml:"Password"`
+	Db                  string `yaml:"Db" default:"dtm"`
+	MaxOpenConns        int64  `yaml:"MaxOpenConns" default:"500"`
+	MaxIdleConns        int64  `yaml:"MaxIdleConns" default:"500"`
+	ConnMaxLifeTime     int64  `yaml:"ConnMaxLifeTime" default:"5"`
+	DataExpire          int64  `yaml:"DataExpire" default:"604800"`        // Trans data will expire in 7 days. only for redis/boltdb/memory.
+	FinishedDataExpire  int64  `yaml:"FinishedDataExpire" default:"86400"` // finished Trans data will expire in 1 days. only for redis/memory.
+	RedisPrefix         string `yaml:"RedisPrefix" default:"{a}"`          // Redis storage prefix. store data to only one slot in cluster
+	BoltPath            string `yaml:"BoltPath" default:"./dtm.bolt"`      // the path of the boltdb file
+	BoltCleanupInterval int64  `yaml:"BoltCleanupInterval" default:"3600"` // interval in seconds to cleanup the expired data of boltdb. 0 means only at startup
+	BoltCompactInterval int64  `yaml:"BoltCompactInterval" default:"0"`    // interval in seconds to compact the boltdb file. 0 means disabled
+	AutoMigrate         int64  `yaml:"AutoMigrate" default:"0"`            // 1 means migrating the schema of mysql/postgres to the latest version at startup
+	Shards              string `yaml:"Shards"`                             // comma separated host:port of the mysql/postgres shards, sharing the other settings. Host and Port are ignored if set
 }
 
 // IsDB checks config driver is mysql or postgres
@@ -67,6 +94,29 @@ func (s *Store) IsDB() bool {
 	return s.Driver == dtmcli.DBTypeMysql || s.Driver == dtmcli.DBTypePostgres
 }
 
+// GetShards returns the config of each shard, nil if the store is not sharded
+func (s *Store) GetShards() ([]Store, error) {
+	if s.Shards == "" {
+		return nil, nil
+	}
+	shards := []Store{}
+	for _, addr := range strings.Split(s.Shards, ",") {
+		host, port, err := net.SplitHostPort(strings.TrimSpace(addr))
+		if err != nil {
+			return nil, fmt.Errorf("bad shard %q: %w", addr, err)
+		}
+		shard := *s
+		shard.Shards = ""
+		shard.Host = host
+		shard.Port, err = strconv.ParseInt(port, 10, 64)
+		if err != nil || host == "" {
+			return nil, fmt.Errorf("bad shard %q", addr)
+		}
+		shards = append(shards, shard)
+	}
+	return shards, nil
+}
+
 // GetDBConf returns db conf info
 func (s *Store) GetDBConf() dtmcli.DBConf {
 	return dtmcli.DBConf{
@@ -82,9 +132,13 @@ func (s *Store) GetDBConf() dtmcli.DBConf {
 // Type is the type for the config of dtm server
 type Type struct {
 	Store                         Store            `yaml:"Store"`
+	Retention                     Retention        `yaml:"Retention"`
 	TransCronInterval             int64            `yaml:"TransCronInterval" default:"3"`
+	TransCronWorkers              int64            `yaml:"TransCronWorkers" default:"1"` // num of goroutines to process the expired trans
 	TimeoutToFail                 int64            `yaml:"TimeoutToFail" default:"35"`
 	RetryInterval                 int64            `yaml:"RetryInterval" default:"10"`
+	RetryPolicy                   string           `yaml:"RetryPolicy" default:"exponential"` // fixed | exponential | exponential_jitter
+	MaxRetryInterval              int64            `yaml:"MaxRetryInterval" default:"3600"`   // the max retry interval of the exponential policies, 0 for unlimited
 	RequestTimeout                int64            `yaml:"RequestTimeout" default:"3"`
 	HTTPPort                      int64            `yaml:"HttpPort" default:"36789"`
 	GrpcPort                      int64            `yaml:"GrpcPort" default:"36790"`
@@ -96,6 +150,10 @@ type Type struct {
 	LogLevel                      string           `yaml:"LogLevel" default:"info"`
 	Log                           Log              `yaml:"Log"`
 	TimeZoneOffset                string           `yaml:"TimeZoneOffset"`
+	WatchPollInterval             int64            `yaml:"WatchPollInterval" default:"3"` // interval in seconds to poll the changes made by other dtm servers for watchers
+	ShutdownTimeout               int64            `yaml:"ShutdownTimeout" default:"30"`  // seconds to wait for the requests and the processing when dtm is shut down
+	HeartbeatInterval             int64            `yaml:"HeartbeatInterval" default:"5"` // seconds between two heartbeats of a dtm server saved in the store
+	HeartbeatTimeout              int64            `yaml:"HeartbeatTimeout" default:"30"` // a dtm server is dead if no heartbeat in this seconds, and its trans are reclaimed
 }
 
 // Config config
@@ -117,3 +175,18 @@ func MustLoadConfig(confFile string) {
 	logger.FatalfIf(err != nil, `config error: '%v'.
 	please visit http://d.dtm.pub to see the config document.`, err)
 }
+
+// LoadStoreConfig loads the store config from env and file in the same way as MustLoadConfig, but Config is not changed.
+// it is used to access a store other than the configured one, such as the target of a migration
+func LoadStoreConfig(confFile string) (*Store, error) {
+	conf := Type{}
+	loadFromEnv("", &conf)
+	cont, err := ioutil.ReadFile(confFile)
+	if err == nil {
+		err = yaml.Unmarshal(cont, &conf)
+	}
+	if err == nil {
+		err = checkConfig(&conf)
+	}
+	return &conf.Store, err
+}
@@ -5,9 +5,18 @@ import (
 	"os"
 	"testing"
 
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
 	"github.com/stretchr/testify/assert"
 )
 
+func init() { // the built-in drivers are registered by the package registry, which imports this package
+	for driver, checker := range map[string]StoreChecker{
+		BoltDb: CheckBoltDb, Memory: nil, Mysql: CheckDB, Postgres: CheckDB, Redis: CheckRedis, Mongo: CheckMongo,
+	} {
+		RegisterStoreDriver(driver, checker)
+	}
+}
+
 func TestLoadFromEnv(t *testing.T) {
 	assert.Equal(t, "MICRO_SERVICE_DRIVER", toUnderscoreUpper("MicroService_Driver"))
 
@@ -20,6 +29,17 @@ func TestLoadFromEnv(t *testing.T) {
 func TestLoadConfig(t *testing.T) {
 	MustLoadConfig("../../conf.sample.yml")
 }
+
+func TestLoadStoreConfig(t *testing.T) {
+	storeConf, err := LoadStoreConfig("../../conf.sample.yml")
+	assert.Nil(t, err)
+	assert.Equal(t, BoltDb, storeConf.Driver)
+	assert.Equal(t, int64(604800), storeConf.DataExpire)
+	assert.Equal(t, "./dtm.bolt", storeConf.BoltPath)
+
+	_, err = LoadStoreConfig("not-exists.yml")
+	assert.Error(t, err)
+}
 func TestCheckConfig(t *testing.T) {
 	conf := Config
 	conf.RetryInterval = 1
@@ -34,9 +54,42 @@ func TestCheckConfig(t *testing.T) {
 	assert.Equal(t, timeoutToFailErr, timeoutToFailExpect)
 
 	conf.TimeoutToFail = 20
+	conf.RetryPolicy = "linear"
+	assert.Equal(t, errors.New("RetryPolicy should be one of fixed, exponential, exponential_jitter, but got: linear"), checkConfig(&conf))
+
+	conf.RetryPolicy = dtmimp.RetryPolicyExponentialJitter
+	conf.MaxRetryInterval = 5
+	assert.Equal(t, errors.New("MaxRetryInterval should not be less than RetryInterval"), checkConfig(&conf))
+
+	conf.MaxRetryInterval = 3600
+	conf.TransCronWorkers = 0
+	assert.Equal(t, errors.New("TransCronWorkers should be greater than 0"), checkConfig(&conf))
+
+	conf.TransCronWorkers = 4
+	conf.WatchPollInterval = 0
+	assert.Equal(t, errors.New("WatchPollInterval should be greater than 0"), checkConfig(&conf))
+
+	conf.WatchPollInterval = 3
+	conf.ShutdownTimeout = 0
+	assert.Equal(t, errors.New("ShutdownTimeout should be greater than 0"), checkConfig(&conf))
+
+	conf.ShutdownTimeout = 30
+	conf.HeartbeatTimeout = 5
+	assert.Equal(t, errors.New("HeartbeatInterval should be greater than 0, and HeartbeatTimeout should be greater than HeartbeatInterval"), checkConfig(&conf))
+
+	conf.HeartbeatTimeout = 30
 	driverErr := checkConfig(&conf)
 	assert.Equal(t, driverErr, nil)
 
+	conf.Retention = Retention{Mode: "move", Age: 10, BatchSize: 10, Interval: 10}
+	assert.Equal(t, errors.New("Retention mode should be archive or delete, but got: move"), checkConfig(&conf))
+
+	conf.Retention = Retention{Mode: RetentionArchive, Age: 10, BatchSize: 0, Interval: 10}
+	assert.Equal(t, errors.New("Retention age, batch size and interval should be greater than 0"), checkConfig(&conf))
+
+	conf.Retention = Retention{Mode: RetentionDelete, Age: 10, BatchSize: 10, Interval: 10}
+	assert.Nil(t, checkConfig(&conf))
+
 	conf.Store = Store{Driver: Mysql}
 	hostErr := checkConfig(&conf)
 	hostExpect := errors.New("Db host not valid ")
@@ -52,12 +105,43 @@ func TestCheckConfig(t *testing.T) {
 	userExpect := errors.New("Db user not valid ")
 	assert.Equal(t, userErr, userExpect)
 
+	conf.Store = Store{Driver: Mysql, Shards: "db1:3306,db2"}
+	assert.Error(t, checkConfig(&conf))
+
+	conf.Store = Store{Driver: Mysql, Shards: "db1:3306, db2:3307", User: "root"}
+	assert.Nil(t, checkConfig(&conf))
+
 	conf.Store = Store{Driver: Redis, Host: "", Port: 8686}
 	assert.Equal(t, errors.New("Redis host not valid"), checkConfig(&conf))
 
 	conf.Store = Store{Driver: Redis, Host: "127.0.0.1", Port: 0}
 	assert.Equal(t, errors.New("Redis port not valid"), checkConfig(&conf))
 
+	conf.Store = Store{Driver: Mongo, Host: "", Port: 27017}
+	assert.Equal(t, errors.New("Mongo host not valid"), checkConfig(&conf))
+
+	conf.Store = Store{Driver: Mongo, Host: "127.0.0.1", Port: 0}
+	assert.Equal(t, errors.New("Mongo port not valid"), checkConfig(&conf))
+
+	conf.Store = Store{Driver: BoltDb, BoltPath: ""}
+	assert.Equal(t, errors.New("BoltDb path not valid"), checkConfig(&conf))
+
+	conf.Store = Store{Driver: Memory}
+	assert.Nil(t, checkConfig(&conf))
+
+	conf.Store = Store{Driver: "custom"}
+	assert.Equal(t, errors.New("unknown store driver: custom"), checkConfig(&conf))
+	RegisterStoreDriver("custom", func(store *Store) error {
+		if store.Host == "" {
+			return errors.New("custom host not valid")
+		}
+		return nil
+	})
+	defer delete(storeCheckers, "custom")
+	assert.Panics(t, func() { RegisterStoreDriver("custom", nil) })
+	assert.Equal(t, errors.New("custom host not valid"), checkConfig(&conf))
+	conf.Store.Host = "127.0.0.1"
+	assert.Nil(t, checkConfig(&conf))
 }
 
 func TestConfig(t *testing.T) {
@@ -82,3 +166,22 @@ func testConfigIntField(fd *int64, val int64, t *testing.T) {
 	assert.NotEqual(t, "", str)
 	*fd = old
 }
+
+func TestGetShards(t *testing.T) {
+	store := Store{Driver: Mysql, Host: "db0", Port: 3306, User: "root", Db: "dtm"}
+	shards, err := store.GetShards()
+	assert.Nil(t, err)
+	assert.Nil(t, shards)
+
+	store.Shards = "db1:3306, db2:3307"
+	shards, err = store.GetShards()
+	assert.Nil(t, err)
+	assert.Equal(t, []Store{
+		{Driver: Mysql, Host: "db1", Port: 3306, User: "root", Db: "dtm"},
+		{Driver: Mysql, Host: "db2", Port: 3307, User: "root", Db: "dtm"},
+	}, shards)
+
+	store.Shards = "db1:port"
+	_, err = store.GetShards()
+	assert.Error(t, err)
+}
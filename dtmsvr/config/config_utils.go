@@ -58,6 +58,15 @@ func toUnderscoreUpper(key string) string {
 	return strings.ToUpper(s2)
 }
 
+// CheckRetryPolicy checks the retry policy of the config or a transaction, empty for the default
+func CheckRetryPolicy(policy string) error {
+	switch policy {
+	case "", dtmimp.RetryPolicyFixed, dtmimp.RetryPolicyExponential, dtmimp.RetryPolicyExponentialJitter:
+		return nil
+	}
+	return fmt.Errorf("RetryPolicy should be one of fixed, exponential, exponential_jitter, but got: %s", policy)
+}
+
 func checkConfig(conf *Type) error {
 	if conf.RetryInterval < 10 {
 		return errors.New("RetryInterval should not be less than 10")
@@ -65,26 +74,112 @@ func checkConfig(conf *Type) error {
 	if conf.TimeoutToFail < conf.RetryInterval {
 		return errors.New("TimeoutToFail should not be less than RetryInterval")
 	}
-	switch conf.Store.Driver {
-	case BoltDb:
-		return nil
-	case Mysql, Postgres:
-		if conf.Store.Host == "" {
-			return errors.New("Db host not valid ")
-		}
-		if conf.Store.Port == 0 {
-			return errors.New("Db port not valid ")
-		}
-		if conf.Store.User == "" {
-			return errors.New("Db user not valid ")
-		}
-	case Redis:
-		if conf.Store.Host == "" {
-			return errors.New("Redis host not valid")
-		}
-		if conf.Store.Port == 0 {
-			return errors.New("Redis port not valid")
+	if err := CheckRetryPolicy(conf.RetryPolicy); err != nil {
+		return err
+	}
+	if conf.MaxRetryInterval != 0 && conf.MaxRetryInterval < conf.RetryInterval {
+		return errors.New("MaxRetryInterval should not be less than RetryInterval")
+	}
+	if conf.TransCronWorkers <= 0 {
+		return errors.New("TransCronWorkers should be greater than 0")
+	}
+	if conf.WatchPollInterval <= 0 {
+		return errors.New("WatchPollInterval should be greater than 0")
+	}
+	if conf.ShutdownTimeout <= 0 {
+		return errors.New("ShutdownTimeout should be greater than 0")
+	}
+	if conf.HeartbeatInterval <= 0 || conf.HeartbeatTimeout <= conf.HeartbeatInterval {
+		return errors.New("HeartbeatInterval should be greater than 0, and HeartbeatTimeout should be greater than HeartbeatInterval")
+	}
+	if err := checkRetention(&conf.Retention); err != nil {
+		return err
+	}
+	checker, ok := storeCheckers[conf.Store.Driver]
+	if !ok {
+		return fmt.Errorf("unknown store driver: %s", conf.Store.Driver)
+	}
+	if checker != nil {
+		return checker(&conf.Store)
+	}
+	return nil
+}
+
+// StoreChecker checks the store config of a driver
+type StoreChecker func(store *Store) error
+
+// storeCheckers is filled by the package registry, where the built-in drivers are registered in the same way as the third-party ones
+var storeCheckers = map[string]StoreChecker{}
+
+// RegisterStoreDriver registers a store driver with the checker of its config, which can be nil.
+// the drivers not registered are rejected by the config check. it panics if the driver is registered twice
+func RegisterStoreDriver(driver string, checker StoreChecker) {
+	if _, dup := storeCheckers[driver]; dup {
+		panic("config: RegisterStoreDriver called twice for driver " + driver)
+	}
+	storeCheckers[driver] = checker
+}
+
+// CheckBoltDb checks the store config of boltdb
+func CheckBoltDb(store *Store) error {
+	if store.BoltPath == "" {
+		return errors.New("BoltDb path not valid")
+	}
+	return nil
+}
+
+// CheckDB checks the store config of mysql and postgres
+func CheckDB(store *Store) error {
+	if store.Shards != "" {
+		_, err := store.GetShards()
+		if err == nil && store.User == "" {
+			err = errors.New("Db user not valid ")
 		}
+		return err
+	}
+	if store.Host == "" {
+		return errors.New("Db host not valid ")
+	}
+	if store.Port == 0 {
+		return errors.New("Db port not valid ")
+	}
+	if store.User == "" {
+		return errors.New("Db user not valid ")
+	}
+	return nil
+}
+
+// CheckRedis checks the store config of redis
+func CheckRedis(store *Store) error {
+	if store.Host == "" {
+		return errors.New("Redis host not valid")
+	}
+	if store.Port == 0 {
+		return errors.New("Redis port not valid")
+	}
+	return nil
+}
+
+// CheckMongo checks the store config of mongo
+func CheckMongo(store *Store) error {
+	if store.Host == "" {
+		return errors.New("Mongo host not valid")
+	}
+	if store.Port == 0 {
+		return errors.New("Mongo port not valid")
+	}
+	return nil
+}
+
+func checkRetention(r *Retention) error {
+	if r.Mode == "" {
+		return nil
+	}
+	if r.Mode != RetentionArchive && r.Mode != RetentionDelete {
+		return fmt.Errorf("Retention mode should be %s or %s, but got: %s", RetentionArchive, RetentionDelete, r.Mode)
+	}
+	if r.Age <= 0 || r.BatchSize <= 0 || r.Interval <= 0 {
+		return errors.New("Retention age, batch size and interval should be greater than 0")
 	}
 	return nil
 }
@@ -7,14 +7,17 @@
 package dtmsvr
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math/rand"
 	"runtime/debug"
+	"sync"
 	"time"
 
 	"github.com/dtm-labs/dtm/client/dtmcli"
 	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
 	"github.com/dtm-labs/logger"
 )
 
@@ -27,35 +30,112 @@ var CronForwardDuration = time.Duration(0)
 // CronTransOnce cron expired trans. use expireIn as expire time
 func CronTransOnce() (gid string) {
 	defer handlePanic(nil)
-	trans := lockOneTrans(CronForwardDuration)
+	trans, err := lockOneTrans(CronForwardDuration)
+	if err != nil {
+		logger.Errorf("cron lock one trans error: %v", err)
+		return
+	}
 	if trans == nil {
 		return
 	}
-	gid = trans.Gid
-	trans.WaitResult = true
-	branches := GetStore().FindBranches(gid)
-	err := trans.Process(branches)
-	dtmimp.PanicIf(err != nil && !errors.Is(err, dtmcli.ErrFailure) && !errors.Is(err, dtmcli.ErrOngoing), err)
-	return
+	processCronTrans(trans)
+	return trans.Gid
 }
 
-// CronExpiredTrans cron expired trans, num == -1 indicate for ever
+// CronExpiredTrans cron expired trans, num == -1 indicate for ever.
+// the expired trans are locked in batches, and processed by a pool of TransCronWorkers goroutines
 func CronExpiredTrans(num int) {
-	for i := 0; i < num || num == -1; i++ {
-		gid := CronTransOnce()
-		if gid == "" && num != 1 {
+	pool := newCronPool(int(conf.TransCronWorkers))
+	for i := 0; (i < num || num == -1) && !isStopping(); i++ {
+		if pool.cronBatch() == 0 && num != 1 {
 			sleepCronTime()
 		}
 	}
+	pool.wg.Wait()
+}
+
+func processCronTrans(trans *TransGlobal) {
+	trans.WaitResult = true
+	branches, err := GetStore().FindBranches(trans.getContext(), trans.Gid)
+	if err == nil {
+		err = trans.Process(branches)
+	}
+	if err != nil && !errors.Is(err, dtmcli.ErrFailure) && !errors.Is(err, dtmcli.ErrOngoing) {
+		logger.Errorf("cron process gid: %s error: %v", trans.Gid, err)
+	}
+}
+
+// cronPool is a pool of the cron workers, a token in idle stands for an idle worker
+type cronPool struct {
+	idle chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newCronPool(workers int) *cronPool {
+	if workers < 1 { // the config may be not checked, such as in the unit tests
+		workers = 1
+	}
+	p := &cronPool{idle: make(chan struct{}, workers)}
+	p.release(workers)
+	return p
+}
+
+// acquire waits for an idle worker, then takes all the idle workers
+func (p *cronPool) acquire() int {
+	<-p.idle
+	for n := 1; ; n++ {
+		select {
+		case <-p.idle:
+		default:
+			return n
+		}
+	}
+}
+
+func (p *cronPool) release(n int) {
+	for i := 0; i < n; i++ {
+		p.idle <- struct{}{}
+	}
+}
+
+// cronBatch locks the expired trans for all the idle workers in one round trip, and processes them in the workers.
+// it returns the num of the locked trans
+func (p *cronPool) cronBatch() int {
+	idle := p.acquire()
+	transList, err := lockTransBatch(CronForwardDuration, idle)
+	if err != nil {
+		logger.Errorf("cron lock trans error: %v", err)
+	}
+	p.release(idle - len(transList))
+	for _, trans := range transList {
+		p.wg.Add(1)
+		go func(trans *TransGlobal) {
+			defer p.wg.Done()
+			defer p.release(1)
+			defer handlePanic(nil)
+			processCronTrans(trans)
+		}(trans)
+	}
+	return len(transList)
+}
+
+func lockOneTrans(expireIn time.Duration) (*TransGlobal, error) {
+	transList, err := lockTransBatch(expireIn, 1)
+	if len(transList) == 0 {
+		return nil, err
+	}
+	return transList[0], err
 }
 
-func lockOneTrans(expireIn time.Duration) *TransGlobal {
-	global := GetStore().LockOneGlobalTrans(expireIn)
-	if global == nil {
-		return nil
+func lockTransBatch(expireIn time.Duration, limit int) (transList []*TransGlobal, err error) {
+	defer handlePanic(&err)
+	ctx := storage.WithLockOwner(context.Background(), serverOwner)
+	globals, err := storage.LockGlobalTransBatch(ctx, GetStore(), expireIn, limit)
+	for _, global := range globals {
+		logger.Infof("cron job return a trans: %s", global.String())
+		transList = append(transList, &TransGlobal{TransGlobalStore: global, Context: context.Background(), eventSource: eventSourceCron})
 	}
-	logger.Infof("cron job return a trans: %s", global.String())
-	return &TransGlobal{TransGlobalStore: *global}
+	return transList, err
 }
 
 func handlePanic(perr *error) {
@@ -71,5 +151,5 @@ func sleepCronTime() {
 	normal := time.Duration((float64(conf.TransCronInterval) - rand.Float64()) * float64(time.Second))
 	interval := dtmimp.If(CronForwardDuration > 0, 1*time.Millisecond, normal).(time.Duration)
 	logger.Debugf("sleeping for %v milli", interval/time.Microsecond)
-	time.Sleep(interval)
+	sleepUnlessStopping(interval)
 }
@@ -27,6 +27,7 @@ var isVersion = flag.Bool("v", false, "Show the version of dtm.")
 var isDebug = flag.Bool("d", false, "Set log level to debug.")
 var isHelp = flag.Bool("h", false, "Show the help information about dtm.")
 var isReset = flag.Bool("r", false, "Reset dtm server data.")
+var isMigrate = flag.Bool("m", false, "Migrate the schema of the mysql/postgres store to the latest version, then exit.")
 var confFile = flag.String("c", "", "Path to the server configuration file.")
 
 // Main is the entry point of dtm server.
@@ -58,7 +59,11 @@ func Main(version *string) (*gin.Engine, *config.Type) {
 	}
 	_, _ = maxprocs.Set(maxprocs.Logger(logger.Infof))
 	registry.WaitStoreUp()
-	app := dtmsvr.StartSvr()       // start dtmsvr api
-	go dtmsvr.CronExpiredTrans(-1) // start dtmsvr cron job
+	logger.FatalIfError(dtmsvr.CheckSchema(*isMigrate || conf.Store.AutoMigrate == 1))
+	if *isMigrate {
+		return nil, nil
+	}
+	app := dtmsvr.StartSvr() // start dtmsvr api
+	dtmsvr.StartCronJobs()   // start dtmsvr cron jobs
 	return app, &config.Config
 }
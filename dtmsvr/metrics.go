@@ -53,6 +53,24 @@ var (
 		Help: "All branches processed by dtm",
 	},
 		[]string{"model", "gid", "branchid", "branchtype", "status"})
+
+	retentionPurgedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dtm_retention_purged_total",
+		Help: "All finished transactions purged by the retention job",
+	},
+		[]string{"mode"})
+
+	retentionBatchTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dtm_retention_batch_total",
+		Help: "All batches executed by the retention job",
+	},
+		[]string{"mode", "status"})
+
+	retentionBatchTime = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "dtm_retention_batch_duration",
+		Help: "The durations of the batches executed by the retention job",
+	},
+		[]string{"mode"})
 )
 
 func setServerInfoMetrics() {
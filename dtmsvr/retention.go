@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package dtmsvr
+
+import (
+	"context"
+	"time"
+
+	"github.com/dtm-labs/dtm/dtmsvr/config"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/logger"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// CronPurgeFinished purges the finished transactions according to conf.Retention, num == -1 indicate for ever
+func CronPurgeFinished(num int) {
+	if conf.Retention.Mode == "" {
+		return
+	}
+	if _, ok := GetStore().(storage.FinishedPurger); !ok {
+		logger.Infof("retention is ignored, because the data of %s are expired by the store", conf.Store.Driver)
+		return
+	}
+	for i := 0; (i < num || num == -1) && !isStopping(); i++ {
+		purged, err := PurgeFinishedOnce()
+		logger.Infof("retention purged %d finished transactions. mode: %s, err: %v", purged, conf.Retention.Mode, err)
+		if num != 1 {
+			sleepUnlessStopping(time.Duration(conf.Retention.Interval) * time.Second)
+		}
+	}
+}
+
+// PurgeFinishedOnce purges the finished transactions older than conf.Retention.Age in batches, until no more can be purged
+// or the shutdown begins
+func PurgeFinishedOnce() (purged int64, err error) {
+	defer handlePanic(&err)
+	purger := GetStore().(storage.FinishedPurger)
+	r := &conf.Retention
+	before := time.Now().Add(-time.Duration(r.Age) * time.Second)
+	for !isStopping() {
+		timer := prometheus.NewTimer(retentionBatchTime.WithLabelValues(r.Mode))
+		n, err := purger.PurgeFinished(context.Background(), before, r.BatchSize, r.Mode == config.RetentionArchive)
+		timer.ObserveDuration()
+		if err != nil {
+			retentionBatchTotal.WithLabelValues(r.Mode, "fail").Inc()
+			return purged, err
+		}
+		retentionBatchTotal.WithLabelValues(r.Mode, "ok").Inc()
+		retentionPurgedTotal.WithLabelValues(r.Mode).Add(float64(n))
+		purged += n
+		if n < r.BatchSize {
+			return purged, nil
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package dtmsvr
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/logger"
+)
+
+// CheckSchema checks the schema version of the store, and migrates the schema to the latest version if migrate is true.
+// an error is returned if the schema is newer than this binary, which is migrated by a newer dtm,
+// or if the schema is older and not migrated, because the queries of this binary will fail on it
+func CheckSchema(migrate bool) error {
+	migrator, ok := GetStore().(storage.SchemaMigrator)
+	if !ok {
+		return nil
+	}
+	ctx := context.Background()
+	current, latest, err := migrator.SchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if current > latest {
+		return fmt.Errorf("the schema version of the store is %d, which is newer than %d supported by dtm %s. please upgrade dtm", current, latest, Version)
+	} else if current == latest {
+		logger.Infof("the schema version of the store is %d", current)
+		return nil
+	} else if !migrate {
+		return fmt.Errorf("the schema version of the store is %d, older than %d required by dtm %s. please run dtm with -m, or set Store.AutoMigrate to 1 to migrate it", current, latest, Version)
+	}
+	logger.Infof("migrating the schema of the store from version %d to %d", current, latest)
+	return migrator.MigrateSchema(ctx)
+}
@@ -0,0 +1,143 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package dtmsvr
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/dtmdriver"
+	"github.com/dtm-labs/logger"
+	"google.golang.org/grpc"
+)
+
+// ServiceUnregister can be implemented by a dtmdriver, so that dtm is unregistered from the registry when it is shut down
+type ServiceUnregister interface {
+	UnregisterService(target string, endpoint string) error
+}
+
+var (
+	httpServer *http.Server
+	grpcServer *grpc.Server
+
+	// stopping is closed when the shutdown begins, then the cron jobs stop polling and the watches return
+	stopping = make(chan struct{})
+	// draining is closed after the processing is finished, then the async branch updaters exit after the queue is flushed
+	draining = make(chan struct{})
+
+	jobs       sync.WaitGroup // the running cron jobs started by StartCronJobs
+	processing sync.WaitGroup // the running processInner
+	updaters   sync.WaitGroup // the running async branch updaters
+)
+
+// StartCronJobs starts the cron jobs in goroutines, which are waited by Shutdown
+func StartCronJobs() {
+	goJob(CronExpiredTrans)  // process the expired trans
+	goJob(CronPurgeFinished) // the retention job if configured
+	goJob(CronHeartbeat)     // save the heartbeat of this node, and reclaim the trans of the dead nodes
+}
+
+// goJob runs job for ever in a goroutine. jobs is added before the goroutine starts, so that it is always waited by Shutdown
+func goJob(job func(num int)) {
+	jobs.Add(1)
+	go func() {
+		defer jobs.Done()
+		job(-1)
+	}()
+}
+
+// isStopping returns true if the shutdown has begun
+func isStopping() bool {
+	select {
+	case <-stopping:
+		return true
+	default:
+		return false
+	}
+}
+
+// sleepUnlessStopping sleeps for d, and returns false if the shutdown begins in the meantime
+func sleepUnlessStopping(d time.Duration) bool {
+	select {
+	case <-stopping:
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+// waitUntil waits for wg until the deadline of ctx, returns false if timeout
+func waitUntil(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// Shutdown stops dtm server in order: stops accepting requests, stops the cron jobs, waits for the processing,
+// flushes the async branch updates, removes this node and closes the store, then unregisters from the registry.
+// the waiting of the requests, the processing and the flushing is limited by timeout
+func Shutdown(timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	close(stopping)
+
+	if httpServer != nil {
+		if err := httpServer.Shutdown(ctx); err != nil {
+			logger.Errorf("shutdown http server error: %v", err)
+		}
+	}
+	if grpcServer != nil {
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			grpcServer.Stop()
+		}
+	}
+	logger.Infof("dtm server stopped accepting requests")
+
+	if !waitUntil(ctx, &jobs) || !waitUntil(ctx, &processing) {
+		logger.Warnf("shutdown timeout, some transactions are still processing, they will be retried by cron")
+	}
+	close(draining)
+	if !waitUntil(ctx, &updaters) {
+		logger.Warnf("shutdown timeout, %d async branch updates are not flushed", len(updateBranchAsyncChan))
+	}
+
+	if registry, ok := GetStore().(storage.NodeRegistry); ok {
+		if err := registry.RemoveNode(context.Background(), serverOwner); err != nil {
+			logger.Errorf("remove node error: %v", err)
+		}
+	}
+	if closer, ok := GetStore().(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			logger.Errorf("close store error: %v", err)
+		}
+	}
+	if unregister, ok := dtmdriver.GetDriver().(ServiceUnregister); ok {
+		if err := unregister.UnregisterService(conf.MicroService.Target, conf.MicroService.EndPoint); err != nil {
+			logger.Errorf("unregister service error: %v", err)
+		}
+	}
+	logger.Infof("dtm server is shut down")
+}
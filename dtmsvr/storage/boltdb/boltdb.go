@@ -7,10 +7,17 @@
 package boltdb
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/dtm-labs/dtm/client/dtmcli"
 	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
 	"github.com/dtm-labs/dtm/dtmsvr/storage"
 	"github.com/dtm-labs/dtm/dtmutil"
@@ -21,29 +28,58 @@ import (
 // Store implements storage.Store, and storage with boltdb
 type Store struct {
 	boltDb *bolt.DB
+	mutex     sync.RWMutex // boltDb is replaced by Compact with mutex locked
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	dataExpire      int64
+	retryInterval   int64
+	path            string
+	cleanupInterval time.Duration
+	compactInterval time.Duration
+}
+
+// Option is the option of NewStore
+type Option func(s *Store)
+
+// WithPath sets the path of the boltdb file, default to ./dtm.bolt
+func WithPath(path string) Option {
+	return func(s *Store) {
+		s.path = path
+	}
+}
+
+// WithCleanupInterval sets the interval to cleanup the expired data.
+// if not set, the expired data is only cleaned up when the store is created
+func WithCleanupInterval(interval time.Duration) Option {
+	return func(s *Store) {
+		s.cleanupInterval = interval
+	}
+}
 
-	dataExpire    int64
-	retryInterval int64
+// WithCompactInterval sets the interval to compact the boltdb file, so that the space of the deleted data is released.
+// if not set, the file is never compacted
+func WithCompactInterval(interval time.Duration) Option {
+	return func(s *Store) {
+		s.compactInterval = interval
+	}
 }
 
 // NewStore will return the boltdb implement
-// TODO: change to options
-func NewStore(dataExpire int64, retryInterval int64) *Store {
+func NewStore(dataExpire int64, retryInterval int64, opts ...Option) *Store {
 	s := &Store{
 		dataExpire:    dataExpire,
 		retryInterval: retryInterval,
+		path:          "./dtm.bolt",
+		closed:        make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
 
-	db, err := bolt.Open("./dtm.bolt", 0666, &bolt.Options{Timeout: 1 * time.Second})
-	dtmimp.E2P(err)
-
-	// NOTE: we must ensure all buckets is exists before we use it
-	err = initializeBuckets(db)
+	db, err := openDB(s.path)
 	dtmimp.E2P(err)
 
-	// TODO:
-	//   1. refactor this code
-	//   2. make cleanup run period, to avoid the file growup when server long-running
 	err = cleanupExpiredData(
 		time.Duration(dataExpire)*time.Second,
 		db,
@@ -51,17 +87,172 @@ func NewStore(dataExpire int64, retryInterval int64) *Store {
 	dtmimp.E2P(err)
 
 	s.boltDb = db
+	if s.cleanupInterval > 0 || s.compactInterval > 0 {
+		go s.maintain()
+	}
 	return s
 }
 
+func openDB(path string) (*bolt.DB, error) {
+	db, err := bolt.Open(path, 0666, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	// NOTE: we must ensure all buckets is exists before we use it
+	err = initializeBuckets(db)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// maintain cleans up the expired data and compacts the file periodically, until the store is closed
+func (s *Store) maintain() {
+	var cleanupC, compactC <-chan time.Time
+	if s.cleanupInterval > 0 {
+		ticker := time.NewTicker(s.cleanupInterval)
+		defer ticker.Stop()
+		cleanupC = ticker.C
+	}
+	if s.compactInterval > 0 {
+		ticker := time.NewTicker(s.compactInterval)
+		defer ticker.Stop()
+		compactC = ticker.C
+	}
+	for {
+		select {
+		case <-cleanupC:
+			err := s.CleanupExpiredData()
+			logger.Debugf("boltdb cleanup expired data. result: %v", err)
+		case <-compactC:
+			err := s.Compact()
+			logger.Infof("boltdb compact %s. result: %v", s.path, err)
+		case <-s.closed:
+			return
+		}
+	}
+}
+
+// CleanupExpiredData removes the transactions finished before dataExpire seconds
+func (s *Store) CleanupExpiredData() error {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return cleanupExpiredData(time.Duration(s.dataExpire)*time.Second, s.boltDb)
+}
+
+// openCompacted opens the compacted file, replaced in tests
+var openCompacted = openDB
+
+// Compact copies the data into a fresh file, and replaces the current file with it.
+// the original file is kept as path.bak until the fresh file is opened, and reopened if any step fails.
+// the operations of the store are blocked during the compaction
+func (s *Store) Compact() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	tmpPath, bakPath := s.path+".compact", s.path+".bak"
+	_ = os.Remove(tmpPath)
+	dst, err := bolt.Open(tmpPath, 0666, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return err
+	}
+	err = bolt.Compact(dst, s.boltDb, 64*1024*1024)
+	if err1 := dst.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	if err := s.boltDb.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return s.reopen(err)
+	}
+	if err := os.Rename(s.path, bakPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return s.reopen(err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		_ = os.Rename(bakPath, s.path)
+		return s.reopen(err)
+	}
+	db, err := openCompacted(s.path)
+	if err != nil {
+		_ = os.Rename(bakPath, s.path)
+		return s.reopen(err)
+	}
+	_ = os.Remove(bakPath)
+	s.boltDb = db
+	return nil
+}
+
+// reopen reopens the original file after the compaction failed by cause
+func (s *Store) reopen(cause error) error {
+	db, err := openDB(s.path)
+	if err != nil {
+		return fmt.Errorf("compact boltdb %s failed: %v, and the reopen failed: %w", s.path, cause, err)
+	}
+	s.boltDb = db
+	return fmt.Errorf("compact boltdb %s failed: %w", s.path, cause)
+}
+
+// Close stops the periodic maintenance and closes the boltdb file. it can be called more than once
+func (s *Store) Close() (err error) {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		err = s.boltDb.Close()
+	})
+	return err
+}
+
+// update runs fn in a writable transaction if ctx is not done. the panics of fn are returned as errors
+func (s *Store) update(ctx context.Context, fn func(t *bolt.Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var err error
+	if perr := dtmimp.CatchP(func() { err = s.boltDb.Update(fn) }); perr != nil {
+		return perr
+	}
+	return err
+}
+
+// view runs fn in a read-only transaction if ctx is not done. the panics of fn are returned as errors
+func (s *Store) view(ctx context.Context, fn func(t *bolt.Tx) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	var err error
+	if perr := dtmimp.CatchP(func() { err = s.boltDb.View(fn) }); perr != nil {
+		return perr
+	}
+	return err
+}
+
 func initializeBuckets(db *bolt.DB) error {
 	return db.Update(func(t *bolt.Tx) error {
+		rebuildScan := t.Bucket(bucketScan) == nil
 		for _, bucket := range allBuckets {
 			_, err := t.CreateBucketIfNotExists(bucket)
 			if err != nil {
 				return err
 			}
 		}
+		if rebuildScan { // the globals saved by the older versions are not indexed for scan
+			return t.Bucket(bucketGlobal).ForEach(func(k, v []byte) error {
+				g := storage.TransGlobalStore{}
+				if err := json.Unmarshal(v, &g); err != nil {
+					return err
+				}
+				return dtmimp.CatchP(func() { tPutScanKeys(t, &g) })
+			})
+		}
 
 		return nil
 	})
@@ -96,13 +287,34 @@ func cleanupExpiredData(expire time.Duration, db *bolt.DB) error {
 			}
 		}
 
+		cleanupStatsWithGids(t, expiredGids)
+		cleanupScanWithGids(t, expiredGids)
 		cleanupGlobalWithGids(t, expiredGids)
 		cleanupBranchWithGids(t, expiredGids)
 		cleanupIndexWithGids(t, expiredGids)
+		cleanupEventWithGids(t, expiredGids)
 		return nil
 	})
 }
 
+// cleanupStatsWithGids decreases the counts of the expired transactions, it should be called before the globals are deleted
+func cleanupStatsWithGids(t *bolt.Tx, gids map[string]struct{}) {
+	for gid := range gids {
+		g := tGetGlobal(t, gid)
+		tIncrStat(t, statCountKey(g), -1)
+	}
+}
+
+// cleanupScanWithGids deletes the scan keys of the expired transactions, it should be called before the globals are deleted
+func cleanupScanWithGids(t *bolt.Tx, gids map[string]struct{}) {
+	if t.Bucket(bucketScan) == nil {
+		return
+	}
+	for gid := range gids {
+		tDelScanKeys(t, tGetGlobal(t, gid))
+	}
+}
+
 func cleanupGlobalWithGids(t *bolt.Tx, gids map[string]struct{}) {
 	bucket := t.Bucket(bucketGlobal)
 	if bucket == nil {
@@ -171,13 +383,81 @@ func cleanupIndexWithGids(t *bolt.Tx, gids map[string]struct{}) {
 	}
 }
 
+func cleanupEventWithGids(t *bolt.Tx, gids map[string]struct{}) {
+	bucket := t.Bucket(bucketEvents)
+	if bucket == nil {
+		return
+	}
+
+	eventKeys := []string{}
+	for gid := range gids {
+		prefix := eventKeyPrefix(gid)
+		cursor := bucket.Cursor()
+		for k, _ := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = cursor.Next() {
+			eventKeys = append(eventKeys, string(k))
+		}
+	}
+
+	logger.Debugf("Start to cleanup %d events", len(eventKeys))
+	for _, key := range eventKeys {
+		dtmimp.E2P(bucket.Delete([]byte(key)))
+	}
+}
+
 var bucketGlobal = []byte("global")
 var bucketBranches = []byte("branches")
 var bucketIndex = []byte("index")
+var bucketEvents = []byte("events")
+var bucketStats = []byte("stats")
+var bucketScan = []byte("scan")
 var allBuckets = [][]byte{
 	bucketBranches,
+	bucketEvents,
 	bucketGlobal,
 	bucketIndex,
+	bucketScan,
+	bucketStats,
+}
+
+// the key prefixes in bucketStats
+var (
+	statPrefixCount      = []byte("count\x00")      // count\x00status\x00trans_type => the number of transactions
+	statPrefixFailing    = []byte("failing\x00")    // failing\x00url => the number of failing branches
+	statPrefixUnfinished = []byte("unfinished\x00") // unfinished\x00create_time\x00gid => gid, the unfinished transactions in the order of creation
+)
+
+// the key prefixes in bucketScan, the global transactions are sorted by the time, then by the gid
+var (
+	scanPrefixCreate = []byte("create\x00") // create\x00create_time\x00gid => gid
+	scanPrefixUpdate = []byte("update\x00") // update\x00update_time\x00gid => gid
+)
+
+// scanTimeLayout formats the time in the scan keys, whose lexical order is the order of the time
+const scanTimeLayout = "20060102150405.000000000"
+
+func scanKey(prefix []byte, t *time.Time, gid string) []byte {
+	tm := time.Time{}
+	if t != nil {
+		tm = *t
+	}
+	return append(append([]byte{}, prefix...), tm.UTC().Format(scanTimeLayout)+"\x00"+gid...)
+}
+
+func statCountKey(g *storage.TransGlobalStore) []byte {
+	return append(append([]byte{}, statPrefixCount...), g.Status+"\x00"+g.TransType...)
+}
+
+func statFailingKey(b *storage.TransBranchStore) []byte {
+	return append(append([]byte{}, statPrefixFailing...), b.URL...)
+}
+
+func statUnfinishedKey(g *storage.TransGlobalStore) []byte {
+	return append(append([]byte{}, statPrefixUnfinished...), fmt.Sprintf("%020d\x00%s", g.CreateTime.Unix(), g.Gid)...)
+}
+
+// eventKeyPrefix returns the key prefix of the events of gid. the separator \x00 keeps the events of gid together
+func eventKeyPrefix(gid string) []byte {
+	return []byte(gid + "\x00")
 }
 
 func tGetGlobal(t *bolt.Tx, gid string) *storage.TransGlobalStore {
@@ -204,11 +484,30 @@ func tGetBranches(t *bolt.Tx, gid string) []storage.TransBranchStore {
 	return branches
 }
 func tPutGlobal(t *bolt.Tx, global *storage.TransGlobalStore) {
+	tDelScanKeys(t, tGetGlobal(t, global.Gid))
+	tPutScanKeys(t, global)
 	bs := dtmimp.MustMarshal(global)
 	err := t.Bucket(bucketGlobal).Put([]byte(global.Gid), bs)
 	dtmimp.E2P(err)
 }
 
+// tPutScanKeys indexes the global for ScanTransGlobalStores
+func tPutScanKeys(t *bolt.Tx, global *storage.TransGlobalStore) {
+	bucket := t.Bucket(bucketScan)
+	dtmimp.E2P(bucket.Put(scanKey(scanPrefixCreate, global.CreateTime, global.Gid), []byte(global.Gid)))
+	dtmimp.E2P(bucket.Put(scanKey(scanPrefixUpdate, global.UpdateTime, global.Gid), []byte(global.Gid)))
+}
+
+// tDelScanKeys deletes the index of the global, nothing is done if global is nil
+func tDelScanKeys(t *bolt.Tx, global *storage.TransGlobalStore) {
+	if global == nil {
+		return
+	}
+	bucket := t.Bucket(bucketScan)
+	dtmimp.E2P(bucket.Delete(scanKey(scanPrefixCreate, global.CreateTime, global.Gid)))
+	dtmimp.E2P(bucket.Delete(scanKey(scanPrefixUpdate, global.UpdateTime, global.Gid)))
+}
+
 func tPutBranches(t *bolt.Tx, branches []storage.TransBranchStore, start int64) {
 	err := tPutBranches2(t, branches, start)
 	dtmimp.E2P(err)
@@ -227,6 +526,12 @@ func tPutBranches2(t *bolt.Tx, branches []storage.TransBranchStore, start int64)
 	}
 	for i, b := range branches {
 		k := b.Gid + fmt.Sprintf("%03d", i+int(start))
+		if old := t.Bucket(bucketBranches).Get([]byte(k)); old != nil {
+			ob := storage.TransBranchStore{}
+			dtmimp.MustUnmarshal(old, &ob)
+			tIncrFailing(t, &ob, -1)
+		}
+		tIncrFailing(t, &b, 1)
 		v := dtmimp.MustMarshalString(b)
 		err := t.Bucket(bucketBranches).Put([]byte(k), []byte(v))
 		dtmimp.E2P(err)
@@ -234,6 +539,42 @@ func tPutBranches2(t *bolt.Tx, branches []storage.TransBranchStore, start int64)
 	return nil
 }
 
+// tIncrStat adds delta to the count of key, the key is deleted if the count is not positive
+func tIncrStat(t *bolt.Tx, key []byte, delta int64) {
+	bucket := t.Bucket(bucketStats)
+	count := delta
+	if v := bucket.Get(key); v != nil {
+		count += int64(dtmimp.MustAtoi(string(v)))
+	}
+	if count <= 0 {
+		dtmimp.E2P(bucket.Delete(key))
+	} else {
+		dtmimp.E2P(bucket.Put(key, []byte(strconv.FormatInt(count, 10))))
+	}
+}
+
+// tIncrFailing updates the number of failing branches if b is failing
+func tIncrFailing(t *bolt.Tx, b *storage.TransBranchStore, delta int64) {
+	if storage.IsFailingBranch(b) {
+		tIncrStat(t, statFailingKey(b), delta)
+	}
+}
+
+// tChangeStats updates the stats when the status of global is changed from old
+func tChangeStats(t *bolt.Tx, old *storage.TransGlobalStore, global *storage.TransGlobalStore) {
+	if old.Status == global.Status {
+		return
+	}
+	tIncrStat(t, statCountKey(old), -1)
+	tIncrStat(t, statCountKey(global), 1)
+	if !old.IsFinished() && global.IsFinished() {
+		dtmimp.E2P(t.Bucket(bucketStats).Delete(statUnfinishedKey(old)))
+		for _, b := range tGetBranches(t, global.Gid) {
+			tIncrFailing(t, &b, -1)
+		}
+	}
+}
+
 func tDelIndex(t *bolt.Tx, unix int64, gid string) {
 	k := fmt.Sprintf("%d-%s", unix, gid)
 	err := t.Bucket(bucketIndex).Delete([]byte(k))
@@ -247,87 +588,161 @@ func tPutIndex(t *bolt.Tx, unix int64, gid string) {
 }
 
 // Ping execs ping cmd to boltdb
-func (s *Store) Ping() error {
+func (s *Store) Ping(ctx context.Context) error {
 	return nil
 }
 
 // PopulateData populates data to boltdb
-func (s *Store) PopulateData(skipDrop bool) {
+func (s *Store) PopulateData(ctx context.Context, skipDrop bool) error {
 	if !skipDrop {
-		err := s.boltDb.Update(func(t *bolt.Tx) error {
+		err := s.update(ctx, func(t *bolt.Tx) error {
 			dtmimp.E2P(t.DeleteBucket(bucketIndex))
 			dtmimp.E2P(t.DeleteBucket(bucketBranches))
 			dtmimp.E2P(t.DeleteBucket(bucketGlobal))
+			dtmimp.E2P(t.DeleteBucket(bucketEvents))
+			dtmimp.E2P(t.DeleteBucket(bucketStats))
+			dtmimp.E2P(t.DeleteBucket(bucketScan))
 			_, err := t.CreateBucket(bucketIndex)
 			dtmimp.E2P(err)
 			_, err = t.CreateBucket(bucketBranches)
 			dtmimp.E2P(err)
 			_, err = t.CreateBucket(bucketGlobal)
 			dtmimp.E2P(err)
+			_, err = t.CreateBucket(bucketEvents)
+			dtmimp.E2P(err)
+			_, err = t.CreateBucket(bucketStats)
+			dtmimp.E2P(err)
+			_, err = t.CreateBucket(bucketScan)
+			dtmimp.E2P(err)
 
 			return nil
 		})
-		dtmimp.E2P(err)
+		if err != nil {
+			return err
+		}
 		logger.Infof("Reset all data for boltdb")
 	}
+	return nil
 }
 
 // FindTransGlobalStore finds GlobalTrans data by gid
-func (s *Store) FindTransGlobalStore(gid string) (trans *storage.TransGlobalStore) {
-	err := s.boltDb.View(func(t *bolt.Tx) error {
+func (s *Store) FindTransGlobalStore(ctx context.Context, gid string) (trans *storage.TransGlobalStore, err error) {
+	err = s.view(ctx, func(t *bolt.Tx) error {
 		trans = tGetGlobal(t, gid)
+		if trans == nil {
+			return storage.ErrNotFound
+		}
 		return nil
 	})
-	dtmimp.E2P(err)
 	return
 }
 
 // ScanTransGlobalStores lists GlobalTrans data
-func (s *Store) ScanTransGlobalStores(position *string, limit int64) []storage.TransGlobalStore {
+// the globals are read in the order of the keys in bucketScan, starting at the position, until the page is full
+func (s *Store) ScanTransGlobalStores(ctx context.Context, position *string, limit int64, condition storage.TransGlobalScanCondition) ([]storage.TransGlobalStore, error) {
 	globals := []storage.TransGlobalStore{}
-	err := s.boltDb.View(func(t *bolt.Tx) error {
-		cursor := t.Bucket(bucketGlobal).Cursor()
-		for k, v := cursor.Seek([]byte(*position)); k != nil; k, v = cursor.Next() {
-			if string(k) == *position {
-				continue
+	err := s.view(ctx, func(t *bolt.Tx) error {
+		prefix := scanPrefixCreate
+		if condition.GetSortBy() == storage.SortByUpdateTime {
+			prefix = scanPrefixUpdate
+		}
+		start := prefix
+		if *position != "" {
+			posTime, posGid, err := storage.DecodePosition(*position)
+			if err != nil {
+				return err
 			}
-			g := storage.TransGlobalStore{}
-			dtmimp.MustUnmarshal(v, &g)
-			globals = append(globals, g)
-			if len(globals) == int(limit) {
-				break
+			start = scanKey(prefix, &posTime, posGid)
+		}
+		cursor := t.Bucket(bucketScan).Cursor()
+		next := cursor.Next
+		k, v := cursor.Seek(start)
+		if condition.SortAsc {
+			if *position != "" && bytes.Equal(k, start) {
+				k, v = cursor.Next()
+			}
+		} else {
+			next = cursor.Prev
+			if *position == "" { // seek the end of the prefix
+				k, v = cursor.Seek(append(prefix[:len(prefix)-1:len(prefix)-1], prefix[len(prefix)-1]+1))
+			}
+			if k == nil {
+				k, v = cursor.Last()
+			} else {
+				k, v = cursor.Prev()
+			}
+		}
+		now := time.Now()
+		for ; k != nil && bytes.HasPrefix(k, prefix) && int64(len(globals)) < limit; k, v = next() {
+			if g := tGetGlobal(t, string(v)); g != nil && condition.Match(g, now) {
+				globals = append(globals, *g)
 			}
 		}
 		return nil
 	})
-	dtmimp.E2P(err)
-	if len(globals) < int(limit) {
-		*position = ""
-	} else {
-		*position = globals[len(globals)-1].Gid
+	if err != nil {
+		return nil, err
 	}
-	return globals
+	condition.UpdatePosition(globals, position, limit)
+	return globals, nil
 }
 
 // FindBranches finds Branch data by gid
-func (s *Store) FindBranches(gid string) []storage.TransBranchStore {
-	var branches []storage.TransBranchStore
-	err := s.boltDb.View(func(t *bolt.Tx) error {
+func (s *Store) FindBranches(ctx context.Context, gid string) (branches []storage.TransBranchStore, err error) {
+	err = s.view(ctx, func(t *bolt.Tx) error {
 		branches = tGetBranches(t, gid)
 		return nil
 	})
-	dtmimp.E2P(err)
-	return branches
+	return
 }
 
-// UpdateBranches update branches info
-func (s *Store) UpdateBranches(branches []storage.TransBranchStore, updates []string) (int, error) {
-	return 0, nil // not implemented
+// UpdateBranches updates the columns of the branches matched by branch_id and op, the unmatched branches are appended
+func (s *Store) UpdateBranches(ctx context.Context, branches []storage.TransBranchStore, updates []string) (int, error) {
+	affected := 0
+	now := time.Now()
+	err := s.update(ctx, func(t *bolt.Tx) error {
+		affected = 0
+		for _, b := range branches {
+			g := tGetGlobal(t, b.Gid)
+			if g == nil {
+				continue
+			}
+			b.UpdateTime = &now
+			olds := tGetBranches(t, b.Gid)
+			pos := len(olds)
+			nb := b
+			for i := range olds {
+				if olds[i].BranchID == b.BranchID && olds[i].Op == b.Op {
+					pos = i
+					nb = olds[i]
+					if err := nb.UpdateColumns(&b, updates); err != nil {
+						return err
+					}
+					break
+				}
+			}
+			// the failing urls are counted only for the unfinished global trans, see tChangeStats
+			if !g.IsFinished() {
+				if pos < len(olds) {
+					tIncrFailing(t, &olds[pos], -1)
+				}
+				tIncrFailing(t, &nb, 1)
+			}
+			k := b.Gid + fmt.Sprintf("%03d", pos)
+			dtmimp.E2P(t.Bucket(bucketBranches).Put([]byte(k), dtmimp.MustMarshal(nb)))
+			affected++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return affected, nil
 }
 
 // LockGlobalSaveBranches creates branches
-func (s *Store) LockGlobalSaveBranches(gid string, status string, branches []storage.TransBranchStore, branchStart int) {
-	err := s.boltDb.Update(func(t *bolt.Tx) error {
+func (s *Store) LockGlobalSaveBranches(ctx context.Context, gid string, status string, branches []storage.TransBranchStore, branchStart int) error {
+	return s.update(ctx, func(t *bolt.Tx) error {
 		g := tGetGlobal(t, gid)
 		if g == nil {
 			return storage.ErrNotFound
@@ -337,28 +752,38 @@ func (s *Store) LockGlobalSaveBranches(gid string, status string, branches []sto
 		}
 		return tPutBranches2(t, branches, int64(branchStart))
 	})
-	dtmimp.E2P(err)
 }
 
 // MaySaveNewTrans creates a new trans
-func (s *Store) MaySaveNewTrans(global *storage.TransGlobalStore, branches []storage.TransBranchStore) error {
-	return s.boltDb.Update(func(t *bolt.Tx) error {
+func (s *Store) MaySaveNewTrans(ctx context.Context, global *storage.TransGlobalStore, branches []storage.TransBranchStore) error {
+	return s.update(ctx, func(t *bolt.Tx) error {
 		g := tGetGlobal(t, global.Gid)
 		if g != nil {
 			return storage.ErrUniqueConflict
 		}
 		tPutGlobal(t, global)
-		tPutIndex(t, global.NextCronTime.Unix(), global.Gid)
+		// a finished trans may be saved, such as by migration
+		if !global.IsFinished() || global.NotifyStatus == dtmcli.StatusPrepared {
+			tPutIndex(t, global.NextCronTime.Unix(), global.Gid)
+		}
 		tPutBranches(t, branches, 0)
+		tIncrStat(t, statCountKey(global), 1)
+		if !global.IsFinished() {
+			dtmimp.E2P(t.Bucket(bucketStats).Put(statUnfinishedKey(global), []byte(global.Gid)))
+		} else {
+			for _, b := range branches {
+				tIncrFailing(t, &b, -1)
+			}
+		}
 		return nil
 	})
 }
 
 // ChangeGlobalStatus changes global trans status
-func (s *Store) ChangeGlobalStatus(global *storage.TransGlobalStore, newStatus string, updates []string, finished bool) {
+func (s *Store) ChangeGlobalStatus(ctx context.Context, global *storage.TransGlobalStore, newStatus string, updates []string, finished bool) error {
 	old := global.Status
 	global.Status = newStatus
-	err := s.boltDb.Update(func(t *bolt.Tx) error {
+	return s.update(ctx, func(t *bolt.Tx) error {
 		g := tGetGlobal(t, global.Gid)
 		if g == nil || g.Status != old {
 			return storage.ErrNotFound
@@ -366,19 +791,19 @@ func (s *Store) ChangeGlobalStatus(global *storage.TransGlobalStore, newStatus s
 		if finished {
 			tDelIndex(t, g.NextCronTime.Unix(), g.Gid)
 		}
+		tChangeStats(t, g, global)
 		tPutGlobal(t, global)
 		return nil
 	})
-	dtmimp.E2P(err)
 }
 
 // TouchCronTime updates cronTime
-func (s *Store) TouchCronTime(global *storage.TransGlobalStore, nextCronInterval int64, nextCronTime *time.Time) {
+func (s *Store) TouchCronTime(ctx context.Context, global *storage.TransGlobalStore, nextCronInterval int64, nextCronTime *time.Time) error {
 	oldUnix := global.NextCronTime.Unix()
 	global.UpdateTime = dtmutil.GetNextTime(0)
 	global.NextCronTime = nextCronTime
 	global.NextCronInterval = nextCronInterval
-	err := s.boltDb.Update(func(t *bolt.Tx) error {
+	return s.update(ctx, func(t *bolt.Tx) error {
 		g := tGetGlobal(t, global.Gid)
 		if g == nil || g.Gid != global.Gid {
 			return storage.ErrNotFound
@@ -388,44 +813,58 @@ func (s *Store) TouchCronTime(global *storage.TransGlobalStore, nextCronInterval
 		tPutIndex(t, global.NextCronTime.Unix(), global.Gid)
 		return nil
 	})
-	dtmimp.E2P(err)
 }
 
 // LockOneGlobalTrans finds GlobalTrans
-func (s *Store) LockOneGlobalTrans(expireIn time.Duration) *storage.TransGlobalStore {
-	var trans *storage.TransGlobalStore
+func (s *Store) LockOneGlobalTrans(ctx context.Context, expireIn time.Duration) (*storage.TransGlobalStore, error) {
+	globals, err := s.LockGlobalTransBatch(ctx, expireIn, 1)
+	if len(globals) == 0 {
+		return nil, err
+	}
+	return &globals[0], err
+}
+
+// LockGlobalTransBatch locks at most limit global trans in one transaction.
+// the index entries of the finished trans are removed on the way
+func (s *Store) LockGlobalTransBatch(ctx context.Context, expireIn time.Duration, limit int) ([]storage.TransGlobalStore, error) {
+	globals := []storage.TransGlobalStore{}
 	min := fmt.Sprintf("%d", time.Now().Add(expireIn).Unix())
-	err := s.boltDb.Update(func(t *bolt.Tx) error {
+	err := s.update(ctx, func(t *bolt.Tx) error {
+		globals = globals[:0]
 		cursor := t.Bucket(bucketIndex).Cursor()
 		toDelete := [][]byte{}
-		for k, v := cursor.First(); k != nil && string(k) <= min && (trans == nil || trans.IsFinished()); k, v = cursor.Next() {
-			trans = tGetGlobal(t, string(v))
+		seen := map[string]bool{}
+		for k, v := cursor.First(); k != nil && string(k) <= min && len(globals) < limit; k, v = cursor.Next() {
 			toDelete = append(toDelete, k)
+			trans := tGetGlobal(t, string(v))
+			if trans != nil && !seen[trans.Gid] && (!trans.IsFinished() || trans.IsNotifyPending()) {
+				seen[trans.Gid] = true
+				globals = append(globals, *trans)
+			}
 		}
 		for _, k := range toDelete {
 			err := t.Bucket(bucketIndex).Delete(k)
 			dtmimp.E2P(err)
 		}
-		if trans != nil && !trans.IsFinished() {
-			next := time.Now().Add(time.Duration(s.retryInterval) * time.Second)
-			trans.NextCronTime = &next
-			tPutGlobal(t, trans)
+		next := time.Now().Add(time.Duration(s.retryInterval) * time.Second)
+		for i := range globals {
+			globals[i].NextCronTime = &next
+			tPutGlobal(t, &globals[i])
 			// this put should be after delete, because the data may be the same
-			tPutIndex(t, next.Unix(), trans.Gid)
+			tPutIndex(t, next.Unix(), globals[i].Gid)
 		}
 		return nil
 	})
-	dtmimp.E2P(err)
-	return trans
+	return globals, err
 }
 
 // ResetCronTime reset nextCronTime
 // unfinished transactions need to be retried as soon as possible after business downtime is recovered
-func (s *Store) ResetCronTime(after time.Duration, limit int64) (succeedCount int64, hasRemaining bool, err error) {
+func (s *Store) ResetCronTime(ctx context.Context, after time.Duration, limit int64) (succeedCount int64, hasRemaining bool, err error) {
 	next := time.Now()
 	var trans *storage.TransGlobalStore
 	min := fmt.Sprintf("%d", time.Now().Add(after).Unix())
-	err = s.boltDb.Update(func(t *bolt.Tx) error {
+	err = s.update(ctx, func(t *bolt.Tx) error {
 		cursor := t.Bucket(bucketIndex).Cursor()
 		succeedCount = 0
 		for k, v := cursor.Seek([]byte(min)); k != nil && succeedCount <= limit; k, v = cursor.Next() {
@@ -447,3 +886,70 @@ func (s *Store) ResetCronTime(after time.Duration, limit int64) (succeedCount in
 	})
 	return
 }
+
+// SaveTransEvents saves the status transitions
+func (s *Store) SaveTransEvents(ctx context.Context, events []storage.TransEventStore) error {
+	return s.update(ctx, func(t *bolt.Tx) error {
+		bucket := t.Bucket(bucketEvents)
+		for _, e := range events {
+			seq, err := bucket.NextSequence()
+			if err != nil {
+				return err
+			}
+			e.ID = seq
+			k := append(eventKeyPrefix(e.Gid), []byte(fmt.Sprintf("%020d", seq))...)
+			if err := bucket.Put(k, dtmimp.MustMarshal(e)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindTransEvents finds the status transitions by gid, in the order of occurrence
+func (s *Store) FindTransEvents(ctx context.Context, gid string) ([]storage.TransEventStore, error) {
+	events := []storage.TransEventStore{}
+	err := s.view(ctx, func(t *bolt.Tx) error {
+		prefix := eventKeyPrefix(gid)
+		cursor := t.Bucket(bucketEvents).Cursor()
+		for k, v := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+			e := storage.TransEventStore{}
+			dtmimp.MustUnmarshal(v, &e)
+			events = append(events, e)
+		}
+		return nil
+	})
+	return events, err
+}
+
+// GetTransStats gets the statistics maintained in the stats bucket
+func (s *Store) GetTransStats(ctx context.Context, topURLs int) (*storage.TransStats, error) {
+	stats := &storage.TransStats{Counts: []storage.TransCount{}}
+	failing := map[string]int64{}
+	now := fmt.Sprintf("%d", time.Now().Unix())
+	err := s.view(ctx, func(t *bolt.Tx) error {
+		cursor := t.Bucket(bucketStats).Cursor()
+		for k, v := cursor.Seek(statPrefixCount); k != nil && bytes.HasPrefix(k, statPrefixCount); k, v = cursor.Next() {
+			ks := strings.SplitN(string(k[len(statPrefixCount):]), "\x00", 2)
+			stats.Counts = append(stats.Counts, storage.TransCount{Status: ks[0], TransType: ks[1], Count: int64(dtmimp.MustAtoi(string(v)))})
+		}
+		for k, v := cursor.Seek(statPrefixFailing); k != nil && bytes.HasPrefix(k, statPrefixFailing); k, v = cursor.Next() {
+			failing[string(k[len(statPrefixFailing):])] = int64(dtmimp.MustAtoi(string(v)))
+		}
+		if k, v := cursor.Seek(statPrefixUnfinished); k != nil && bytes.HasPrefix(k, statPrefixUnfinished) {
+			if g := tGetGlobal(t, string(v)); g != nil {
+				stats.OldestUnfinishedTime = g.CreateTime
+			}
+		}
+		index := t.Bucket(bucketIndex).Cursor()
+		for k, _ := index.First(); k != nil && string(k) < now; k, _ = index.Next() {
+			stats.CronLagCount++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	stats.FailingURLs = storage.TopURLCounts(failing, topURLs)
+	return stats, nil
+}
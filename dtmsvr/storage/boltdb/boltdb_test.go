@@ -7,7 +7,12 @@
 package boltdb
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
 	"path"
+	"strings"
 	"testing"
 	"time"
 
@@ -16,8 +21,23 @@ import (
 
 	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
 	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/dtm/dtmsvr/storage/storagetest"
 )
 
+var ctx = context.Background()
+
+func failingURLs(s *Store) []storage.URLCount {
+	stats, err := s.GetTransStats(ctx, 10)
+	dtmimp.E2P(err)
+	return stats.FailingURLs
+}
+
+func mustFindBranches(s *Store, gid string) []storage.TransBranchStore {
+	branches, err := s.FindBranches(ctx, gid)
+	dtmimp.E2P(err)
+	return branches
+}
+
 func TestInitializeBuckets(t *testing.T) {
 	t.Run("normal test", func(t *testing.T) {
 		g := NewWithT(t)
@@ -336,3 +356,237 @@ func TestCleanupIndexWithGids(t *testing.T) {
 		g.Expect(actualKeys).To(Equal([]string{"3-gid2", "a", "z"}))
 	})
 }
+
+func TestTransEvents(t *testing.T) {
+	g := NewWithT(t)
+	db, err := bolt.Open(path.Join(t.TempDir(), "./test.bolt"), 0666, &bolt.Options{Timeout: 1 * time.Second})
+	g.Expect(err).ToNot(HaveOccurred())
+	defer db.Close()
+	g.Expect(initializeBuckets(db)).ToNot(HaveOccurred())
+	s := &Store{boltDb: db}
+
+	err = s.SaveTransEvents(ctx, []storage.TransEventStore{
+		{Gid: "gid", NewStatus: "submitted"},
+		{Gid: "gid0", NewStatus: "submitted"},
+		{Gid: "gid", BranchID: "01", Op: "action", OldStatus: "prepared", NewStatus: "succeed"},
+		{Gid: "gid", OldStatus: "submitted", NewStatus: "succeed"},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	events, err := s.FindTransEvents(ctx, "gid")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(events).To(HaveLen(3))
+	g.Expect(events[0].NewStatus).To(Equal("submitted"))
+	g.Expect(events[1].BranchID).To(Equal("01"))
+	g.Expect(events[2].OldStatus).To(Equal("submitted"))
+	g.Expect(s.FindTransEvents(ctx, "gid0")).To(HaveLen(1))
+	g.Expect(s.FindTransEvents(ctx, "gi")).To(HaveLen(0))
+
+	err = db.Update(func(t *bolt.Tx) error {
+		cleanupEventWithGids(t, map[string]struct{}{"gid": {}})
+		return nil
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(s.FindTransEvents(ctx, "gid")).To(HaveLen(0))
+	g.Expect(s.FindTransEvents(ctx, "gid0")).To(HaveLen(1))
+}
+
+func TestTransStats(t *testing.T) {
+	g := NewWithT(t)
+	db, err := bolt.Open(path.Join(t.TempDir(), "./test.bolt"), 0666, &bolt.Options{Timeout: 1 * time.Second})
+	g.Expect(err).ToNot(HaveOccurred())
+	defer db.Close()
+	g.Expect(initializeBuckets(db)).ToNot(HaveOccurred())
+	s := &Store{boltDb: db}
+
+	past := time.Now().Add(-time.Minute)
+	future := time.Now().Add(time.Minute)
+	for i, next := range []*time.Time{&past, &future} {
+		global := &storage.TransGlobalStore{Gid: fmt.Sprintf("gid%d", i), TransType: "saga", Status: "submitted", NextCronTime: next}
+		global.CreateTime = next
+		branches := []storage.TransBranchStore{{Gid: global.Gid, BranchID: "01", Op: "action", URL: "url1", Status: "prepared"}}
+		g.Expect(s.MaySaveNewTrans(ctx, global, branches)).ToNot(HaveOccurred())
+		branches[0].LastError = "ongoing"
+		g.Expect(s.LockGlobalSaveBranches(ctx, global.Gid, "submitted", branches, 0)).ToNot(HaveOccurred())
+	}
+	stats, err := s.GetTransStats(ctx, 10)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(stats.Counts).To(Equal([]storage.TransCount{{Status: "submitted", TransType: "saga", Count: 2}}))
+	g.Expect(stats.OldestUnfinishedTime.Unix()).To(Equal(past.Unix()))
+	g.Expect(stats.CronLagCount).To(Equal(int64(1)))
+	g.Expect(stats.FailingURLs).To(Equal([]storage.URLCount{{URL: "url1", Count: 2}}))
+
+	global, err := s.FindTransGlobalStore(ctx, "gid0")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(s.ChangeGlobalStatus(ctx, global, "succeed", []string{}, true)).ToNot(HaveOccurred())
+	stats, err = s.GetTransStats(ctx, 10)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(stats.Counts).To(HaveLen(2))
+	g.Expect(stats.OldestUnfinishedTime.Unix()).To(Equal(future.Unix()))
+	g.Expect(stats.CronLagCount).To(Equal(int64(0)))
+	g.Expect(stats.FailingURLs).To(Equal([]storage.URLCount{{URL: "url1", Count: 1}}))
+}
+
+func TestPeriodicCleanup(t *testing.T) {
+	g := NewWithT(t)
+	s := NewStore(1, 10, WithPath(path.Join(t.TempDir(), "./test.bolt")), WithCleanupInterval(100*time.Millisecond))
+	defer s.Close()
+
+	finished := time.Now().Add(-2 * time.Second)
+	global := &storage.TransGlobalStore{Gid: "gid", TransType: "saga", Status: "succeed", FinishTime: &finished}
+	g.Expect(s.MaySaveNewTrans(ctx, global, []storage.TransBranchStore{})).ToNot(HaveOccurred())
+	g.Expect(s.FindTransGlobalStore(ctx, "gid")).ToNot(BeNil())
+
+	g.Eventually(func() error {
+		_, err := s.FindTransGlobalStore(ctx, "gid")
+		return err
+	}, time.Second, 50*time.Millisecond).Should(Equal(storage.ErrNotFound))
+}
+
+func TestCompact(t *testing.T) {
+	g := NewWithT(t)
+	file := path.Join(t.TempDir(), "./test.bolt")
+	s := NewStore(3600, 10, WithPath(file))
+	defer s.Close()
+
+	finished := time.Now().Add(-2 * time.Hour)
+	for i := 0; i < 200; i++ {
+		global := &storage.TransGlobalStore{Gid: fmt.Sprintf("gid%d", i), TransType: "saga", Status: "succeed",
+			FinishTime: &finished, CustomData: strings.Repeat("x", 4096)}
+		g.Expect(s.MaySaveNewTrans(ctx, global, []storage.TransBranchStore{})).ToNot(HaveOccurred())
+	}
+	now := time.Now()
+	global := &storage.TransGlobalStore{Gid: "kept", TransType: "saga", Status: "submitted", NextCronTime: &now}
+	global.CreateTime = &now
+	g.Expect(s.MaySaveNewTrans(ctx, global, []storage.TransBranchStore{{Gid: "kept", BranchID: "01", Op: "action"}})).ToNot(HaveOccurred())
+
+	g.Expect(s.CleanupExpiredData()).ToNot(HaveOccurred())
+	before, err := os.Stat(file)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(s.Compact()).ToNot(HaveOccurred())
+	after, err := os.Stat(file)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(after.Size()).To(BeNumerically("<", before.Size()))
+
+	_, err = s.FindTransGlobalStore(ctx, "gid0")
+	g.Expect(err).To(Equal(storage.ErrNotFound))
+	g.Expect(s.FindTransGlobalStore(ctx, "kept")).ToNot(BeNil())
+	g.Expect(s.FindBranches(ctx, "kept")).To(HaveLen(1))
+	global.Gid = "new"
+	g.Expect(s.MaySaveNewTrans(ctx, global, []storage.TransBranchStore{})).ToNot(HaveOccurred())
+	g.Expect(s.FindTransGlobalStore(ctx, "new")).ToNot(BeNil())
+}
+
+func TestCompactFailed(t *testing.T) {
+	g := NewWithT(t)
+	file := path.Join(t.TempDir(), "./test.bolt")
+	s := NewStore(3600, 10, WithPath(file))
+	defer s.Close()
+	now := time.Now()
+	global := &storage.TransGlobalStore{Gid: "kept", TransType: "saga", Status: "submitted", NextCronTime: &now}
+	global.CreateTime = &now
+	g.Expect(s.MaySaveNewTrans(ctx, global, []storage.TransBranchStore{})).ToNot(HaveOccurred())
+
+	openCompacted = func(path string) (*bolt.DB, error) {
+		return nil, errors.New("open failed")
+	}
+	defer func() { openCompacted = openDB }()
+	g.Expect(s.Compact()).To(MatchError(ContainSubstring("open failed")))
+
+	g.Expect(s.FindTransGlobalStore(ctx, "kept")).ToNot(BeNil()) // the original file is reopened
+	global.Gid = "new"
+	g.Expect(s.MaySaveNewTrans(ctx, global, []storage.TransBranchStore{})).ToNot(HaveOccurred())
+	_, err := os.Stat(file + ".bak")
+	g.Expect(os.IsNotExist(err)).To(BeTrue())
+}
+
+func TestCloseTwice(t *testing.T) {
+	g := NewWithT(t)
+	s := NewStore(3600, 10, WithPath(path.Join(t.TempDir(), "./test.bolt")), WithCleanupInterval(time.Hour))
+	g.Expect(s.Close()).ToNot(HaveOccurred())
+	g.Expect(s.Close()).ToNot(HaveOccurred())
+}
+
+func TestScanRebuilt(t *testing.T) {
+	g := NewWithT(t)
+	db, err := bolt.Open(path.Join(t.TempDir(), "./test.bolt"), 0666, &bolt.Options{Timeout: 1 * time.Second})
+	g.Expect(err).ToNot(HaveOccurred())
+	defer db.Close()
+	g.Expect(initializeBuckets(db)).ToNot(HaveOccurred())
+	s := &Store{boltDb: db}
+	now := time.Now()
+	for _, gid := range []string{"gid1", "gid2"} {
+		global := &storage.TransGlobalStore{Gid: gid, TransType: "saga", Status: "submitted", NextCronTime: &now}
+		global.CreateTime = &now
+		global.UpdateTime = &now
+		g.Expect(s.MaySaveNewTrans(ctx, global, []storage.TransBranchStore{})).ToNot(HaveOccurred())
+	}
+	// the db saved by the older versions has no scan bucket
+	g.Expect(db.Update(func(t *bolt.Tx) error { return t.DeleteBucket(bucketScan) })).ToNot(HaveOccurred())
+	g.Expect(initializeBuckets(db)).ToNot(HaveOccurred())
+
+	position := ""
+	globals, err := s.ScanTransGlobalStores(ctx, &position, 10, storage.TransGlobalScanCondition{})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(globals).To(HaveLen(2))
+	g.Expect(globals[0].Gid).To(Equal("gid2")) // the same create time, sorted by gid desc
+}
+
+func TestUpdateBranches(t *testing.T) {
+	g := NewWithT(t)
+	db, err := bolt.Open(path.Join(t.TempDir(), "./test.bolt"), 0666, &bolt.Options{Timeout: 1 * time.Second})
+	g.Expect(err).ToNot(HaveOccurred())
+	defer db.Close()
+	g.Expect(initializeBuckets(db)).ToNot(HaveOccurred())
+	s := &Store{boltDb: db}
+
+	now := time.Now()
+	global := &storage.TransGlobalStore{Gid: "gid", TransType: "saga", Status: "submitted", NextCronTime: &now}
+	global.CreateTime = &now
+	g.Expect(s.MaySaveNewTrans(ctx, global, []storage.TransBranchStore{
+		{Gid: "gid", BranchID: "01", Op: "compensate", URL: "url1", Status: "prepared"},
+		{Gid: "gid", BranchID: "01", Op: "action", URL: "url1", Status: "prepared"},
+	})).ToNot(HaveOccurred())
+	updates := []string{"status", "update_time", "attempts", "last_error"}
+
+	affected, err := s.UpdateBranches(ctx, []storage.TransBranchStore{
+		{Gid: "gid", BranchID: "01", Op: "action", Status: "prepared", Attempts: 1, LastError: "ongoing"},
+		{Gid: "gid", BranchID: "02", Op: "action", URL: "url2", Status: "succeed"},
+		{Gid: "gid-not-exists", BranchID: "01", Op: "action", Status: "succeed"},
+	}, updates)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(affected).To(Equal(2))
+	branches, err := s.FindBranches(ctx, "gid")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(branches).To(HaveLen(3))
+	g.Expect(branches[1].URL).To(Equal("url1"))
+	g.Expect(branches[1].Attempts).To(Equal(int64(1)))
+	g.Expect(branches[1].UpdateTime).ToNot(BeNil())
+	g.Expect(branches[2].BranchID).To(Equal("02"))
+	g.Expect(s.FindBranches(ctx, "gid-not-exists")).To(HaveLen(0))
+	g.Expect(failingURLs(s)).To(Equal([]storage.URLCount{{URL: "url1", Count: 1}}))
+
+	affected, err = s.UpdateBranches(ctx, []storage.TransBranchStore{
+		{Gid: "gid", BranchID: "01", Op: "action", Status: "succeed", Attempts: 2},
+	}, updates)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(affected).To(Equal(1))
+	g.Expect(mustFindBranches(s, "gid")[1].LastError).To(Equal(""))
+	g.Expect(failingURLs(s)).To(HaveLen(0))
+
+	// the failing urls of the finished trans are not counted
+	g.Expect(s.ChangeGlobalStatus(ctx, global, "succeed", []string{}, true)).ToNot(HaveOccurred())
+	_, err = s.UpdateBranches(ctx, []storage.TransBranchStore{
+		{Gid: "gid", BranchID: "01", Op: "compensate", Status: "prepared", LastError: "error"},
+	}, updates)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(failingURLs(s)).To(HaveLen(0))
+
+	_, err = s.UpdateBranches(ctx, []storage.TransBranchStore{{Gid: "gid", BranchID: "01", Op: "action"}}, []string{"gid"})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestConformance(t *testing.T) {
+	file := path.Join(t.TempDir(), "./test.bolt")
+	storagetest.Run(t, func() storage.Store { return NewStore(100, 10, WithPath(file)) })
+}
@@ -0,0 +1,484 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dtm-labs/dtm/client/dtmcli"
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/dtm/dtmutil"
+)
+
+// Store implements storage.Store, and stores all data in memory.
+// it is used for development and tests, all data are lost when dtm exits
+type Store struct {
+	mutex sync.Mutex
+	trans map[string]*transData
+	nodes map[string]storage.NodeStore
+
+	dataExpire         int64
+	finishedDataExpire int64
+	retryInterval      int64
+}
+
+// transData is the data of a global transaction. all data are copied when saved or read
+type transData struct {
+	global   storage.TransGlobalStore
+	branches []storage.TransBranchStore
+	events   []storage.TransEventStore
+	inCron   bool      // true if the trans is in the cron index, which is selected by LockOneGlobalTrans
+	expireAt time.Time // zero if never expire
+}
+
+// NewStore will return the memory implement
+func NewStore(dataExpire int64, finishedDataExpire int64, retryInterval int64) *Store {
+	return &Store{
+		trans:              map[string]*transData{},
+		nodes:              map[string]storage.NodeStore{},
+		dataExpire:         dataExpire,
+		finishedDataExpire: finishedDataExpire,
+		retryInterval:      retryInterval,
+	}
+}
+
+// clone copies the data in the same way as the other stores, so fields not stored are dropped
+func clone(src interface{}, dst interface{}) {
+	dtmimp.MustUnmarshal(dtmimp.MustMarshal(src), dst)
+}
+
+func cloneGlobal(g *storage.TransGlobalStore) storage.TransGlobalStore {
+	r := storage.TransGlobalStore{}
+	clone(g, &r)
+	return r
+}
+
+func cloneBranches(branches []storage.TransBranchStore) []storage.TransBranchStore {
+	r := []storage.TransBranchStore{}
+	clone(branches, &r)
+	return r
+}
+
+func expireAt(seconds int64) time.Time {
+	if seconds <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(seconds) * time.Second)
+}
+
+func (d *transData) isExpired(now time.Time) bool {
+	return !d.expireAt.IsZero() && now.After(d.expireAt)
+}
+
+// get returns the data of gid, nil if not found or expired. should be called with mutex locked
+func (s *Store) get(gid string) *transData {
+	d := s.trans[gid]
+	if d != nil && d.isExpired(time.Now()) {
+		delete(s.trans, gid)
+		return nil
+	}
+	return d
+}
+
+// all returns all the unexpired data. should be called with mutex locked
+func (s *Store) all() []*transData {
+	now := time.Now()
+	datas := []*transData{}
+	for gid, d := range s.trans {
+		if d.isExpired(now) {
+			delete(s.trans, gid)
+			continue
+		}
+		datas = append(datas, d)
+	}
+	return datas
+}
+
+// lock locks the mutex if ctx is not done
+func (s *Store) lock(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	return nil
+}
+
+// Ping always succeeds
+func (s *Store) Ping(ctx context.Context) error {
+	return nil
+}
+
+// PopulateData removes all data
+func (s *Store) PopulateData(ctx context.Context, skipDrop bool) error {
+	if skipDrop {
+		return nil
+	}
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mutex.Unlock()
+	s.trans = map[string]*transData{}
+	s.nodes = map[string]storage.NodeStore{}
+	return nil
+}
+
+// FindTransGlobalStore finds GlobalTrans data by gid
+func (s *Store) FindTransGlobalStore(ctx context.Context, gid string) (*storage.TransGlobalStore, error) {
+	if err := s.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mutex.Unlock()
+	d := s.get(gid)
+	if d == nil {
+		return nil, storage.ErrNotFound
+	}
+	g := cloneGlobal(&d.global)
+	return &g, nil
+}
+
+// ScanTransGlobalStores lists GlobalTrans data
+func (s *Store) ScanTransGlobalStores(ctx context.Context, position *string, limit int64, condition storage.TransGlobalScanCondition) ([]storage.TransGlobalStore, error) {
+	if err := s.lock(ctx); err != nil {
+		return nil, err
+	}
+	globals := []storage.TransGlobalStore{}
+	for _, d := range s.all() {
+		if strings.HasPrefix(d.global.Gid, condition.GidPrefix) {
+			globals = append(globals, cloneGlobal(&d.global))
+		}
+	}
+	s.mutex.Unlock()
+	return condition.FilterSortPage(globals, position, limit)
+}
+
+// FindBranches finds Branch data by gid
+func (s *Store) FindBranches(ctx context.Context, gid string) ([]storage.TransBranchStore, error) {
+	if err := s.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mutex.Unlock()
+	d := s.get(gid)
+	if d == nil {
+		return []storage.TransBranchStore{}, nil
+	}
+	return cloneBranches(d.branches), nil
+}
+
+// UpdateBranches updates the columns of the branches matched by branch_id and op, the unmatched branches are appended
+func (s *Store) UpdateBranches(ctx context.Context, branches []storage.TransBranchStore, updates []string) (int, error) {
+	if err := s.lock(ctx); err != nil {
+		return 0, err
+	}
+	defer s.mutex.Unlock()
+	affected := 0
+	now := time.Now()
+	for _, b := range branches {
+		d := s.get(b.Gid)
+		if d == nil {
+			continue
+		}
+		b.UpdateTime = &now
+		found := false
+		for i := range d.branches {
+			old := &d.branches[i]
+			if old.BranchID == b.BranchID && old.Op == b.Op {
+				if err := old.UpdateColumns(&b, updates); err != nil {
+					return affected, err
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			d.branches = append(d.branches, cloneBranches([]storage.TransBranchStore{b})...)
+		}
+		affected++
+	}
+	return affected, nil
+}
+
+// LockGlobalSaveBranches creates branches
+func (s *Store) LockGlobalSaveBranches(ctx context.Context, gid string, status string, branches []storage.TransBranchStore, branchStart int) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mutex.Unlock()
+	d := s.get(gid)
+	if d == nil || d.global.Status != status {
+		return storage.ErrNotFound
+	}
+	if branchStart == -1 {
+		for _, b := range d.branches {
+			if b.BranchID == branches[0].BranchID && b.Op == branches[0].Op {
+				return storage.ErrUniqueConflict
+			}
+		}
+		branchStart = len(d.branches)
+	}
+	for i, b := range cloneBranches(branches) {
+		if branchStart+i < len(d.branches) {
+			d.branches[branchStart+i] = b
+		} else {
+			d.branches = append(d.branches, b)
+		}
+	}
+	return nil
+}
+
+// MaySaveNewTrans creates a new trans
+func (s *Store) MaySaveNewTrans(ctx context.Context, global *storage.TransGlobalStore, branches []storage.TransBranchStore) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mutex.Unlock()
+	if s.get(global.Gid) != nil {
+		return storage.ErrUniqueConflict
+	}
+	d := &transData{
+		global:   cloneGlobal(global),
+		branches: cloneBranches(branches),
+		events:   []storage.TransEventStore{},
+		inCron:   true,
+		expireAt: expireAt(s.dataExpire),
+	}
+	// a finished trans may be saved, such as by migration
+	if global.IsFinished() && global.NotifyStatus != dtmcli.StatusPrepared {
+		d.inCron = false
+		d.expireAt = expireAt(s.finishedDataExpire)
+	}
+	s.trans[global.Gid] = d
+	return nil
+}
+
+// ChangeGlobalStatus changes global trans status
+func (s *Store) ChangeGlobalStatus(ctx context.Context, global *storage.TransGlobalStore, newStatus string, updates []string, finished bool) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mutex.Unlock()
+	old := global.Status
+	global.Status = newStatus
+	d := s.get(global.Gid)
+	if d == nil || d.global.Status != old {
+		return storage.ErrNotFound
+	}
+	d.global = cloneGlobal(global)
+	d.expireAt = expireAt(s.dataExpire)
+	if finished {
+		d.inCron = false
+		d.expireAt = expireAt(s.finishedDataExpire)
+	}
+	return nil
+}
+
+// TouchCronTime updates cronTime
+func (s *Store) TouchCronTime(ctx context.Context, global *storage.TransGlobalStore, nextCronInterval int64, nextCronTime *time.Time) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mutex.Unlock()
+	global.UpdateTime = dtmutil.GetNextTime(0)
+	global.NextCronTime = nextCronTime
+	global.NextCronInterval = nextCronInterval
+	d := s.get(global.Gid)
+	if d == nil || d.global.Status != global.Status {
+		return storage.ErrNotFound
+	}
+	touched := cloneGlobal(global)
+	d.global.UpdateTime = touched.UpdateTime
+	d.global.NextCronTime = touched.NextCronTime
+	d.global.NextCronInterval = touched.NextCronInterval
+	return nil
+}
+
+// LockOneGlobalTrans finds the trans with the earliest next cron time, and delays its next cron time
+func (s *Store) LockOneGlobalTrans(ctx context.Context, expireIn time.Duration) (*storage.TransGlobalStore, error) {
+	globals, err := s.LockGlobalTransBatch(ctx, expireIn, 1)
+	if len(globals) == 0 {
+		return nil, err
+	}
+	return &globals[0], err
+}
+
+// LockGlobalTransBatch locks at most limit global trans, the ones with the earliest cron time first
+func (s *Store) LockGlobalTransBatch(ctx context.Context, expireIn time.Duration, limit int) ([]storage.TransGlobalStore, error) {
+	if err := s.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mutex.Unlock()
+	expired := time.Now().Add(expireIn)
+	found := []*transData{}
+	for _, d := range s.all() {
+		if d.inCron && d.global.NextCronTime.Before(expired) {
+			found = append(found, d)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].global.NextCronTime.Before(*found[j].global.NextCronTime) })
+	if len(found) > limit {
+		found = found[:limit]
+	}
+	globals := []storage.TransGlobalStore{}
+	for _, d := range found {
+		d.global.Owner = storage.LockOwnerFromContext(ctx)
+		d.global.UpdateTime = dtmutil.GetNextTime(0)
+		d.global.NextCronTime = dtmutil.GetNextTime(s.retryInterval)
+		globals = append(globals, cloneGlobal(&d.global))
+	}
+	return globals, nil
+}
+
+// ResetCronTime reset nextCronTime
+// unfinished transactions need to be retried as soon as possible after business downtime is recovered
+func (s *Store) ResetCronTime(ctx context.Context, after time.Duration, limit int64) (succeedCount int64, hasRemaining bool, err error) {
+	if err = s.lock(ctx); err != nil {
+		return
+	}
+	defer s.mutex.Unlock()
+	timeout := time.Now().Add(after)
+	for _, d := range s.all() {
+		if !d.inCron || d.global.IsFinished() || !d.global.NextCronTime.After(timeout) {
+			continue
+		}
+		if succeedCount == limit {
+			hasRemaining = true
+			break
+		}
+		d.global.UpdateTime = dtmutil.GetNextTime(0)
+		d.global.NextCronTime = dtmutil.GetNextTime(0)
+		succeedCount++
+	}
+	return
+}
+
+// SaveTransEvents saves the status transitions. the events are stored with the trans, and expire with it
+func (s *Store) SaveTransEvents(ctx context.Context, events []storage.TransEventStore) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mutex.Unlock()
+	for _, e := range events {
+		d := s.get(e.Gid)
+		if d == nil {
+			continue
+		}
+		e.ID = uint64(len(d.events) + 1)
+		d.events = append(d.events, e)
+	}
+	return nil
+}
+
+// FindTransEvents finds the status transitions by gid, in the order of occurrence
+func (s *Store) FindTransEvents(ctx context.Context, gid string) ([]storage.TransEventStore, error) {
+	if err := s.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mutex.Unlock()
+	events := []storage.TransEventStore{}
+	if d := s.get(gid); d != nil {
+		clone(d.events, &events)
+	}
+	return events, nil
+}
+
+// GetTransStats computes the statistics from all the data
+func (s *Store) GetTransStats(ctx context.Context, topURLs int) (*storage.TransStats, error) {
+	if err := s.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mutex.Unlock()
+	stats := &storage.TransStats{Counts: []storage.TransCount{}}
+	now := time.Now()
+	counts := map[storage.TransCount]int64{}
+	failing := map[string]int64{}
+	for _, d := range s.all() {
+		g := &d.global
+		counts[storage.TransCount{Status: g.Status, TransType: g.TransType}]++
+		if d.inCron && g.NextCronTime.Before(now) {
+			stats.CronLagCount++
+		}
+		if g.IsFinished() {
+			continue
+		}
+		if g.CreateTime != nil && (stats.OldestUnfinishedTime == nil || g.CreateTime.Before(*stats.OldestUnfinishedTime)) {
+			createTime := *g.CreateTime
+			stats.OldestUnfinishedTime = &createTime
+		}
+		for i := range d.branches {
+			if storage.IsFailingBranch(&d.branches[i]) {
+				failing[d.branches[i].URL]++
+			}
+		}
+	}
+	for c, count := range counts {
+		c.Count = count
+		stats.Counts = append(stats.Counts, c)
+	}
+	sort.Slice(stats.Counts, func(i, j int) bool {
+		ci, cj := stats.Counts[i], stats.Counts[j]
+		return ci.Status < cj.Status || ci.Status == cj.Status && ci.TransType < cj.TransType
+	})
+	stats.FailingURLs = storage.TopURLCounts(failing, topURLs)
+	return stats, nil
+}
+
+// SaveNode creates or updates the node
+func (s *Store) SaveNode(ctx context.Context, node *storage.NodeStore) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mutex.Unlock()
+	n := storage.NodeStore{}
+	clone(node, &n)
+	s.nodes[node.ID] = n
+	return nil
+}
+
+// FindNodes returns all the nodes, sorted by id
+func (s *Store) FindNodes(ctx context.Context) ([]storage.NodeStore, error) {
+	if err := s.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer s.mutex.Unlock()
+	nodes := []storage.NodeStore{}
+	for _, n := range s.nodes {
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes, nil
+}
+
+// RemoveNode removes the node
+func (s *Store) RemoveNode(ctx context.Context, id string) error {
+	if err := s.lock(ctx); err != nil {
+		return err
+	}
+	defer s.mutex.Unlock()
+	delete(s.nodes, id)
+	return nil
+}
+
+// ReclaimTrans makes the unfinished global trans locked by the node due now
+func (s *Store) ReclaimTrans(ctx context.Context, nodeID string) (int64, error) {
+	if err := s.lock(ctx); err != nil {
+		return 0, err
+	}
+	defer s.mutex.Unlock()
+	count := int64(0)
+	for _, d := range s.all() {
+		if d.inCron && d.global.Owner == nodeID {
+			d.global.Owner = ""
+			d.global.NextCronTime = dtmutil.GetNextTime(0)
+			count++
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/dtm/dtmsvr/storage/storagetest"
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+func newTrans(gid string, status string, nextCron time.Duration) (*storage.TransGlobalStore, []storage.TransBranchStore) {
+	now := time.Now()
+	next := now.Add(nextCron)
+	global := &storage.TransGlobalStore{Gid: gid, TransType: "saga", Status: status, NextCronTime: &next}
+	global.CreateTime = &now
+	branches := []storage.TransBranchStore{
+		{Gid: gid, BranchID: "01", Op: "compensate", URL: "url1", Status: "prepared"},
+		{Gid: gid, BranchID: "01", Op: "action", URL: "url1", Status: "prepared"},
+	}
+	return global, branches
+}
+
+func mustFindGlobal(t *testing.T, s *Store, gid string) *storage.TransGlobalStore {
+	g, err := s.FindTransGlobalStore(ctx, gid)
+	assert.Nil(t, err)
+	return g
+}
+
+func mustFindBranches(t *testing.T, s *Store, gid string) []storage.TransBranchStore {
+	branches, err := s.FindBranches(ctx, gid)
+	assert.Nil(t, err)
+	return branches
+}
+
+func mustLockOne(t *testing.T, s *Store, expireIn time.Duration) *storage.TransGlobalStore {
+	g, err := s.LockOneGlobalTrans(ctx, expireIn)
+	assert.Nil(t, err)
+	return g
+}
+
+func TestSaveAndFind(t *testing.T) {
+	s := NewStore(100, 10, 10)
+	global, branches := newTrans("gid", "submitted", 0)
+	assert.Nil(t, s.MaySaveNewTrans(ctx, global, branches))
+	assert.Equal(t, storage.ErrUniqueConflict, s.MaySaveNewTrans(ctx, global, branches))
+	_, err := s.FindTransGlobalStore(ctx, "gid1")
+	assert.Equal(t, storage.ErrNotFound, err)
+	assert.Equal(t, "submitted", mustFindGlobal(t, s, "gid").Status)
+
+	branches[1].Status = "succeed"
+	assert.Equal(t, "prepared", mustFindBranches(t, s, "gid")[1].Status) // saved data is copied
+	assert.Nil(t, s.LockGlobalSaveBranches(ctx, "gid", "submitted", branches[1:], 1))
+	assert.Equal(t, "succeed", mustFindBranches(t, s, "gid")[1].Status)
+	assert.Equal(t, storage.ErrNotFound, s.LockGlobalSaveBranches(ctx, "gid", "prepared", branches[1:], 1))
+	assert.Equal(t, storage.ErrUniqueConflict, s.LockGlobalSaveBranches(ctx, "gid", "submitted", branches[1:], -1))
+
+	branches[0].Status = "succeed"
+	affected, err := s.UpdateBranches(ctx, branches[:1], []string{"status", "update_time"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, affected)
+	assert.Equal(t, "succeed", mustFindBranches(t, s, "gid")[0].Status)
+	_, err = s.UpdateBranches(ctx, branches[:1], []string{"gid"})
+	assert.Error(t, err)
+	count := len(mustFindBranches(t, s, "gid"))
+	affected, err = s.UpdateBranches(ctx, []storage.TransBranchStore{{Gid: "gid", BranchID: "02", Op: "action", Status: "succeed"}}, []string{"status"})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, affected)
+	assert.Len(t, mustFindBranches(t, s, "gid"), count+1) // the unmatched branch is appended, like the workflow branches
+
+	assert.Nil(t, s.ChangeGlobalStatus(ctx, global, "succeed", []string{"status"}, true))
+	assert.Equal(t, "succeed", mustFindGlobal(t, s, "gid").Status)
+	global.Status = "submitted" // the status is changed by others
+	assert.Equal(t, storage.ErrNotFound, s.ChangeGlobalStatus(ctx, global, "failed", []string{"status"}, true))
+	assert.Nil(t, mustLockOne(t, s, time.Minute)) // finished trans is not in the cron index
+
+	assert.Nil(t, s.SaveTransEvents(ctx, []storage.TransEventStore{{Gid: "gid", NewStatus: "submitted"}, {Gid: "gid", NewStatus: "succeed"}}))
+	events, err := s.FindTransEvents(ctx, "gid")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(events))
+	assert.Equal(t, uint64(2), events[1].ID)
+
+	assert.Nil(t, s.PopulateData(ctx, false))
+	_, err = s.FindTransGlobalStore(ctx, "gid")
+	assert.Equal(t, storage.ErrNotFound, err)
+}
+
+func TestCronIndex(t *testing.T) {
+	s := NewStore(100, 10, 10)
+	for i, next := range []time.Duration{time.Second, 0, time.Hour} {
+		global, branches := newTrans(fmt.Sprintf("gid%d", i), "submitted", next)
+		assert.Nil(t, s.MaySaveNewTrans(ctx, global, branches))
+	}
+	assert.Equal(t, "gid1", mustLockOne(t, s, 2*time.Second).Gid)
+	assert.Equal(t, "gid0", mustLockOne(t, s, 2*time.Second).Gid)
+	assert.Nil(t, mustLockOne(t, s, 2*time.Second)) // the locked trans are delayed by retry interval
+
+	count, hasRemaining, err := s.ResetCronTime(ctx, time.Minute, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.False(t, hasRemaining)
+	assert.Equal(t, "gid2", mustLockOne(t, s, 0).Gid)
+
+	count, hasRemaining, err = s.ResetCronTime(ctx, 0, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.True(t, hasRemaining)
+
+	global := mustFindGlobal(t, s, "gid2")
+	next := time.Now().Add(-time.Second)
+	assert.Nil(t, s.TouchCronTime(ctx, global, 20, &next))
+	assert.Equal(t, int64(20), mustFindGlobal(t, s, "gid2").NextCronInterval)
+	assert.Equal(t, "gid2", mustLockOne(t, s, 0).Gid)
+	stats, err := s.GetTransStats(ctx, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []storage.TransCount{{Status: "submitted", TransType: "saga", Count: 3}}, stats.Counts)
+	assert.NotNil(t, stats.OldestUnfinishedTime)
+}
+
+func TestExpire(t *testing.T) {
+	s := NewStore(1, 0, 10)
+	global, branches := newTrans("gid", "submitted", 0)
+	assert.Nil(t, s.MaySaveNewTrans(ctx, global, branches))
+	s.trans["gid"].expireAt = time.Now().Add(-time.Second)
+	_, err := s.FindTransGlobalStore(ctx, "gid")
+	assert.Equal(t, storage.ErrNotFound, err)
+	assert.Equal(t, 0, len(mustFindBranches(t, s, "gid")))
+	assert.Nil(t, s.MaySaveNewTrans(ctx, global, branches)) // the expired gid can be reused
+
+	assert.Nil(t, s.ChangeGlobalStatus(ctx, global, "succeed", []string{"status"}, true))
+	assert.True(t, s.trans["gid"].expireAt.IsZero()) // finished data never expire if finishedDataExpire is 0
+}
+
+func TestCanceled(t *testing.T) {
+	s := NewStore(100, 10, 10)
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	global, branches := newTrans("gid", "submitted", 0)
+	assert.Equal(t, context.Canceled, s.MaySaveNewTrans(canceled, global, branches))
+	_, err := s.FindTransGlobalStore(canceled, "gid")
+	assert.Equal(t, context.Canceled, err)
+	_, err = s.FindTransGlobalStore(ctx, "gid")
+	assert.Equal(t, storage.ErrNotFound, err)
+}
+
+func TestConcurrent(t *testing.T) {
+	s := NewStore(100, 10, 10)
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			global, branches := newTrans(fmt.Sprintf("gid%d", i%5), "submitted", 0)
+			_ = s.MaySaveNewTrans(ctx, global, branches)
+			_, _ = s.LockOneGlobalTrans(ctx, time.Second)
+			_, _ = s.ScanTransGlobalStores(ctx, new(string), 10, storage.TransGlobalScanCondition{})
+		}(i)
+	}
+	wg.Wait()
+	position := ""
+	globals, err := s.ScanTransGlobalStores(ctx, &position, 10, storage.TransGlobalScanCondition{})
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(globals))
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func() storage.Store { return NewStore(100, 10, 10) })
+}
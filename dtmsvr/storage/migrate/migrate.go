@@ -0,0 +1,255 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/logger"
+)
+
+// Result is the result of Migrate or Verify
+type Result struct {
+	Total       int64    `json:"total"`       // the number of global transactions scanned from the source
+	Copied      int64    `json:"copied"`      // the number of global transactions copied to the target
+	Overwritten int64    `json:"overwritten"` // the number of global transactions in the target overwritten by the newer ones from the source
+	Same        int64    `json:"same"`        // the number of global transactions already in the target with the same data
+	Missing     []string `json:"missing"`     // the gids not found in the target, only for Verify
+	Mismatched  []string `json:"mismatched"`  // the gids found in the target with different data
+}
+
+func newResult() *Result {
+	return &Result{Missing: []string{}, Mismatched: []string{}}
+}
+
+// the columns overwritten when the global transaction in the source is newer
+var globalColumns = []string{"status", "query_prepared", "protocol", "result", "rollback_reason", "options", "custom_data", "ext_data",
+	"notify_status", "next_cron_interval", "next_cron_time", "finish_time", "rollback_time", "update_time"}
+
+// the columns overwritten for the branches of a newer global transaction
+var branchColumns = []string{"status", "finish_time", "rollback_time", "attempts", "last_error",
+	"last_status_code", "last_response", "update_time"}
+
+// Migrate copies all the global transactions, with their branches and events, from src to dst.
+// a transaction already in dst is overwritten if it is updated later in src, otherwise it is compared
+// and recorded in Mismatched if the data is different. the events missing in dst are always copied, so it can be rerun
+func Migrate(ctx context.Context, src storage.Store, dst storage.Store, batchSize int64) (*Result, error) {
+	result := newResult()
+	err := scanAll(ctx, src, batchSize, func(g *storage.TransGlobalStore) error {
+		result.Total++
+		branches, err := src.FindBranches(ctx, g.Gid)
+		if err != nil {
+			return err
+		}
+		err = dst.MaySaveNewTrans(ctx, withoutID(g), withoutIDs(branches))
+		if errors.Is(err, storage.ErrUniqueConflict) {
+			err = result.mayOverwrite(ctx, g, branches, dst)
+		} else if err == nil {
+			result.Copied++
+			logger.Debugf("migrated gid: %s", g.Gid)
+		}
+		if err != nil {
+			return err
+		}
+		return copyEvents(ctx, g.Gid, src, dst)
+	})
+	return result, err
+}
+
+// mayOverwrite overwrites the global transaction in dst and its branches if g is updated later, otherwise they are compared
+func (r *Result) mayOverwrite(ctx context.Context, g *storage.TransGlobalStore, branches []storage.TransBranchStore, dst storage.Store) error {
+	dg, err := dst.FindTransGlobalStore(ctx, g.Gid)
+	if err != nil {
+		return err
+	}
+	if g.UpdateTime == nil || dg.UpdateTime != nil && !g.UpdateTime.After(*dg.UpdateTime) {
+		return r.compare(ctx, g, branches, dst)
+	}
+	if _, err := dst.UpdateBranches(ctx, withoutIDs(branches), branchColumns); err != nil {
+		return err
+	}
+	ng := withoutID(g)
+	ng.Status = dg.Status
+	finished := g.IsFinished()
+	if !finished { // the cron time is changed by TouchCronTime, which maintains the cron index of dst
+		ng.NextCronTime, ng.NextCronInterval = dg.NextCronTime, dg.NextCronInterval
+	}
+	err = dst.ChangeGlobalStatus(ctx, ng, g.Status, globalColumns, finished)
+	if err == nil && !finished {
+		err = dst.TouchCronTime(ctx, ng, g.NextCronInterval, g.NextCronTime)
+	}
+	if errors.Is(err, storage.ErrNotFound) { // changed by others during the migration
+		r.Mismatched = append(r.Mismatched, g.Gid)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	r.Overwritten++
+	logger.Debugf("overwritten gid: %s", g.Gid)
+	return nil
+}
+
+// copyEvents saves the events of gid in src which are not found in dst
+func copyEvents(ctx context.Context, gid string, src storage.Store, dst storage.Store) error {
+	events, err := src.FindTransEvents(ctx, gid)
+	if err != nil || len(events) == 0 {
+		return err
+	}
+	devents, err := dst.FindTransEvents(ctx, gid)
+	if err != nil {
+		return err
+	}
+	saved := map[string]int{}
+	for i := range devents {
+		saved[eventDigest(&devents[i])]++
+	}
+	missing := []storage.TransEventStore{}
+	for _, e := range events {
+		if d := eventDigest(&e); saved[d] > 0 {
+			saved[d]--
+			continue
+		}
+		e.ID = 0 // the id is assigned by dst
+		missing = append(missing, e)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	return dst.SaveTransEvents(ctx, missing)
+}
+
+// eventDigest returns the data of the event except the id, which is different in each store
+func eventDigest(e *storage.TransEventStore) string {
+	ne := *e
+	ne.ID = 0
+	ne.CreateTime = nil
+	return dtmimp.MustMarshalString(&ne) + fmt.Sprint(unix(e.CreateTime))
+}
+
+// withoutID returns a copy of g, whose id will be assigned by dst
+func withoutID(g *storage.TransGlobalStore) *storage.TransGlobalStore {
+	ng := *g
+	ng.ID = 0
+	return &ng
+}
+
+// withoutIDs returns a copy of branches, whose ids will be assigned by dst
+func withoutIDs(branches []storage.TransBranchStore) []storage.TransBranchStore {
+	nbs := make([]storage.TransBranchStore, len(branches))
+	copy(nbs, branches)
+	for i := range nbs {
+		nbs[i].ID = 0
+	}
+	return nbs
+}
+
+// Verify compares all the global transactions and their branches in src with the ones in dst
+func Verify(ctx context.Context, src storage.Store, dst storage.Store, batchSize int64) (*Result, error) {
+	result := newResult()
+	err := scanAll(ctx, src, batchSize, func(g *storage.TransGlobalStore) error {
+		result.Total++
+		branches, err := src.FindBranches(ctx, g.Gid)
+		if err != nil {
+			return err
+		}
+		return result.compare(ctx, g, branches, dst)
+	})
+	return result, err
+}
+
+// scanAll calls fn for every global transaction in the order of creation,
+// so that the transactions created during the scanning are also scanned
+func scanAll(ctx context.Context, s storage.Store, batchSize int64, fn func(g *storage.TransGlobalStore) error) error {
+	condition := storage.TransGlobalScanCondition{SortBy: storage.SortByCreateTime, SortAsc: true}
+	for position := ""; ; {
+		globals, err := s.ScanTransGlobalStores(ctx, &position, batchSize, condition)
+		if err != nil {
+			return err
+		}
+		for i := range globals {
+			if err := fn(&globals[i]); err != nil {
+				return err
+			}
+		}
+		if position == "" {
+			return nil
+		}
+	}
+}
+
+func (r *Result) compare(ctx context.Context, g *storage.TransGlobalStore, branches []storage.TransBranchStore, dst storage.Store) error {
+	dg, err := dst.FindTransGlobalStore(ctx, g.Gid)
+	if err == storage.ErrNotFound {
+		r.Missing = append(r.Missing, g.Gid)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	dbranches, err := dst.FindBranches(ctx, g.Gid)
+	if err != nil {
+		return err
+	}
+	if digestOf(g, branches) != digestOf(dg, dbranches) {
+		r.Mismatched = append(r.Mismatched, g.Gid)
+	} else {
+		r.Same++
+	}
+	return nil
+}
+
+// digestOf returns the data to be compared. the fields not saved by every store and the fractional seconds are ignored
+func digestOf(g *storage.TransGlobalStore, branches []storage.TransBranchStore) string {
+	bs := []map[string]interface{}{}
+	for _, b := range branches {
+		bs = append(bs, map[string]interface{}{
+			"gid":              b.Gid,
+			"url":              b.URL,
+			"bin_data":         b.BinData,
+			"branch_id":        b.BranchID,
+			"op":               b.Op,
+			"status":           b.Status,
+			"create_time":      unix(b.CreateTime),
+			"finish_time":      unix(b.FinishTime),
+			"rollback_time":    unix(b.RollbackTime),
+			"attempts":         b.Attempts,
+			"last_error":       b.LastError,
+			"last_status_code": b.LastStatusCode,
+			"last_response":    b.LastResponse,
+		})
+	}
+	return dtmimp.MustMarshalString(map[string]interface{}{
+		"gid":                g.Gid,
+		"trans_type":         g.TransType,
+		"status":             g.Status,
+		"query_prepared":     g.QueryPrepared,
+		"protocol":           g.Protocol,
+		"result":             g.Result,
+		"rollback_reason":    g.RollbackReason,
+		"options":            g.Options,
+		"custom_data":        g.CustomData,
+		"ext_data":           g.ExtData,
+		"notify_status":      g.NotifyStatus,
+		"next_cron_interval": g.NextCronInterval,
+		"create_time":        unix(g.CreateTime),
+		"finish_time":        unix(g.FinishTime),
+		"rollback_time":      unix(g.RollbackTime),
+		"next_cron_time":     unix(g.NextCronTime),
+		"branches":           bs,
+	})
+}
+
+func unix(t *time.Time) int64 {
+	if t == nil {
+		return 0
+	}
+	return t.Unix()
+}
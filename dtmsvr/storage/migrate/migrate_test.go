@@ -0,0 +1,173 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/dtm/dtmsvr/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+func saveTrans(s storage.Store, gid string, status string) {
+	now := time.Now()
+	next := now.Add(time.Hour)
+	global := &storage.TransGlobalStore{Gid: gid, TransType: "saga", Status: status, NextCronTime: &next,
+		NextCronInterval: 20, Options: `{"retry_interval":20}`, ExtData: `{"headers":{"k":"v"}}`}
+	global.CreateTime = &now
+	branches := []storage.TransBranchStore{
+		{Gid: gid, BranchID: "01", Op: "compensate", URL: "url1", Status: "prepared"},
+		{Gid: gid, BranchID: "01", Op: "action", URL: "url1", Status: "prepared", LastError: "err"},
+	}
+	err := s.MaySaveNewTrans(ctx, global, branches)
+	if err != nil {
+		panic(err)
+	}
+	err = s.SaveTransEvents(ctx, []storage.TransEventStore{{Gid: gid, OldStatus: "", NewStatus: status}})
+	if err != nil {
+		panic(err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	src := memory.NewStore(100, 10, 10)
+	dst := memory.NewStore(100, 10, 3*3600) // the locked trans will not be locked again in the test
+	for i := 0; i < 5; i++ {
+		saveTrans(src, fmt.Sprintf("gid%d", i), "submitted")
+	}
+	saveTrans(src, "gid-succeed", "succeed")
+	saveTrans(dst, "gid0", "submitted")
+	saveTrans(dst, "gid1", "aborting")
+
+	r, err := Verify(ctx, src, dst, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), r.Total)
+	assert.Equal(t, int64(1), r.Same)
+	assert.Equal(t, []string{"gid2", "gid3", "gid4", "gid-succeed"}, r.Missing)
+	assert.Equal(t, []string{"gid1"}, r.Mismatched)
+
+	r, err = Migrate(ctx, src, dst, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(6), r.Total)
+	assert.Equal(t, int64(4), r.Copied)
+	assert.Equal(t, int64(1), r.Same)
+	assert.Equal(t, []string{"gid1"}, r.Mismatched)
+
+	g, _ := dst.FindTransGlobalStore(ctx, "gid3")
+	sg, _ := src.FindTransGlobalStore(ctx, "gid3")
+	assert.Equal(t, sg.Status, g.Status)
+	assert.Equal(t, sg.NextCronTime.Unix(), g.NextCronTime.Unix())
+	assert.Equal(t, sg.Options, g.Options)
+	assert.Equal(t, sg.ExtData, g.ExtData)
+	sbs, _ := src.FindBranches(ctx, "gid3")
+	dbs, _ := dst.FindBranches(ctx, "gid3")
+	assert.Equal(t, sbs, dbs)
+	events, _ := dst.FindTransEvents(ctx, "gid3")
+	assert.Len(t, events, 1)
+
+	r, err = Migrate(ctx, src, dst, 2) // rerun
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), r.Copied)
+	assert.Equal(t, int64(5), r.Same)
+	assert.Equal(t, []string{"gid1"}, r.Mismatched)
+	r, err = Verify(ctx, dst, src, 2)
+	assert.Nil(t, err)
+	assert.Empty(t, r.Missing)
+
+	// the finished trans is not processed by cron in dst
+	for {
+		g, err := dst.LockOneGlobalTrans(ctx, 2*time.Hour)
+		assert.Nil(t, err)
+		if g == nil {
+			break
+		}
+		assert.NotEqual(t, "gid-succeed", g.Gid)
+	}
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = Migrate(canceled, src, dst, 2)
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestMigrateOverwrite(t *testing.T) {
+	src := memory.NewStore(100, 10, 10)
+	dst := memory.NewStore(100, 10, 3*3600)
+	saveTrans(dst, "gid-newer", "submitted")
+	saveTrans(dst, "gid-older", "submitted")
+	saveTrans(dst, "gid-finished", "submitted")
+	saveTrans(src, "gid-newer", "aborting")
+	saveTrans(src, "gid-older", "aborting")
+	saveTrans(src, "gid-finished", "succeed")
+	later := time.Now().Add(-time.Minute)
+	earlier := time.Now().Add(-2 * time.Minute)
+	for gid, updated := range map[string]*time.Time{"gid-newer": &later, "gid-older": &earlier, "gid-finished": &later} {
+		g, _ := src.FindTransGlobalStore(ctx, gid)
+		g.UpdateTime = updated
+		next := time.Now().Add(2 * time.Hour)
+		err := src.ChangeGlobalStatus(ctx, g, g.Status, []string{"update_time"}, g.IsFinished())
+		assert.Nil(t, err)
+		if !g.IsFinished() {
+			assert.Nil(t, src.TouchCronTime(ctx, g, 30, &next))
+			g.UpdateTime = updated
+			assert.Nil(t, src.ChangeGlobalStatus(ctx, g, g.Status, []string{"update_time"}, false))
+		}
+		d, _ := dst.FindTransGlobalStore(ctx, gid)
+		d.UpdateTime = &earlier
+		assert.Nil(t, dst.ChangeGlobalStatus(ctx, d, d.Status, []string{"update_time"}, false))
+	}
+	_, err := src.UpdateBranches(ctx, []storage.TransBranchStore{{Gid: "gid-newer", BranchID: "01", Op: "action", Status: "failed"}}, []string{"status"})
+	assert.Nil(t, err)
+
+	r, err := Migrate(ctx, src, dst, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), r.Overwritten)
+	assert.Equal(t, []string{"gid-older"}, r.Mismatched)
+
+	g, _ := dst.FindTransGlobalStore(ctx, "gid-newer")
+	assert.Equal(t, "aborting", g.Status)
+	assert.Equal(t, int64(30), g.NextCronInterval)
+	sg, _ := src.FindTransGlobalStore(ctx, "gid-newer")
+	assert.Equal(t, sg.NextCronTime.Unix(), g.NextCronTime.Unix())
+	bs, _ := dst.FindBranches(ctx, "gid-newer")
+	assert.Equal(t, "failed", bs[1].Status)
+	g, _ = dst.FindTransGlobalStore(ctx, "gid-older")
+	assert.Equal(t, "submitted", g.Status)
+	g, _ = dst.FindTransGlobalStore(ctx, "gid-finished")
+	assert.Equal(t, "succeed", g.Status)
+	// the events of src are copied to dst, even if the trans is not overwritten
+	events, _ := dst.FindTransEvents(ctx, "gid-older")
+	assert.Len(t, events, 2)
+
+	r, err = Migrate(ctx, src, dst, 2) // rerun
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), r.Overwritten)
+	assert.Equal(t, int64(2), r.Same)
+	events, _ = dst.FindTransEvents(ctx, "gid-newer")
+	assert.Len(t, events, 2)
+}
+
+func TestMigrateEventsAfterConflict(t *testing.T) {
+	src := memory.NewStore(100, 10, 10)
+	dst := memory.NewStore(100, 10, 10)
+	saveTrans(src, "gid", "submitted")
+	sg, _ := src.FindTransGlobalStore(ctx, "gid")
+	bs, _ := src.FindBranches(ctx, "gid")
+	// the trans is saved by a previous run, which failed before saving the events
+	assert.Nil(t, dst.MaySaveNewTrans(ctx, sg, bs))
+
+	r, err := Migrate(ctx, src, dst, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), r.Same)
+	events, _ := dst.FindTransEvents(ctx, "gid")
+	assert.Len(t, events, 1)
+}
@@ -0,0 +1,626 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package mongo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/dtm-labs/dtm/client/dtmcli"
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr/config"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/dtm/dtmutil"
+	"github.com/dtm-labs/logger"
+)
+
+var conf = &config.Config
+
+// the collections, named as the tables of sql store
+const (
+	colGlobal   = "trans_global"
+	colBranches = "trans_branch_op"
+	colEvents   = "trans_event"
+)
+
+// Store is the storage with mongo. multi-document transactions are used, so mongo should be a replica set
+type Store struct {
+	storeConf *config.Store
+	client    *mongo.Client
+	once      sync.Once
+}
+
+// NewStore returns the mongo store with the conf
+func NewStore(storeConf *config.Store) *Store {
+	return &Store{storeConf: storeConf}
+}
+
+// globalDoc is the document of global transaction
+type globalDoc struct {
+	storage.TransGlobalStore
+	ExpireAt *time.Time `json:"expire_at,omitempty"` // the documents are removed by the ttl index, never expire if nil
+}
+
+// branchDoc is the document of branch
+type branchDoc struct {
+	storage.TransBranchStore
+	Pos      int        `json:"pos"` // the position of the branch in the global transaction
+	ExpireAt *time.Time `json:"expire_at,omitempty"`
+}
+
+// eventDoc is the document of event
+type eventDoc struct {
+	storage.TransEventStore
+	ExpireAt *time.Time `json:"expire_at,omitempty"`
+}
+
+// structTagParser uses the json tags, so that the fields are named as the other stores, and the embedded structs are inlined
+var structTagParser bsoncodec.StructTagParserFunc = func(sf reflect.StructField) (bsoncodec.StructTags, error) {
+	if sf.Anonymous {
+		return bsoncodec.StructTags{Name: sf.Name, Inline: true}, nil
+	}
+	return bsoncodec.JSONFallbackStructTagParser(sf)
+}
+
+var registry = newRegistry()
+
+func newRegistry() *bsoncodec.Registry {
+	codec, err := bsoncodec.NewStructCodec(structTagParser)
+	dtmimp.E2P(err)
+	return bson.NewRegistryBuilder().
+		RegisterDefaultEncoder(reflect.Struct, codec).
+		RegisterDefaultDecoder(reflect.Struct, codec).
+		Build()
+}
+
+func expireAt(seconds int64) *time.Time {
+	if seconds <= 0 {
+		return nil
+	}
+	return dtmutil.GetNextTime(seconds)
+}
+
+func unfinishedFilter() bson.M {
+	return bson.M{"status": bson.M{"$in": storage.UnfinishedStatuses}}
+}
+
+// Ping execs ping cmd to mongo
+func (s *Store) Ping(ctx context.Context) error {
+	return s.mongoGet().Ping(ctx, nil)
+}
+
+// PopulateData drops the database of dtm, and creates the indexes
+func (s *Store) PopulateData(ctx context.Context, skipDrop bool) error {
+	if !skipDrop {
+		err := s.db().Drop(ctx)
+		logger.Infof("drop mongo database %s. result: %v", s.db().Name(), err)
+		if err != nil {
+			return err
+		}
+	}
+	return createIndexes(ctx, s.db())
+}
+
+// FindTransGlobalStore finds GlobalTrans data by gid
+func (s *Store) FindTransGlobalStore(ctx context.Context, gid string) (*storage.TransGlobalStore, error) {
+	doc := globalDoc{}
+	err := s.db().Collection(colGlobal).FindOne(ctx, bson.M{"gid": gid}).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, storage.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &doc.TransGlobalStore, nil
+}
+
+// ScanTransGlobalStores lists GlobalTrans data
+func (s *Store) ScanTransGlobalStores(ctx context.Context, position *string, limit int64, condition storage.TransGlobalScanCondition) ([]storage.TransGlobalStore, error) {
+	filters := bson.A{}
+	if condition.Status != "" {
+		filters = append(filters, bson.M{"status": condition.Status})
+	}
+	if condition.TransType != "" {
+		filters = append(filters, bson.M{"trans_type": condition.TransType})
+	}
+	if condition.GidPrefix != "" {
+		filters = append(filters, bson.M{"gid": bson.M{"$regex": "^" + regexp.QuoteMeta(condition.GidPrefix)}})
+	}
+	for field, cond := range map[string]map[string]time.Time{
+		"create_time": {"$gte": condition.CreateTimeStart, "$lt": condition.CreateTimeEnd},
+		"update_time": {"$gte": condition.UpdateTimeStart, "$lt": condition.UpdateTimeEnd},
+	} {
+		for op, t := range cond {
+			if !t.IsZero() {
+				filters = append(filters, bson.M{field: bson.M{op: t}})
+			}
+		}
+	}
+	if condition.StuckSeconds > 0 {
+		filters = append(filters, unfinishedFilter(), bson.M{"update_time": bson.M{"$lt": condition.StuckBefore(time.Now())}})
+	}
+	if condition.Unfinished {
+		filters = append(filters, unfinishedFilter())
+	}
+	sortBy, cmp, order := condition.GetSortBy(), "$lt", -1
+	if condition.SortAsc {
+		cmp, order = "$gt", 1
+	}
+	if *position != "" {
+		posTime, posGid, err := storage.DecodePosition(*position)
+		if err != nil {
+			return nil, err
+		}
+		filters = append(filters, bson.M{"$or": bson.A{
+			bson.M{sortBy: bson.M{cmp: posTime}},
+			bson.M{sortBy: posTime, "gid": bson.M{cmp: posGid}},
+		}})
+	}
+	filter := bson.M{}
+	if len(filters) > 0 {
+		filter["$and"] = filters
+	}
+	opts := options.Find().SetSort(bson.D{{Key: sortBy, Value: order}, {Key: "gid", Value: order}}).SetLimit(limit)
+	docs := []globalDoc{}
+	if err := s.findAll(ctx, colGlobal, &docs, filter, opts); err != nil {
+		return nil, err
+	}
+	globals := []storage.TransGlobalStore{}
+	for _, doc := range docs {
+		globals = append(globals, doc.TransGlobalStore)
+	}
+	condition.UpdatePosition(globals, position, limit)
+	return globals, nil
+}
+
+// FindBranches finds Branch data by gid
+func (s *Store) FindBranches(ctx context.Context, gid string) ([]storage.TransBranchStore, error) {
+	docs := []branchDoc{}
+	if err := s.findAll(ctx, colBranches, &docs, bson.M{"gid": gid}, options.Find().SetSort(bson.M{"pos": 1})); err != nil {
+		return nil, err
+	}
+	branches := []storage.TransBranchStore{}
+	for _, doc := range docs {
+		branches = append(branches, doc.TransBranchStore)
+	}
+	return branches, nil
+}
+
+// UpdateBranches updates the columns of the branches matched by branch_id and op, the unmatched branches are appended
+func (s *Store) UpdateBranches(ctx context.Context, branches []storage.TransBranchStore, updates []string) (int, error) {
+	models := []mongo.WriteModel{}
+	now := time.Now()
+	for _, b := range branches {
+		doc := bson.M{}
+		bs, err := bson.MarshalWithRegistry(registry, b)
+		if err == nil {
+			err = bson.UnmarshalWithRegistry(registry, bs, &doc)
+		}
+		if err != nil {
+			return 0, err
+		}
+		set, unset := bson.M{}, bson.M{}
+		for _, column := range updates {
+			if column == "update_time" {
+				set[column] = now
+			} else if v, ok := doc[column]; ok {
+				set[column] = v
+			} else { // the empty values are omitted
+				unset[column] = ""
+			}
+		}
+		update := bson.M{"$set": set}
+		if len(unset) > 0 {
+			update["$unset"] = unset
+		}
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"gid": b.Gid, "branch_id": b.BranchID, "op": b.Op}).
+			SetUpdate(update))
+	}
+	if len(models) == 0 {
+		return 0, nil
+	}
+	r, err := s.db().Collection(colBranches).BulkWrite(ctx, models)
+	if err != nil {
+		return 0, err
+	}
+	affected := int(r.MatchedCount)
+	if affected < len(models) {
+		appended, err := s.appendMissingBranches(ctx, branches, now)
+		return affected + appended, err
+	}
+	return affected, nil
+}
+
+// appendMissingBranches appends the branches not found, which expire with their global trans
+func (s *Store) appendMissingBranches(ctx context.Context, branches []storage.TransBranchStore, now time.Time) (int, error) {
+	appended := 0
+	for _, b := range branches {
+		g := globalDoc{}
+		err := s.db().Collection(colGlobal).FindOne(ctx, bson.M{"gid": b.Gid}).Decode(&g)
+		if err == mongo.ErrNoDocuments {
+			continue
+		} else if err != nil {
+			return appended, err
+		}
+		count, err := s.db().Collection(colBranches).CountDocuments(ctx, bson.M{"gid": b.Gid, "branch_id": b.BranchID, "op": b.Op})
+		if err != nil {
+			return appended, err
+		} else if count > 0 {
+			continue
+		}
+		count, err = s.db().Collection(colBranches).CountDocuments(ctx, bson.M{"gid": b.Gid})
+		if err != nil {
+			return appended, err
+		}
+		b.CreateTime = &now
+		b.UpdateTime = &now
+		err = s.insertBranches(ctx, []storage.TransBranchStore{b}, int(count), g.ExpireAt)
+		if err == storage.ErrUniqueConflict { // appended by others
+			continue
+		} else if err != nil {
+			return appended, err
+		}
+		appended++
+	}
+	return appended, nil
+}
+
+// LockGlobalSaveBranches creates branches
+func (s *Store) LockGlobalSaveBranches(ctx context.Context, gid string, status string, branches []storage.TransBranchStore, branchStart int) error {
+	return s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+		// the write makes this transaction conflict with the concurrent changes of the global transaction
+		r, err := s.db().Collection(colGlobal).UpdateOne(sc, bson.M{"gid": gid, "status": status}, bson.M{"$inc": bson.M{"lock_seq": 1}})
+		if err != nil {
+			return err
+		}
+		if r.MatchedCount == 0 {
+			return storage.ErrNotFound
+		}
+		expire := expireAt(s.storeConf.DataExpire)
+		if branchStart == -1 {
+			count, err := s.db().Collection(colBranches).CountDocuments(sc, bson.M{"gid": gid})
+			if err != nil {
+				return err
+			}
+			return s.insertBranches(sc, branches, int(count), expire)
+		}
+		for i, b := range branches {
+			doc := branchDoc{TransBranchStore: b, Pos: branchStart + i, ExpireAt: expire}
+			_, err := s.db().Collection(colBranches).ReplaceOne(sc, bson.M{"gid": b.Gid, "branch_id": b.BranchID, "op": b.Op}, doc)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *Store) insertBranches(sc context.Context, branches []storage.TransBranchStore, start int, expire *time.Time) error {
+	if len(branches) == 0 {
+		return nil
+	}
+	docs := []interface{}{}
+	for i, b := range branches {
+		docs = append(docs, branchDoc{TransBranchStore: b, Pos: start + i, ExpireAt: expire})
+	}
+	_, err := s.db().Collection(colBranches).InsertMany(sc, docs)
+	if mongo.IsDuplicateKeyError(err) {
+		return storage.ErrUniqueConflict
+	}
+	return err
+}
+
+// MaySaveNewTrans creates a new trans
+func (s *Store) MaySaveNewTrans(ctx context.Context, global *storage.TransGlobalStore, branches []storage.TransBranchStore) error {
+	return s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+		expire := expireAt(s.storeConf.DataExpire)
+		if global.IsFinished() && global.NotifyStatus != dtmcli.StatusPrepared { // a finished trans may be saved, such as by migration
+			expire = expireAt(s.storeConf.FinishedDataExpire)
+		}
+		_, err := s.db().Collection(colGlobal).InsertOne(sc, globalDoc{TransGlobalStore: *global, ExpireAt: expire})
+		if mongo.IsDuplicateKeyError(err) {
+			return storage.ErrUniqueConflict
+		}
+		if err != nil {
+			return err
+		}
+		return s.insertBranches(sc, branches, 0, expire)
+	})
+}
+
+// ChangeGlobalStatus changes global trans status
+func (s *Store) ChangeGlobalStatus(ctx context.Context, global *storage.TransGlobalStore, newStatus string, updates []string, finished bool) error {
+	old := global.Status
+	global.Status = newStatus
+	expire := expireAt(s.storeConf.DataExpire)
+	if finished {
+		expire = expireAt(s.storeConf.FinishedDataExpire)
+	}
+	return s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+		r, err := s.db().Collection(colGlobal).ReplaceOne(sc, bson.M{"gid": global.Gid, "status": old}, globalDoc{TransGlobalStore: *global, ExpireAt: expire})
+		if err != nil {
+			return err
+		}
+		if r.MatchedCount == 0 {
+			return storage.ErrNotFound
+		}
+		if !finished {
+			return nil
+		}
+		for _, col := range []string{colBranches, colEvents} {
+			_, err := s.db().Collection(col).UpdateMany(sc, bson.M{"gid": global.Gid}, bson.M{"$set": bson.M{"expire_at": expire}})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// TouchCronTime updates cronTime, and refreshes the expiration of the trans with its branches and events
+func (s *Store) TouchCronTime(ctx context.Context, global *storage.TransGlobalStore, nextCronInterval int64, nextCronTime *time.Time) error {
+	global.UpdateTime = dtmutil.GetNextTime(0)
+	global.NextCronTime = nextCronTime
+	global.NextCronInterval = nextCronInterval
+	expire := expireAt(s.storeConf.DataExpire)
+	return s.withTransaction(ctx, func(sc mongo.SessionContext) error {
+		r, err := s.db().Collection(colGlobal).UpdateOne(sc, bson.M{"gid": global.Gid, "status": global.Status}, bson.M{"$set": bson.M{
+			"next_cron_time":     global.NextCronTime,
+			"update_time":        global.UpdateTime,
+			"next_cron_interval": global.NextCronInterval,
+			"expire_at":          expire,
+		}})
+		if err != nil {
+			return err
+		}
+		if r.MatchedCount == 0 {
+			return storage.ErrNotFound
+		}
+		for _, col := range []string{colBranches, colEvents} {
+			_, err := s.db().Collection(col).UpdateMany(sc, bson.M{"gid": global.Gid}, bson.M{"$set": bson.M{"expire_at": expire}})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// LockOneGlobalTrans finds the trans with the earliest next cron time, and delays its next cron time
+func (s *Store) LockOneGlobalTrans(ctx context.Context, expireIn time.Duration) (*storage.TransGlobalStore, error) {
+	filter := bson.M{
+		"next_cron_time": bson.M{"$lt": time.Now().Add(expireIn)},
+		"$or":            bson.A{unfinishedFilter(), bson.M{"notify_status": dtmcli.StatusPrepared}},
+	}
+	update := bson.M{"$set": bson.M{
+		"update_time":    dtmutil.GetNextTime(0),
+		"next_cron_time": dtmutil.GetNextTime(conf.RetryInterval),
+	}}
+	opts := options.FindOneAndUpdate().SetSort(bson.M{"next_cron_time": 1}).SetReturnDocument(options.After)
+	doc := globalDoc{}
+	err := s.db().Collection(colGlobal).FindOneAndUpdate(ctx, filter, update, opts).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &doc.TransGlobalStore, nil
+}
+
+// ResetCronTime reset nextCronTime
+// unfinished transactions need to be retried as soon as possible after business downtime is recovered
+func (s *Store) ResetCronTime(ctx context.Context, after time.Duration, limit int64) (succeedCount int64, hasRemaining bool, err error) {
+	filter := unfinishedFilter()
+	filter["next_cron_time"] = bson.M{"$gt": time.Now().Add(after)}
+	cursor, err := s.db().Collection(colGlobal).Find(ctx, filter, options.Find().SetLimit(limit+1).SetProjection(bson.M{"gid": 1}))
+	if err != nil {
+		return 0, false, err
+	}
+	docs := []globalDoc{}
+	if err = cursor.All(ctx, &docs); err != nil {
+		return 0, false, err
+	}
+	if int64(len(docs)) > limit {
+		hasRemaining = true
+		docs = docs[:limit]
+	}
+	gids := []string{}
+	for _, doc := range docs {
+		gids = append(gids, doc.Gid)
+	}
+	if len(gids) == 0 {
+		return 0, false, nil
+	}
+	filter["gid"] = bson.M{"$in": gids}
+	r, err := s.db().Collection(colGlobal).UpdateMany(ctx, filter, bson.M{"$set": bson.M{
+		"update_time":    dtmutil.GetNextTime(0),
+		"next_cron_time": dtmutil.GetNextTime(0),
+	}})
+	if err != nil {
+		return 0, false, err
+	}
+	return r.ModifiedCount, hasRemaining, nil
+}
+
+// SaveTransEvents saves the status transitions
+func (s *Store) SaveTransEvents(ctx context.Context, events []storage.TransEventStore) error {
+	if len(events) == 0 {
+		return nil
+	}
+	docs := []interface{}{}
+	for _, e := range events {
+		docs = append(docs, eventDoc{TransEventStore: e, ExpireAt: expireAt(s.storeConf.DataExpire)})
+	}
+	_, err := s.db().Collection(colEvents).InsertMany(ctx, docs)
+	return err
+}
+
+// FindTransEvents finds the status transitions by gid, in the order of occurrence
+func (s *Store) FindTransEvents(ctx context.Context, gid string) ([]storage.TransEventStore, error) {
+	docs := []eventDoc{}
+	opts := options.Find().SetSort(bson.D{{Key: "create_time", Value: 1}, {Key: "_id", Value: 1}})
+	if err := s.findAll(ctx, colEvents, &docs, bson.M{"gid": gid}, opts); err != nil {
+		return nil, err
+	}
+	events := []storage.TransEventStore{}
+	for _, doc := range docs {
+		events = append(events, doc.TransEventStore)
+	}
+	return events, nil
+}
+
+// GetTransStats aggregates the statistics of transactions in mongo
+func (s *Store) GetTransStats(ctx context.Context, topURLs int) (*storage.TransStats, error) {
+	stats := &storage.TransStats{Counts: []storage.TransCount{}, FailingURLs: []storage.URLCount{}}
+	counts := []struct {
+		ID    storage.TransCount `json:"_id"`
+		Count int64              `json:"count"`
+	}{}
+	err := s.aggregate(ctx, colGlobal, &counts, bson.A{
+		bson.M{"$group": bson.M{"_id": bson.M{"status": "$status", "trans_type": "$trans_type"}, "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "_id.status", Value: 1}, {Key: "_id.trans_type", Value: 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range counts {
+		c.ID.Count = c.Count
+		stats.Counts = append(stats.Counts, c.ID)
+	}
+
+	oldest := globalDoc{}
+	opts := options.FindOne().SetSort(bson.M{"create_time": 1}).SetProjection(bson.M{"create_time": 1})
+	err = s.db().Collection(colGlobal).FindOne(ctx, unfinishedFilter(), opts).Decode(&oldest)
+	if err == nil {
+		stats.OldestUnfinishedTime = oldest.CreateTime
+	} else if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	stats.CronLagCount, err = s.db().Collection(colGlobal).CountDocuments(ctx, bson.M{
+		"next_cron_time": bson.M{"$lt": time.Now()},
+		"$or":            bson.A{unfinishedFilter(), bson.M{"notify_status": dtmcli.StatusPrepared}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.aggregate(ctx, colBranches, &stats.FailingURLs, bson.A{
+		bson.M{"$match": bson.M{"status": dtmcli.StatusPrepared, "last_error": bson.M{"$nin": bson.A{nil, ""}}}},
+		bson.M{"$lookup": bson.M{"from": colGlobal, "localField": "gid", "foreignField": "gid", "as": "global"}},
+		bson.M{"$match": bson.M{"global.status": bson.M{"$in": storage.UnfinishedStatuses}}},
+		bson.M{"$group": bson.M{"_id": "$url", "count": bson.M{"$sum": 1}}},
+		bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+		bson.M{"$limit": topURLs},
+		bson.M{"$project": bson.M{"url": "$_id", "count": 1}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+func (s *Store) findAll(ctx context.Context, col string, results interface{}, filter interface{}, opts ...*options.FindOptions) error {
+	cursor, err := s.db().Collection(col).Find(ctx, filter, opts...)
+	if err != nil {
+		return err
+	}
+	return cursor.All(ctx, results)
+}
+
+func (s *Store) aggregate(ctx context.Context, col string, results interface{}, pipeline bson.A) error {
+	cursor, err := s.db().Collection(col).Aggregate(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	return cursor.All(ctx, results)
+}
+
+// withTransaction runs fn in a multi-document transaction
+func (s *Store) withTransaction(ctx context.Context, fn func(sc mongo.SessionContext) error) error {
+	return s.mongoGet().UseSession(ctx, func(sc mongo.SessionContext) error {
+		_, err := sc.WithTransaction(sc, func(sc mongo.SessionContext) (interface{}, error) {
+			return nil, fn(sc)
+		})
+		return err
+	})
+}
+
+func createIndexes(ctx context.Context, db *mongo.Database) error {
+	ttl := options.Index().SetExpireAfterSeconds(0)
+	indexes := map[string][]mongo.IndexModel{
+		colGlobal: {
+			{Keys: bson.D{{Key: "gid", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_cron_time", Value: 1}}}, // cron job will use this index to query trans
+			{Keys: bson.D{{Key: "notify_status", Value: 1}, {Key: "next_cron_time", Value: 1}}},
+			{Keys: bson.D{{Key: "create_time", Value: 1}}},
+			{Keys: bson.D{{Key: "update_time", Value: 1}}},
+			{Keys: bson.D{{Key: "expire_at", Value: 1}}, Options: ttl},
+		},
+		colBranches: {
+			{Keys: bson.D{{Key: "gid", Value: 1}, {Key: "branch_id", Value: 1}, {Key: "op", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "expire_at", Value: 1}}, Options: ttl},
+		},
+		colEvents: {
+			{Keys: bson.D{{Key: "gid", Value: 1}}},
+			{Keys: bson.D{{Key: "expire_at", Value: 1}}, Options: ttl},
+		},
+	}
+	for col, models := range indexes {
+		_, err := db.Collection(col).Indexes().CreateMany(ctx, models)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close disconnects the mongo client if it is connected
+func (s *Store) Close() error {
+	s.once.Do(func() {}) // the client will not be connected after closed
+	if s.client == nil {
+		return nil
+	}
+	return s.client.Disconnect(context.Background())
+}
+
+func (s *Store) mongoGet() *mongo.Client {
+	s.once.Do(func() {
+		uri := fmt.Sprintf("mongodb://%s:%d/?directConnection=true", s.storeConf.Host, s.storeConf.Port)
+		logger.Debugf("connecting to mongo: %s", uri)
+		opts := options.Client().ApplyURI(uri).SetRegistry(registry)
+		if s.storeConf.User != "" {
+			opts.SetAuth(options.Credential{Username: s.storeConf.User, Password: s.storeConf.Password})
+		}
+		ctx := context.Background() // the client is shared by all the calls
+		c, err := mongo.Connect(ctx, opts)
+		dtmimp.E2P(err)
+		s.client = c
+		err = createIndexes(ctx, c.Database(s.dbName()))
+		if err != nil { // mongo may be not ready, the indexes will be created again in PopulateData
+			logger.Errorf("create mongo indexes error: %v", err)
+		}
+	})
+	return s.client
+}
+
+func (s *Store) db() *mongo.Database {
+	return s.mongoGet().Database(s.dbName())
+}
+
+func (s *Store) dbName() string {
+	return dtmimp.OrString(s.storeConf.Db, "dtm")
+}
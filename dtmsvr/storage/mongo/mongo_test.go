@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package mongo
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/dtm-labs/dtm/dtmsvr/config"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/dtm/dtmsvr/storage/storagetest"
+)
+
+var ctx = context.Background()
+
+// newTestStore returns the store of the local mongo, which is a replica set, the tests are skipped if TEST_STORE is not mongo
+func newTestStore(t *testing.T) *Store {
+	if os.Getenv("TEST_STORE") != config.Mongo {
+		t.Skip("TEST_STORE is not mongo")
+	}
+	return NewStore(&config.Store{Driver: config.Mongo, Host: "localhost", Port: 27017, Db: "dtm_storetest",
+		DataExpire: 100, FinishedDataExpire: 10})
+}
+
+func TestStore(t *testing.T) {
+	newTestStore(t)
+	storagetest.Run(t, func() storage.Store { return newTestStore(t) })
+}
+
+func TestTouchCronTimeExpire(t *testing.T) {
+	s := newTestStore(t)
+	assert.Nil(t, s.PopulateData(ctx, false))
+	now := time.Now()
+	g := &storage.TransGlobalStore{Gid: "gid-expire", TransType: "saga", Status: "submitted", NextCronTime: &now}
+	g.CreateTime = &now
+	branches := []storage.TransBranchStore{{Gid: g.Gid, BranchID: "01", Op: "action", URL: "url1", Status: "prepared"}}
+	assert.Nil(t, s.MaySaveNewTrans(ctx, g, branches))
+	assert.Nil(t, s.SaveTransEvents(ctx, []storage.TransEventStore{{Gid: g.Gid, NewStatus: "submitted"}}))
+
+	s.storeConf.DataExpire = 3600
+	next := now.Add(time.Minute)
+	assert.Nil(t, s.TouchCronTime(ctx, g, 60, &next))
+	for _, col := range []string{colGlobal, colBranches, colEvents} {
+		doc := struct {
+			ExpireAt *time.Time `json:"expire_at"`
+		}{}
+		assert.Nil(t, s.db().Collection(col).FindOne(ctx, bson.M{"gid": g.Gid}).Decode(&doc))
+		assert.True(t, doc.ExpireAt.After(now.Add(time.Hour-time.Minute)), col)
+	}
+}
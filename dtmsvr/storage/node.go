@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// NodeStore is a dtm server sharing the store, which saves its heartbeat periodically
+type NodeStore struct {
+	ID            string     `json:"id"`
+	Version       string     `json:"version"`
+	Load          int64      `json:"load"` // the num of the transactions being processed by the node
+	StartTime     *time.Time `json:"start_time"`
+	HeartbeatTime *time.Time `json:"heartbeat_time"`
+}
+
+// TableName TableName
+func (n *NodeStore) TableName() string {
+	return "dtm_node"
+}
+
+// NodeRegistry is implemented by the stores which keep the heartbeats of the dtm nodes,
+// and record the owner of the trans locked by LockOneGlobalTrans, so that the trans of a dead node can be reclaimed
+type NodeRegistry interface {
+	// SaveNode creates or updates the node
+	SaveNode(ctx context.Context, node *NodeStore) error
+	// FindNodes returns all the nodes
+	FindNodes(ctx context.Context) ([]NodeStore, error)
+	// RemoveNode removes the node
+	RemoveNode(ctx context.Context, id string) error
+	// ReclaimTrans makes the unfinished global trans locked by the node due now, returns the num of the reclaimed trans
+	ReclaimTrans(ctx context.Context, nodeID string) (int64, error)
+}
+
+type lockOwnerKey struct{}
+
+// WithLockOwner returns a context, with which the trans locked by LockOneGlobalTrans are recorded as owned by the node
+func WithLockOwner(ctx context.Context, nodeID string) context.Context {
+	return context.WithValue(ctx, lockOwnerKey{}, nodeID)
+}
+
+// LockOwnerFromContext returns the node set by WithLockOwner, empty if not set
+func LockOwnerFromContext(ctx context.Context) string {
+	nodeID, _ := ctx.Value(lockOwnerKey{}).(string)
+	return nodeID
+}
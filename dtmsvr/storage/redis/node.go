@@ -0,0 +1,91 @@
+/*
+ * Copyright (c) 2022 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+)
+
+// the nodes are kept in the hash prefix_n, and the trans locked by a node are kept in the sorted set prefix_nl_<node>,
+// scored by the time when the lock expires, so that the expired locks can be pruned
+
+// AppendNodeLocks appends the key of the trans locked by the node and the current time, used by luaNodeLocks.
+// nothing is recorded if nodeID is empty
+func (a *argList) AppendNodeLocks(nodeID string) *argList {
+	if nodeID != "" {
+		a.Keys = append(a.Keys, a.prefix+"_nl_"+nodeID)
+	}
+	return a.AppendRaw(time.Now().Unix())
+}
+
+// luaNodeLocks defines the function to record the gid locked until ARGV[4] in KEYS[5], the locks expired before ARGV[6] are pruned
+const luaNodeLocks = `
+local function addNodeLock(gid)
+	if KEYS[5] == nil then
+		return
+	end
+	redis.call('ZREMRANGEBYSCORE', KEYS[5], '-inf', '(' .. ARGV[6])
+	redis.call('ZADD', KEYS[5], ARGV[4], gid)
+	redis.call('EXPIRE', KEYS[5], ARGV[2])
+end`
+
+// SaveNode creates or updates the node
+func (s *Store) SaveNode(ctx context.Context, node *storage.NodeStore) error {
+	return s.redisGet().HSet(ctx, s.storeConf.RedisPrefix+"_n", node.ID, dtmimp.MustMarshalString(node)).Err()
+}
+
+// FindNodes returns all the nodes, sorted by id
+func (s *Store) FindNodes(ctx context.Context) ([]storage.NodeStore, error) {
+	values, err := s.redisGet().HGetAll(ctx, s.storeConf.RedisPrefix+"_n").Result()
+	if err != nil {
+		return nil, err
+	}
+	nodes := []storage.NodeStore{}
+	for _, v := range values {
+		node := storage.NodeStore{}
+		if err := json.Unmarshal([]byte(v), &node); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes, nil
+}
+
+// RemoveNode removes the node and the record of the trans locked by it
+func (s *Store) RemoveNode(ctx context.Context, id string) error {
+	if err := s.redisGet().HDel(ctx, s.storeConf.RedisPrefix+"_n", id).Err(); err != nil {
+		return err
+	}
+	return s.redisGet().Del(ctx, s.storeConf.RedisPrefix+"_nl_"+id).Err()
+}
+
+// ReclaimTrans makes the unfinished global trans locked by the node due now.
+// only the trans still in the cron index, whose locks are not expired, are reclaimed
+func (s *Store) ReclaimTrans(ctx context.Context, nodeID string) (int64, error) {
+	args := s.newArgList().AppendGid("").AppendRaw(time.Now().Unix())
+	args.Keys = append(args.Keys, s.storeConf.RedisPrefix+"_nl_"+nodeID)
+	r, err := s.callLua(ctx, args, `-- ReclaimTrans
+local r = redis.call('ZRANGEBYSCORE', KEYS[5], ARGV[3], '+inf')
+local reclaimed = 0
+for _, gid in ipairs(r) do
+	reclaimed = reclaimed + redis.call('ZADD', KEYS[3], 'XX', 'CH', ARGV[3], gid)
+end
+redis.call('DEL', KEYS[5])
+return tostring(reclaimed)
+`)
+	if err != nil {
+		return 0, err
+	}
+	return int64(dtmimp.MustAtoi(r)), nil
+}
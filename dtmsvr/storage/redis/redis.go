@@ -8,13 +8,17 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 
+	"github.com/dtm-labs/dtm/client/dtmcli"
 	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
 	"github.com/dtm-labs/dtm/dtmsvr/config"
 	"github.com/dtm-labs/dtm/dtmsvr/storage"
@@ -25,100 +29,177 @@ import (
 // TODO: optimize this, it's very strange to use pointer to dtmutil.Config
 var conf = &config.Config
 
-// TODO: optimize this, all function should have context as first parameter
-var ctx = context.Background()
-
 // Store is the storage with redis, all transaction information will bachend with redis
 type Store struct {
+	storeConf *config.Store
+	rdb       *redis.Client
+	once      sync.Once
+}
+
+// NewStore returns the redis store with the conf
+func NewStore(storeConf *config.Store) *Store {
+	return &Store{storeConf: storeConf}
 }
 
 // Ping execs ping cmd to redis
-func (s *Store) Ping() error {
-	_, err := redisGet().Ping(ctx).Result()
+func (s *Store) Ping(ctx context.Context) error {
+	_, err := s.redisGet().Ping(ctx).Result()
 	return err
 }
 
 // PopulateData populates data to redis
-func (s *Store) PopulateData(skipDrop bool) {
+func (s *Store) PopulateData(ctx context.Context, skipDrop bool) error {
 	if !skipDrop {
-		_, err := redisGet().FlushAll(ctx).Result()
+		_, err := s.redisGet().FlushAll(ctx).Result()
 		logger.Infof("call redis flushall. result: %v", err)
-		dtmimp.PanicIf(err != nil, err)
+		return err
 	}
+	return nil
 }
 
 // FindTransGlobalStore finds GlobalTrans data by gid
-func (s *Store) FindTransGlobalStore(gid string) *storage.TransGlobalStore {
+func (s *Store) FindTransGlobalStore(ctx context.Context, gid string) (*storage.TransGlobalStore, error) {
 	logger.Debugf("calling FindTransGlobalStore: %s", gid)
-	r, err := redisGet().Get(ctx, conf.Store.RedisPrefix+"_g_"+gid).Result()
+	r, err := s.redisGet().Get(ctx, s.storeConf.RedisPrefix+"_g_"+gid).Result()
 	if err == redis.Nil {
-		return nil
+		return nil, storage.ErrNotFound
+	} else if err != nil {
+		return nil, err
 	}
-	dtmimp.E2P(err)
 	trans := &storage.TransGlobalStore{}
-	dtmimp.MustUnmarshalString(r, trans)
-	return trans
-}
-
-// ScanTransGlobalStores lists GlobalTrans data
-func (s *Store) ScanTransGlobalStores(position *string, limit int64) []storage.TransGlobalStore {
-	logger.Debugf("calling ScanTransGlobalStores: %s %d", *position, limit)
-	lid := uint64(0)
-	if *position != "" {
-		lid = uint64(dtmimp.MustAtoi(*position))
+	if err := json.Unmarshal([]byte(r), trans); err != nil {
+		return nil, err
 	}
-	keys, cursor, err := redisGet().Scan(ctx, lid, conf.Store.RedisPrefix+"_g_*", limit).Result()
-	dtmimp.E2P(err)
-	globals := []storage.TransGlobalStore{}
-	if len(keys) > 0 {
-		values, err := redisGet().MGet(ctx, keys...).Result()
-		dtmimp.E2P(err)
-		for _, v := range values {
-			global := storage.TransGlobalStore{}
-			dtmimp.MustUnmarshalString(v.(string), &global)
-			globals = append(globals, global)
-		}
-	}
-	if cursor > 0 {
-		*position = fmt.Sprintf("%d", cursor)
-	} else {
-		*position = ""
-	}
-	return globals
+	return trans, nil
 }
 
 // FindBranches finds Branch data by gid
-func (s *Store) FindBranches(gid string) []storage.TransBranchStore {
+func (s *Store) FindBranches(ctx context.Context, gid string) ([]storage.TransBranchStore, error) {
 	logger.Debugf("calling FindBranches: %s", gid)
-	sa, err := redisGet().LRange(ctx, conf.Store.RedisPrefix+"_b_"+gid, 0, -1).Result()
-	dtmimp.E2P(err)
+	sa, err := s.redisGet().LRange(ctx, s.storeConf.RedisPrefix+"_b_"+gid, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
 	branches := make([]storage.TransBranchStore, len(sa))
 	for k, v := range sa {
-		dtmimp.MustUnmarshalString(v, &branches[k])
+		if err := json.Unmarshal([]byte(v), &branches[k]); err != nil {
+			return nil, err
+		}
 	}
-	return branches
+	return branches, nil
 }
 
-// UpdateBranches updates branches info
-func (s *Store) UpdateBranches(branches []storage.TransBranchStore, updates []string) (int, error) {
-	return 0, nil // not implemented
+// UpdateBranches updates the columns of the branches matched by branch_id and op, the unmatched branches are appended.
+// the branches of a global trans are updated by a lua script, and the scripts are sent in a pipeline
+func (s *Store) UpdateBranches(ctx context.Context, branches []storage.TransBranchStore, updates []string) (int, error) {
+	now := time.Now()
+	gids := []string{}
+	groups := map[string][]storage.TransBranchStore{}
+	for _, b := range branches {
+		if _, ok := groups[b.Gid]; !ok {
+			gids = append(gids, b.Gid)
+		}
+		b.UpdateTime = &now
+		groups[b.Gid] = append(groups[b.Gid], b)
+	}
+	cmds, err := s.redisGet().Pipelined(ctx, func(p redis.Pipeliner) error {
+		for _, gid := range gids {
+			a := s.newArgList().
+				AppendGid(gid).
+				AppendStats().
+				AppendObject(updates).
+				AppendBranches(groups[gid])
+			logger.Debugf("calling lua. args: %v\nlua:%s", a, luaUpdateBranches)
+			p.Eval(ctx, luaUpdateBranches, a.Keys, a.List...)
+		}
+		return nil
+	})
+	if err != nil && err != redis.Nil {
+		return 0, err
+	}
+	affected := 0
+	for _, cmd := range cmds {
+		r, err := handleRedisResult(cmd.(*redis.Cmd).Result())
+		if err != nil {
+			return affected, err
+		}
+		affected += dtmimp.MustAtoi(r)
+	}
+	return affected, nil
 }
 
+// luaUpdateBranches updates the branches of a global trans.
+// the failing urls are counted only for the unfinished global trans, like the other scripts
+const luaUpdateBranches = `-- UpdateBranches` + luaFailingURL + `
+local status = redis.call('GET', KEYS[4])
+if status == false then
+	return '0'
+end
+local unfinished = status ~= 'succeed' and status ~= 'failed'
+local columns = cjson.decode(ARGV[3])
+local bs = redis.call('LRANGE', KEYS[2], 0, -1)
+local created = table.getn(bs) == 0
+local affected = 0
+for k = 4, table.getn(ARGV) do
+	local b = cjson.decode(ARGV[k])
+	local found = false
+	for i = 1, table.getn(bs) do
+		local c = cjson.decode(bs[i])
+		if c['branch_id'] == b['branch_id'] and c['op'] == b['op'] then
+			if unfinished then
+				incrFailing(c, -1)
+			end
+			for _, column in ipairs(columns) do
+				c[column] = b[column]
+			end
+			if unfinished then
+				incrFailing(c, 1)
+			end
+			bs[i] = cjson.encode(c)
+			redis.call('LSET', KEYS[2], i-1, bs[i])
+			found = true
+			break
+		end
+	end
+	if not found then
+		table.insert(bs, ARGV[k])
+		redis.call('RPUSH', KEYS[2], ARGV[k])
+		if unfinished then
+			incrFailing(b, 1)
+		end
+	end
+	affected = affected + 1
+end
+if created then
+	redis.call('EXPIRE', KEYS[2], ARGV[2])
+end
+return tostring(affected)
+`
+
 type argList struct {
-	Keys []string      // 1 global trans, 2 branches, 3 indices, 4 status
-	List []interface{} // 1 redis prefix, 2 data expire
+	Keys   []string      // 1 global trans, 2 branches, 3 indices, 4 status, 5 stats of counts, 6 stats of failing urls, 7 stats of unfinished, then the indexes for scan if appended
+	List   []interface{} // 1 redis prefix, 2 data expire
+	prefix string
 }
 
-func newArgList() *argList {
-	a := &argList{}
-	return a.AppendRaw(conf.Store.RedisPrefix).AppendObject(conf.Store.DataExpire)
+func (s *Store) newArgList() *argList {
+	a := &argList{prefix: s.storeConf.RedisPrefix}
+	return a.AppendRaw(s.storeConf.RedisPrefix).AppendObject(s.storeConf.DataExpire)
 }
 
 func (a *argList) AppendGid(gid string) *argList {
-	a.Keys = append(a.Keys, conf.Store.RedisPrefix+"_g_"+gid)
-	a.Keys = append(a.Keys, conf.Store.RedisPrefix+"_b_"+gid)
-	a.Keys = append(a.Keys, conf.Store.RedisPrefix+"_u")
-	a.Keys = append(a.Keys, conf.Store.RedisPrefix+"_s_"+gid)
+	a.Keys = append(a.Keys, a.prefix+"_g_"+gid)
+	a.Keys = append(a.Keys, a.prefix+"_b_"+gid)
+	a.Keys = append(a.Keys, a.prefix+"_u")
+	a.Keys = append(a.Keys, a.prefix+"_s_"+gid)
+	return a
+}
+
+// AppendStats appends the keys of the statistics, which are maintained by the lua scripts
+func (a *argList) AppendStats() *argList {
+	a.Keys = append(a.Keys, a.prefix+"_sc")
+	a.Keys = append(a.Keys, a.prefix+"_sf")
+	a.Keys = append(a.Keys, a.prefix+"_so")
 	return a
 }
 
@@ -151,48 +232,78 @@ func handleRedisResult(ret interface{}, err error) (string, error) {
 	return s, err
 }
 
-func callLua(a *argList, lua string) (string, error) {
+func (s *Store) callLua(ctx context.Context, a *argList, lua string) (string, error) {
 	logger.Debugf("calling lua. args: %v\nlua:%s", a, lua)
-	ret, err := redisGet().Eval(ctx, lua, a.Keys, a.List...).Result()
+	ret, err := s.redisGet().Eval(ctx, lua, a.Keys, a.List...).Result()
 	return handleRedisResult(ret, err)
 }
 
 // MaySaveNewTrans creates a new trans
-func (s *Store) MaySaveNewTrans(global *storage.TransGlobalStore, branches []storage.TransBranchStore) error {
-	a := newArgList().
+func (s *Store) MaySaveNewTrans(ctx context.Context, global *storage.TransGlobalStore, branches []storage.TransBranchStore) error {
+	// a finished trans may be saved, such as by migration
+	inCron := !global.IsFinished() || global.NotifyStatus == dtmcli.StatusPrepared
+	expire := s.storeConf.DataExpire
+	if !inCron {
+		expire = s.storeConf.FinishedDataExpire
+	}
+	createTime := time.Now()
+	if global.CreateTime != nil {
+		createTime = *global.CreateTime
+	}
+	a := s.newArgList().
 		AppendGid(global.Gid).
+		AppendStats().
+		AppendIndexes().
 		AppendObject(global).
 		AppendRaw(global.NextCronTime.Unix()).
 		AppendRaw(global.Gid).
 		AppendRaw(global.Status).
+		AppendRaw(global.TransType).
+		AppendRaw(createTime.Unix()).
+		AppendObject(expire).
+		AppendRaw(inCron).
+		AppendRaw(!global.IsFinished()).
+		AppendRaw(timeScore(&createTime)).
+		AppendRaw(timeScore(global.UpdateTime)).
 		AppendBranches(branches)
 	global.Steps = nil
 	global.Payloads = nil
-	_, err := callLua(a, `-- MaySaveNewTrans
+	_, err := s.callLua(ctx, a, `-- MaySaveNewTrans`+luaFailingURL+luaIndexes+`
 local g = redis.call('GET', KEYS[1])
 if g ~= false then
 	return 'UNIQUE_CONFLICT'
 end
 
-redis.call('SET', KEYS[1], ARGV[3], 'EX', ARGV[2])
-redis.call('SET', KEYS[4], ARGV[6], 'EX', ARGV[2])
-redis.call('ZADD', KEYS[3], ARGV[4], ARGV[5])
-for k = 7, table.getn(ARGV) do
+redis.call('SET', KEYS[1], ARGV[3], 'EX', ARGV[9])
+redis.call('SET', KEYS[4], ARGV[6], 'EX', ARGV[9])
+if ARGV[10] == '1' then
+	redis.call('ZADD', KEYS[3], ARGV[4], ARGV[5])
+end
+redis.call('HINCRBY', KEYS[5], ARGV[6] .. '|' .. ARGV[7], 1)
+if ARGV[11] == '1' then
+	redis.call('ZADD', KEYS[7], ARGV[8], ARGV[5])
+end
+indexGlobal(KEYS[8], KEYS[9], ARGV[5], ARGV[12], ARGV[13])
+for k = 14, table.getn(ARGV) do
 	redis.call('RPUSH', KEYS[2], ARGV[k])
+	if ARGV[11] == '1' then
+		incrFailing(cjson.decode(ARGV[k]), 1)
+	end
 end
-redis.call('EXPIRE', KEYS[2], ARGV[2])
+redis.call('EXPIRE', KEYS[2], ARGV[9])
 `)
 	return err
 }
 
 // LockGlobalSaveBranches creates branches
-func (s *Store) LockGlobalSaveBranches(gid string, status string, branches []storage.TransBranchStore, branchStart int) {
-	args := newArgList().
+func (s *Store) LockGlobalSaveBranches(ctx context.Context, gid string, status string, branches []storage.TransBranchStore, branchStart int) error {
+	args := s.newArgList().
 		AppendGid(gid).
+		AppendStats().
 		AppendRaw(status).
 		AppendRaw(branchStart).
 		AppendBranches(branches)
-	_, err := callLua(args, `-- LockGlobalSaveBranches
+	_, err := s.callLua(ctx, args, `-- LockGlobalSaveBranches`+luaFailingURL+`
 local old = redis.call('GET', KEYS[4])
 if old ~= ARGV[3] then
 	return 'NOT_FOUND'
@@ -213,33 +324,52 @@ for k = 5, table.getn(ARGV) do
 	if start == "-1" then
 		redis.call('RPUSH', KEYS[2], ARGV[k])
 	else
+		incrFailing(cjson.decode(redis.call('LINDEX', KEYS[2], start+k-5)), -1)
+		incrFailing(cjson.decode(ARGV[k]), 1)
 		redis.call('LSET', KEYS[2], start+k-5, ARGV[k])
 	end
 end
 redis.call('EXPIRE', KEYS[2], ARGV[2])
 	`)
-	dtmimp.E2P(err)
+	return err
 }
 
 // ChangeGlobalStatus changes global trans status
-func (s *Store) ChangeGlobalStatus(global *storage.TransGlobalStore, newStatus string, updates []string, finished bool) {
+func (s *Store) ChangeGlobalStatus(ctx context.Context, global *storage.TransGlobalStore, newStatus string, updates []string, finished bool) error {
 	old := global.Status
 	global.Status = newStatus
-	args := newArgList().
+	args := s.newArgList().
 		AppendGid(global.Gid).
+		AppendStats().
+		AppendIndexes().
 		AppendObject(global).
 		AppendRaw(old).
 		AppendRaw(finished).
 		AppendRaw(global.Gid).
 		AppendRaw(newStatus).
-		AppendObject(conf.Store.FinishedDataExpire)
-	_, err := callLua(args, `-- ChangeGlobalStatus
+		AppendObject(s.storeConf.FinishedDataExpire).
+		AppendRaw(global.TransType).
+		AppendRaw(global.IsFinished()).
+		AppendRaw(timeScore(global.UpdateTime))
+	_, err := s.callLua(ctx, args, `-- ChangeGlobalStatus`+luaFailingURL+luaIndexes+`
 local old = redis.call('GET', KEYS[4])
 if old ~= ARGV[4] then
   return 'NOT_FOUND'
 end
+if ARGV[4] ~= ARGV[7] then
+	redis.call('HINCRBY', KEYS[5], ARGV[4] .. '|' .. ARGV[9], -1)
+	redis.call('HINCRBY', KEYS[5], ARGV[7] .. '|' .. ARGV[9], 1)
+	if ARGV[10] == '1' then
+		redis.call('ZREM', KEYS[7], ARGV[6])
+		local bs = redis.call('LRANGE', KEYS[2], 0, -1)
+		for i = 1, table.getn(bs) do
+			incrFailing(cjson.decode(bs[i]), -1)
+		end
+	end
+end
 redis.call('SET', KEYS[1],  ARGV[3], 'EX', ARGV[2])
 redis.call('SET', KEYS[4],  ARGV[7], 'EX', ARGV[2])
+indexGlobal(KEYS[8], KEYS[9], ARGV[6], false, ARGV[11])
 if ARGV[5] == '1' then
 	redis.call('ZREM', KEYS[3], ARGV[6])
 	redis.call('EXPIRE', KEYS[1], ARGV[8])
@@ -247,15 +377,16 @@ if ARGV[5] == '1' then
 	redis.call('EXPIRE', KEYS[4], ARGV[8])
 end
 `)
-	dtmimp.E2P(err)
+	return err
 }
 
 // LockOneGlobalTrans finds GlobalTrans
-func (s *Store) LockOneGlobalTrans(expireIn time.Duration) *storage.TransGlobalStore {
+func (s *Store) LockOneGlobalTrans(ctx context.Context, expireIn time.Duration) (*storage.TransGlobalStore, error) {
 	expired := time.Now().Add(expireIn).Unix()
 	next := time.Now().Add(time.Duration(conf.RetryInterval) * time.Second).Unix()
-	args := newArgList().AppendGid("").AppendRaw(expired).AppendRaw(next)
-	lua := `-- LockOneGlobalTrans
+	args := s.newArgList().AppendGid("").AppendRaw(expired).AppendRaw(next).AppendRaw(0).
+		AppendNodeLocks(storage.LockOwnerFromContext(ctx))
+	lua := `-- LockOneGlobalTrans` + luaNodeLocks + `
 local r = redis.call('ZRANGE', KEYS[3], 0, 0, 'WITHSCORES')
 local gid = r[1]
 if gid == nil then
@@ -266,27 +397,76 @@ if tonumber(r[2]) > tonumber(ARGV[3]) then
 	return 'NOT_FOUND'
 end
 redis.call('ZADD', KEYS[3], ARGV[4], gid)
+addNodeLock(gid)
 return gid
 `
 	for {
-		r, err := callLua(args, lua)
+		r, err := s.callLua(ctx, args, lua)
 		if errors.Is(err, storage.ErrNotFound) {
-			return nil
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		}
+		global, err := s.FindTransGlobalStore(ctx, r)
+		if err != storage.ErrNotFound {
+			return global, err
 		}
-		dtmimp.E2P(err)
-		global := s.FindTransGlobalStore(r)
-		if global != nil {
-			return global
+	}
+}
+
+// LockGlobalTransBatch locks at most limit due global trans by moving them forward in the cron index in one script,
+// then gets them in one MGET. the trans expired in redis are skipped
+func (s *Store) LockGlobalTransBatch(ctx context.Context, expireIn time.Duration, limit int) ([]storage.TransGlobalStore, error) {
+	expired := time.Now().Add(expireIn).Unix()
+	next := time.Now().Add(time.Duration(conf.RetryInterval) * time.Second).Unix()
+	args := s.newArgList().AppendGid("").AppendRaw(expired).AppendRaw(next).AppendRaw(limit).
+		AppendNodeLocks(storage.LockOwnerFromContext(ctx))
+	lua := `-- LockGlobalTransBatch` + luaNodeLocks + `
+local r = redis.call('ZRANGEBYSCORE', KEYS[3], '-inf', ARGV[3], 'LIMIT', 0, ARGV[5])
+if #r == 0 then
+	return 'NOT_FOUND'
+end
+for _, gid in ipairs(r) do
+	redis.call('ZADD', KEYS[3], ARGV[4], gid)
+	addNodeLock(gid)
+end
+return cjson.encode(r)
+`
+	globals := []storage.TransGlobalStore{}
+	r, err := s.callLua(ctx, args, lua)
+	if errors.Is(err, storage.ErrNotFound) {
+		return globals, nil
+	} else if err != nil {
+		return globals, err
+	}
+	gids := []string{}
+	dtmimp.MustUnmarshalString(r, &gids)
+	keys := []string{}
+	for _, gid := range gids {
+		keys = append(keys, s.storeConf.RedisPrefix+"_g_"+gid)
+	}
+	values, err := s.redisGet().MGet(ctx, keys...).Result()
+	if err != nil {
+		return globals, err
+	}
+	for _, v := range values {
+		if str, ok := v.(string); ok {
+			global := storage.TransGlobalStore{}
+			if err := json.Unmarshal([]byte(str), &global); err != nil {
+				return globals, err
+			}
+			globals = append(globals, global)
 		}
 	}
+	return globals, nil
 }
 
 // ResetCronTime reset nextCronTime
 // unfinished transactions need to be retried as soon as possible after business downtime is recovered
-func (s *Store) ResetCronTime(after time.Duration, limit int64) (succeedCount int64, hasRemaining bool, err error) {
+func (s *Store) ResetCronTime(ctx context.Context, after time.Duration, limit int64) (succeedCount int64, hasRemaining bool, err error) {
 	next := time.Now().Unix()
 	timeoutTimestamp := time.Now().Add(after).Unix()
-	args := newArgList().AppendGid("").AppendRaw(timeoutTimestamp).AppendRaw(next).AppendRaw(limit)
+	args := s.newArgList().AppendGid("").AppendRaw(timeoutTimestamp).AppendRaw(next).AppendRaw(limit)
 	lua := `-- ResetCronTime
 local r = redis.call('ZRANGEBYSCORE', KEYS[3], ARGV[3], '+inf', 'LIMIT', 0, ARGV[5]+1)
 local i = 0
@@ -301,8 +481,10 @@ end
 return tostring(i)
 `
 	r := ""
-	r, err = callLua(args, lua)
-	dtmimp.E2P(err)
+	r, err = s.callLua(ctx, args, lua)
+	if err != nil {
+		return
+	}
 	succeedCount = int64(dtmimp.MustAtoi(r))
 	if succeedCount > limit {
 		hasRemaining = true
@@ -312,40 +494,185 @@ return tostring(i)
 }
 
 // TouchCronTime updates cronTime
-func (s *Store) TouchCronTime(global *storage.TransGlobalStore, nextCronInterval int64, nextCronTime *time.Time) {
+func (s *Store) TouchCronTime(ctx context.Context, global *storage.TransGlobalStore, nextCronInterval int64, nextCronTime *time.Time) error {
 	global.UpdateTime = dtmutil.GetNextTime(0)
 	global.NextCronTime = nextCronTime
 	global.NextCronInterval = nextCronInterval
-	args := newArgList().
+	args := s.newArgList().
 		AppendGid(global.Gid).
+		AppendIndexes().
 		AppendObject(global).
 		AppendRaw(global.NextCronTime.Unix()).
 		AppendRaw(global.Status).
-		AppendRaw(global.Gid)
-	_, err := callLua(args, `-- TouchCronTime
+		AppendRaw(global.Gid).
+		AppendRaw(timeScore(global.UpdateTime))
+	_, err := s.callLua(ctx, args, `-- TouchCronTime`+luaIndexes+`
 local old = redis.call('GET', KEYS[4])
 if old ~= ARGV[5] then
 	return 'NOT_FOUND'
 end
 redis.call('ZADD', KEYS[3], ARGV[4], ARGV[6])
 redis.call('SET', KEYS[1], ARGV[3], 'EX', ARGV[2])
+indexGlobal(KEYS[5], KEYS[6], ARGV[6], false, ARGV[7])
 	`)
-	dtmimp.E2P(err)
+	return err
 }
 
-var (
-	rdb  *redis.Client
-	once sync.Once
-)
+// SaveTransEvents saves the status transitions
+func (s *Store) SaveTransEvents(ctx context.Context, events []storage.TransEventStore) error {
+	if len(events) == 0 {
+		return nil
+	}
+	_, err := s.redisGet().Pipelined(ctx, func(p redis.Pipeliner) error {
+		for _, e := range events {
+			key := s.storeConf.RedisPrefix + "_e_" + e.Gid
+			p.RPush(ctx, key, dtmimp.MustMarshalString(e))
+			p.Expire(ctx, key, time.Duration(s.storeConf.DataExpire)*time.Second)
+		}
+		return nil
+	})
+	return err
+}
+
+// FindTransEvents finds the status transitions by gid, in the order of occurrence
+func (s *Store) FindTransEvents(ctx context.Context, gid string) ([]storage.TransEventStore, error) {
+	sa, err := s.redisGet().LRange(ctx, s.storeConf.RedisPrefix+"_e_"+gid, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	events := make([]storage.TransEventStore, len(sa))
+	for k, v := range sa {
+		if err := json.Unmarshal([]byte(v), &events[k]); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+// luaFailingURL defines the functions to maintain the number of failing branches by url in KEYS[6]
+const luaFailingURL = `
+local function incrFailing(b, delta)
+	if b['status'] ~= 'prepared' or b['last_error'] == nil or b['last_error'] == '' then
+		return
+	end
+	if redis.call('HINCRBY', KEYS[6], b['url'], delta) <= 0 then
+		redis.call('HDEL', KEYS[6], b['url'])
+	end
+end`
+
+// GetTransStats gets the statistics of the transactions.
+// the unfinished ones are counted from prefix_so, whose members expired by redis are pruned.
+// the finished ones are counted by the lua scripts, they are lifetime counters including the expired transactions
+func (s *Store) GetTransStats(ctx context.Context, topURLs int) (*storage.TransStats, error) {
+	stats := &storage.TransStats{Counts: []storage.TransCount{}}
+	counts, err := s.redisGet().HGetAll(ctx, s.storeConf.RedisPrefix+"_sc").Result()
+	if err != nil {
+		return nil, err
+	}
+	unfinishedStatus := map[string]bool{}
+	for _, status := range storage.UnfinishedStatuses {
+		unfinishedStatus[status] = true
+	}
+	for k, v := range counts {
+		count := int64(dtmimp.MustAtoi(v))
+		ks := strings.SplitN(k, "|", 2)
+		if count <= 0 || unfinishedStatus[ks[0]] {
+			continue
+		}
+		stats.Counts = append(stats.Counts, storage.TransCount{Status: ks[0], TransType: ks[1], Count: count})
+	}
+	unfinished, err := s.countUnfinished(ctx, stats)
+	if err != nil {
+		return nil, err
+	}
+	for k, count := range unfinished {
+		stats.Counts = append(stats.Counts, storage.TransCount{Status: k[0], TransType: k[1], Count: count})
+	}
+	sort.Slice(stats.Counts, func(i, j int) bool {
+		ci, cj := stats.Counts[i], stats.Counts[j]
+		return ci.Status < cj.Status || ci.Status == cj.Status && ci.TransType < cj.TransType
+	})
+	stats.CronLagCount, err = s.redisGet().ZCount(ctx, s.storeConf.RedisPrefix+"_u", "-inf", fmt.Sprintf("(%d", time.Now().Unix())).Result()
+	if err != nil {
+		return nil, err
+	}
+	failing, err := s.redisGet().HGetAll(ctx, s.storeConf.RedisPrefix+"_sf").Result()
+	if err != nil {
+		return nil, err
+	}
+	urls := map[string]int64{}
+	for k, v := range failing {
+		urls[k] = int64(dtmimp.MustAtoi(v))
+	}
+	stats.FailingURLs = storage.TopURLCounts(urls, topURLs)
+	return stats, nil
+}
+
+// countUnfinished counts the unfinished transactions in prefix_so by status and trans_type, and sets the oldest create time of them.
+// the members whose transaction is expired by redis are removed
+func (s *Store) countUnfinished(ctx context.Context, stats *storage.TransStats) (map[[2]string]int64, error) {
+	counts := map[[2]string]int64{}
+	members, err := s.redisGet().ZRangeWithScores(ctx, s.storeConf.RedisPrefix+"_so", 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	for len(members) > 0 {
+		n := len(members)
+		if n > 1000 {
+			n = 1000
+		}
+		batch := members[:n]
+		members = members[n:]
+		keys := []string{}
+		for _, m := range batch {
+			keys = append(keys, s.storeConf.RedisPrefix+"_g_"+m.Member.(string))
+		}
+		values, err := s.redisGet().MGet(ctx, keys...).Result()
+		if err != nil {
+			return nil, err
+		}
+		expired := []interface{}{}
+		for i, v := range values {
+			if v == nil {
+				expired = append(expired, batch[i].Member)
+				continue
+			}
+			global := storage.TransGlobalStore{}
+			if err := json.Unmarshal([]byte(v.(string)), &global); err != nil {
+				return nil, err
+			}
+			counts[[2]string{global.Status, global.TransType}]++
+			if stats.OldestUnfinishedTime == nil {
+				t := time.Unix(int64(batch[i].Score), 0)
+				stats.OldestUnfinishedTime = &t
+			}
+		}
+		if len(expired) > 0 {
+			if err := s.redisGet().ZRem(ctx, s.storeConf.RedisPrefix+"_so", expired...).Err(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return counts, nil
+}
+
+// Close closes the redis client if it is connected
+func (s *Store) Close() error {
+	s.once.Do(func() {}) // the client will not be connected after closed
+	if s.rdb == nil {
+		return nil
+	}
+	return s.rdb.Close()
+}
 
-func redisGet() *redis.Client {
-	once.Do(func() {
-		logger.Debugf("connecting to redis: %v", conf.Store)
-		rdb = redis.NewClient(&redis.Options{
-			Addr:     fmt.Sprintf("%s:%d", conf.Store.Host, conf.Store.Port),
-			Username: conf.Store.User,
-			Password: conf.Store.Password,
+func (s *Store) redisGet() *redis.Client {
+	s.once.Do(func() {
+		logger.Debugf("connecting to redis: %v", s.storeConf)
+		s.rdb = redis.NewClient(&redis.Options{
+			Addr:     fmt.Sprintf("%s:%d", s.storeConf.Host, s.storeConf.Port),
+			Username: s.storeConf.User,
+			Password: s.storeConf.Password,
 		})
 	})
-	return rdb
+	return s.rdb
 }
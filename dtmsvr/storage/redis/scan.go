@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2022 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/logger"
+	"github.com/go-redis/redis/v8"
+)
+
+// the global trans are indexed by create_time in the sorted set prefix_ic, and by update_time in prefix_iu.
+// the scores are in milliseconds, the trans in the same millisecond are sorted in memory.
+// the trans saved by the older versions are not indexed until they are updated
+
+// AppendIndexes appends the keys of the indexes for ScanTransGlobalStores, used by luaIndexes
+func (a *argList) AppendIndexes() *argList {
+	a.Keys = append(a.Keys, a.prefix+"_ic")
+	a.Keys = append(a.Keys, a.prefix+"_iu")
+	return a
+}
+
+// timeScore returns the score of t in the indexes
+func timeScore(t *time.Time) int64 {
+	if t == nil {
+		t = &time.Time{}
+	}
+	return t.Unix()*1000 + int64(t.Nanosecond()/int(time.Millisecond))
+}
+
+// luaIndexes defines the function to index gid by the scores in the keys ic and iu, the create time is not indexed if it is false.
+// the two oldest updated trans are checked, and removed from the indexes if they are expired, so the indexes will not grow forever
+const luaIndexes = `
+local function indexGlobal(ic, iu, gid, createScore, updateScore)
+	if createScore then
+		redis.call('ZADD', ic, createScore, gid)
+	end
+	redis.call('ZADD', iu, updateScore, gid)
+	for _, old in ipairs(redis.call('ZRANGE', iu, 0, 1)) do
+		if redis.call('EXISTS', ARGV[1] .. '_g_' .. old) == 0 then
+			redis.call('ZREM', ic, old)
+			redis.call('ZREM', iu, old)
+		end
+	end
+end`
+
+// ScanTransGlobalStores lists GlobalTrans data.
+// the trans are read from the index of the sort field, starting at the position, until the page is full
+func (s *Store) ScanTransGlobalStores(ctx context.Context, position *string, limit int64, condition storage.TransGlobalScanCondition) ([]storage.TransGlobalStore, error) {
+	logger.Debugf("calling ScanTransGlobalStores: %s %d %v", *position, limit, condition)
+	key := s.storeConf.RedisPrefix + "_ic"
+	if condition.GetSortBy() == storage.SortByUpdateTime {
+		key = s.storeConf.RedisPrefix + "_iu"
+	}
+	rangeBy := redis.ZRangeBy{Min: "-inf", Max: "+inf", Count: 1000}
+	var posTime time.Time
+	var posGid string
+	if *position != "" {
+		var err error
+		posTime, posGid, err = storage.DecodePosition(*position)
+		if err != nil {
+			return nil, err
+		}
+		if condition.SortAsc {
+			rangeBy.Min = strconv.FormatInt(timeScore(&posTime), 10)
+		} else {
+			rangeBy.Max = strconv.FormatInt(timeScore(&posTime), 10)
+		}
+	}
+	now := time.Now()
+	// the candidates are read until the page is full, and all the trans in the same millisecond as the last one are read
+	candidates := []storage.TransGlobalStore{}
+	expired := []interface{}{}
+	var lastScore float64
+	full := false
+	for done := false; !done; {
+		var zs []redis.Z
+		var err error
+		if condition.SortAsc {
+			zs, err = s.redisGet().ZRangeByScoreWithScores(ctx, key, &rangeBy).Result()
+		} else {
+			zs, err = s.redisGet().ZRevRangeByScoreWithScores(ctx, key, &rangeBy).Result()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(zs) == 0 {
+			break
+		}
+		done = int64(len(zs)) < rangeBy.Count
+		rangeBy.Offset += int64(len(zs))
+		keys := []string{}
+		for _, z := range zs {
+			keys = append(keys, s.storeConf.RedisPrefix+"_g_"+z.Member.(string))
+		}
+		values, err := s.redisGet().MGet(ctx, keys...).Result()
+		if err != nil {
+			return nil, err
+		}
+		for i, v := range values {
+			if full && zs[i].Score != lastScore {
+				done = true
+				break
+			}
+			if v == nil { // expired by redis
+				expired = append(expired, zs[i].Member)
+				continue
+			}
+			global := storage.TransGlobalStore{}
+			if err := json.Unmarshal([]byte(v.(string)), &global); err != nil {
+				return nil, err
+			}
+			if condition.Match(&global, now) && (*position == "" || condition.After(&global, posTime, posGid)) {
+				candidates = append(candidates, global)
+				full = int64(len(candidates)) >= limit
+				lastScore = zs[i].Score
+			}
+		}
+	}
+	if len(expired) > 0 {
+		if err := s.redisGet().ZRem(ctx, s.storeConf.RedisPrefix+"_ic", expired...).Err(); err != nil {
+			return nil, err
+		}
+		if err := s.redisGet().ZRem(ctx, s.storeConf.RedisPrefix+"_iu", expired...).Err(); err != nil {
+			return nil, err
+		}
+	}
+	return condition.FilterSortPage(candidates, position, limit)
+}
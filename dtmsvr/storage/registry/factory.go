@@ -3,6 +3,7 @@ package registry
 import (
 	"sync"
 
+	"github.com/dtm-labs/dtm/dtmsvr/config"
 	"github.com/dtm-labs/dtm/dtmsvr/storage"
 )
 
@@ -15,6 +16,11 @@ type SingletonFactory struct {
 	creatorFunction func() storage.Store
 }
 
+// NewSingletonFactory returns a factory which creates the store by creator at the first call of GetStorage
+func NewSingletonFactory(creator func() storage.Store) *SingletonFactory {
+	return &SingletonFactory{creatorFunction: creator}
+}
+
 // GetStorage implement the StorageFactory.GetStorage
 func (f *SingletonFactory) GetStorage() storage.Store {
 	f.once.Do(func() {
@@ -23,3 +29,33 @@ func (f *SingletonFactory) GetStorage() storage.Store {
 
 	return f.store
 }
+
+// DriverFactory is the factory of a store driver. it creates the store of the global config in SINGLETON pattern,
+// and creates new stores with the given configs for NewStore
+type DriverFactory struct {
+	SingletonFactory
+	creator func(storeConf *config.Store) storage.Store
+	checker config.StoreChecker
+}
+
+// NewDriverFactory returns a DriverFactory which creates the stores by creator, and checks the store config by checker, which can be nil
+func NewDriverFactory(creator func(storeConf *config.Store) storage.Store, checker config.StoreChecker) *DriverFactory {
+	f := &DriverFactory{creator: creator, checker: checker}
+	f.creatorFunction = func() storage.Store {
+		return creator(&conf.Store)
+	}
+	return f
+}
+
+// NewStore implement the StoreCreator.NewStore
+func (f *DriverFactory) NewStore(storeConf *config.Store) storage.Store {
+	return f.creator(storeConf)
+}
+
+// CheckConfig implement the ConfigChecker.CheckConfig
+func (f *DriverFactory) CheckConfig(storeConf *config.Store) error {
+	if f.checker == nil {
+		return nil
+	}
+	return f.checker(storeConf)
+}
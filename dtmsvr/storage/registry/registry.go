@@ -1,6 +1,8 @@
 package registry
 
 import (
+	"context"
+	"fmt"
 	"time"
 
 	"github.com/dtm-labs/logger"
@@ -8,7 +10,10 @@ import (
 	"github.com/dtm-labs/dtm/dtmsvr/config"
 	"github.com/dtm-labs/dtm/dtmsvr/storage"
 	"github.com/dtm-labs/dtm/dtmsvr/storage/boltdb"
+	"github.com/dtm-labs/dtm/dtmsvr/storage/memory"
+	"github.com/dtm-labs/dtm/dtmsvr/storage/mongo"
 	"github.com/dtm-labs/dtm/dtmsvr/storage/redis"
+	"github.com/dtm-labs/dtm/dtmsvr/storage/sharded"
 	"github.com/dtm-labs/dtm/dtmsvr/storage/sql"
 )
 
@@ -20,25 +25,92 @@ type StorageFactory interface {
 	GetStorage() storage.Store
 }
 
-var sqlFac = &SingletonFactory{
-	creatorFunction: func() storage.Store {
-		return &sql.Store{}
-	},
+// ConfigChecker can be implemented by a StorageFactory to check the store config of its driver
+type ConfigChecker interface {
+	CheckConfig(storeConf *config.Store) error
 }
 
-var storeFactorys = map[string]StorageFactory{
-	"boltdb": &SingletonFactory{
-		creatorFunction: func() storage.Store {
-			return boltdb.NewStore(conf.Store.DataExpire, conf.RetryInterval)
-		},
-	},
-	"redis": &SingletonFactory{
-		creatorFunction: func() storage.Store {
-			return &redis.Store{}
-		},
-	},
-	"mysql":    sqlFac,
-	"postgres": sqlFac,
+// StoreCreator can be implemented by a StorageFactory to create new stores with the given configs, which is used by NewStore
+type StoreCreator interface {
+	NewStore(storeConf *config.Store) storage.Store
+}
+
+var storeFactorys = map[string]StorageFactory{}
+
+func init() {
+	Register(config.BoltDb, NewDriverFactory(newBoltDbStore, config.CheckBoltDb))
+	Register(config.Memory, NewDriverFactory(func(storeConf *config.Store) storage.Store {
+		return memory.NewStore(storeConf.DataExpire, storeConf.FinishedDataExpire, conf.RetryInterval)
+	}, nil))
+	Register(config.Redis, NewDriverFactory(func(storeConf *config.Store) storage.Store {
+		return redis.NewStore(storeConf)
+	}, config.CheckRedis))
+	Register(config.Mongo, NewDriverFactory(func(storeConf *config.Store) storage.Store {
+		return mongo.NewStore(storeConf)
+	}, config.CheckMongo))
+	Register(config.Mysql, NewDriverFactory(newSQLStore, config.CheckDB))
+	Register(config.Postgres, NewDriverFactory(newSQLStore, config.CheckDB))
+}
+
+// Register registers the factory of a store driver, then the driver can be used by setting Store.Driver to name.
+// a third-party driver can be linked in with a blank import of the package that calls Register in its init.
+// the store config is checked by the factory if it implements ConfigChecker.
+// it panics if the name is registered twice, like database/sql.Register
+func Register(name string, factory StorageFactory) {
+	if factory == nil {
+		panic("registry: Register factory is nil")
+	}
+	if _, dup := storeFactorys[name]; dup {
+		panic("registry: Register called twice for driver " + name)
+	}
+	var checker config.StoreChecker
+	if c, ok := factory.(ConfigChecker); ok {
+		checker = c.CheckConfig
+	}
+	config.RegisterStoreDriver(name, checker)
+	storeFactorys[name] = factory
+}
+
+// NewStore creates a new store with the storeConf by the registered factory, so that several stores can be used at the same time.
+// the settings not in storeConf, such as RetryInterval, are from the global config.
+// a factory not implementing StoreCreator can only return the store of the global config
+func NewStore(storeConf *config.Store) storage.Store {
+	factory, ok := storeFactorys[storeConf.Driver]
+	if !ok {
+		panic(fmt.Errorf("unknown store driver: %s", storeConf.Driver))
+	}
+	if creator, ok := factory.(StoreCreator); ok {
+		return creator.NewStore(storeConf)
+	}
+	if storeConf != &conf.Store {
+		panic(fmt.Errorf("store driver %s can not create a store with the given config, StoreCreator should be implemented", storeConf.Driver))
+	}
+	return factory.GetStorage()
+}
+
+func newBoltDbStore(storeConf *config.Store) storage.Store {
+	return boltdb.NewStore(storeConf.DataExpire, conf.RetryInterval,
+		boltdb.WithPath(storeConf.BoltPath),
+		boltdb.WithCleanupInterval(time.Duration(storeConf.BoltCleanupInterval)*time.Second),
+		boltdb.WithCompactInterval(time.Duration(storeConf.BoltCompactInterval)*time.Second))
+}
+
+func newSQLStore(storeConf *config.Store) storage.Store {
+	shardConfs, err := storeConf.GetShards()
+	if err != nil {
+		panic(err)
+	}
+	if shardConfs == nil {
+		return sql.NewStore(storeConf)
+	}
+	shards := []sharded.Shard{}
+	for i := range shardConfs {
+		shards = append(shards, sharded.Shard{
+			Name:  fmt.Sprintf("%s:%d", shardConfs[i].Host, shardConfs[i].Port),
+			Store: sql.NewStore(&shardConfs[i]),
+		})
+	}
+	return sharded.NewStore(shards)
 }
 
 // GetStore returns storage.Store
@@ -48,7 +120,7 @@ func GetStore() storage.Store {
 
 // WaitStoreUp wait for db to go up
 func WaitStoreUp() {
-	for err := GetStore().Ping(); err != nil; err = GetStore().Ping() {
+	for err := GetStore().Ping(context.Background()); err != nil; err = GetStore().Ping(context.Background()) {
 		logger.Infof("wait store up: %v", err)
 		time.Sleep(3 * time.Second)
 	}
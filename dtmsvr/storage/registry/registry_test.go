@@ -0,0 +1,60 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package registry
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dtm-labs/dtm/dtmsvr/config"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/dtm/dtmsvr/storage/memory"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterDriver(t *testing.T) {
+	created := []string{}
+	Register("fake", NewDriverFactory(func(storeConf *config.Store) storage.Store {
+		created = append(created, storeConf.Host)
+		return memory.NewStore(storeConf.DataExpire, storeConf.FinishedDataExpire, conf.RetryInterval)
+	}, func(storeConf *config.Store) error {
+		if storeConf.Host == "" {
+			return errors.New("fake host not valid")
+		}
+		return nil
+	}))
+	defer delete(storeFactorys, "fake")
+
+	s1 := NewStore(&config.Store{Driver: "fake", Host: "host1"})
+	s2 := NewStore(&config.Store{Driver: "fake", Host: "host2"})
+	assert.NotNil(t, s1)
+	assert.NotSame(t, s1, s2)
+	assert.Equal(t, []string{"host1", "host2"}, created)
+
+	assert.Equal(t, errors.New("fake host not valid"), storeFactorys["fake"].(ConfigChecker).CheckConfig(&config.Store{Driver: "fake"}))
+}
+
+func TestRegisterSingletonFactory(t *testing.T) {
+	store := memory.NewStore(100, 100, 10)
+	Register("fake-singleton", NewSingletonFactory(func() storage.Store { return store }))
+	defer delete(storeFactorys, "fake-singleton")
+
+	old := conf.Store
+	defer func() { conf.Store = old }()
+	conf.Store = config.Store{Driver: "fake-singleton"}
+	assert.Same(t, store, NewStore(&conf.Store)) // only the store of the global config can be returned
+	assert.Panics(t, func() { NewStore(&config.Store{Driver: "fake-singleton"}) })
+}
+
+func TestNewStoreBuiltin(t *testing.T) {
+	_, ok := NewStore(&config.Store{Driver: config.Memory}).(*memory.Store)
+	assert.True(t, ok)
+	assert.Panics(t, func() { NewStore(&config.Store{Driver: "unknown"}) })
+	assert.Panics(t, func() { // the built-in driver can not be overwritten
+		Register(config.Memory, NewSingletonFactory(func() storage.Store { return nil }))
+	})
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// sort fields supported by ScanTransGlobalStores
+const (
+	SortByCreateTime = "create_time"
+	SortByUpdateTime = "update_time"
+)
+
+// TransGlobalScanCondition defines the filters and the order of ScanTransGlobalStores
+// zero value fields are ignored. the default order is create_time desc, gid desc
+type TransGlobalScanCondition struct {
+	Status          string
+	TransType       string
+	GidPrefix       string
+	CreateTimeStart time.Time // inclusive
+	CreateTimeEnd   time.Time // exclusive
+	UpdateTimeStart time.Time // inclusive
+	UpdateTimeEnd   time.Time // exclusive
+	// StuckSeconds selects unfinished transactions that have not been updated for StuckSeconds
+	StuckSeconds int64
+	// Unfinished selects unfinished transactions only
+	Unfinished bool
+	SortBy     string
+	SortAsc    bool
+}
+
+// Validate checks the condition is supported
+func (c *TransGlobalScanCondition) Validate() error {
+	if c.SortBy != "" && c.SortBy != SortByCreateTime && c.SortBy != SortByUpdateTime {
+		return fmt.Errorf("sort by %s is not supported. supported: %s, %s", c.SortBy, SortByCreateTime, SortByUpdateTime)
+	}
+	if c.StuckSeconds < 0 {
+		return fmt.Errorf("stuck seconds should not be negative: %d", c.StuckSeconds)
+	}
+	return nil
+}
+
+// GetSortBy returns the sort field, default to create_time
+func (c *TransGlobalScanCondition) GetSortBy() string {
+	if c.SortBy == "" {
+		return SortByCreateTime
+	}
+	return c.SortBy
+}
+
+// StuckBefore returns the update_time before which the unfinished trans are considered stuck
+func (c *TransGlobalScanCondition) StuckBefore(now time.Time) time.Time {
+	return now.Add(-time.Duration(c.StuckSeconds) * time.Second)
+}
+
+// Match returns true if the global transaction matches the condition.
+// it is used by the stores that can not filter data by query
+func (c *TransGlobalScanCondition) Match(g *TransGlobalStore, now time.Time) bool {
+	createTime, updateTime := timeOf(g.CreateTime), timeOf(g.UpdateTime)
+	return (c.Status == "" || g.Status == c.Status) &&
+		(c.TransType == "" || g.TransType == c.TransType) &&
+		strings.HasPrefix(g.Gid, c.GidPrefix) &&
+		(c.CreateTimeStart.IsZero() || !createTime.Before(c.CreateTimeStart)) &&
+		(c.CreateTimeEnd.IsZero() || createTime.Before(c.CreateTimeEnd)) &&
+		(c.UpdateTimeStart.IsZero() || !updateTime.Before(c.UpdateTimeStart)) &&
+		(c.UpdateTimeEnd.IsZero() || updateTime.Before(c.UpdateTimeEnd)) &&
+		(c.StuckSeconds == 0 || !g.IsFinished() && updateTime.Before(c.StuckBefore(now))) &&
+		(!c.Unfinished || !g.IsFinished())
+}
+
+// SortTime returns the value of the sort field of g
+func (c *TransGlobalScanCondition) SortTime(g *TransGlobalStore) time.Time {
+	if c.GetSortBy() == SortByUpdateTime {
+		return timeOf(g.UpdateTime)
+	}
+	return timeOf(g.CreateTime)
+}
+
+// EncodePosition encodes the position after g. position is "<sort time>|<gid>"
+func (c *TransGlobalScanCondition) EncodePosition(g *TransGlobalStore) string {
+	return c.SortTime(g).Format(time.RFC3339Nano) + "|" + g.Gid
+}
+
+// DecodePosition decodes the position returned by EncodePosition
+func DecodePosition(position string) (time.Time, string, error) {
+	i := strings.Index(position, "|")
+	if i < 0 {
+		return time.Time{}, "", fmt.Errorf("bad position: %s", position)
+	}
+	t, err := time.Parse(time.RFC3339Nano, position[:i])
+	return t, position[i+1:], err
+}
+
+// After returns true if g is located after the position in the order of the condition
+func (c *TransGlobalScanCondition) After(g *TransGlobalStore, posTime time.Time, posGid string) bool {
+	t := c.SortTime(g)
+	if c.SortAsc {
+		return t.After(posTime) || t.Equal(posTime) && g.Gid > posGid
+	}
+	return t.Before(posTime) || t.Equal(posTime) && g.Gid < posGid
+}
+
+// Less returns true if a is located before b in the order of the condition
+func (c *TransGlobalScanCondition) Less(a *TransGlobalStore, b *TransGlobalStore) bool {
+	ta, tb := c.SortTime(a), c.SortTime(b)
+	if !ta.Equal(tb) {
+		return ta.Before(tb) == c.SortAsc
+	}
+	return (a.Gid < b.Gid) == c.SortAsc
+}
+
+// FilterSortPage filters, sorts and pages the globals in memory, and updates the position.
+// it is used by the stores that can not filter or sort data by query
+func (c *TransGlobalScanCondition) FilterSortPage(globals []TransGlobalStore, position *string, limit int64) ([]TransGlobalStore, error) {
+	var posTime time.Time
+	var posGid string
+	var err error
+	if *position != "" {
+		posTime, posGid, err = DecodePosition(*position)
+		if err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	matched := []TransGlobalStore{}
+	for i := range globals {
+		g := &globals[i]
+		if c.Match(g, now) && (*position == "" || c.After(g, posTime, posGid)) {
+			matched = append(matched, *g)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool { return c.Less(&matched[i], &matched[j]) })
+	if int64(len(matched)) > limit {
+		matched = matched[:limit]
+	}
+	c.UpdatePosition(matched, position, limit)
+	return matched, nil
+}
+
+// UpdatePosition sets position to the last item of a full page, or "" if there is no more data
+func (c *TransGlobalScanCondition) UpdatePosition(globals []TransGlobalStore, position *string, limit int64) {
+	if int64(len(globals)) < limit || len(globals) == 0 {
+		*position = ""
+	} else {
+		*position = c.EncodePosition(&globals[len(globals)-1])
+	}
+}
+
+func timeOf(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+	return *t
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dtm-labs/dtm/client/dtmcli"
+	"github.com/stretchr/testify/assert"
+)
+
+func newScanTrans(gid string, status string, createTime time.Time, updateTime time.Time) TransGlobalStore {
+	g := TransGlobalStore{Gid: gid, Status: status, TransType: "saga"}
+	g.CreateTime = &createTime
+	g.UpdateTime = &updateTime
+	return g
+}
+
+func TestScanConditionMatch(t *testing.T) {
+	now := time.Now()
+	g := newScanTrans("g1", dtmcli.StatusSubmitted, now.Add(-time.Hour), now.Add(-time.Minute))
+
+	assert.True(t, (&TransGlobalScanCondition{}).Match(&g, now))
+	assert.True(t, (&TransGlobalScanCondition{Status: dtmcli.StatusSubmitted, TransType: "saga", GidPrefix: "g"}).Match(&g, now))
+	assert.False(t, (&TransGlobalScanCondition{Status: dtmcli.StatusSucceed}).Match(&g, now))
+	assert.False(t, (&TransGlobalScanCondition{TransType: "msg"}).Match(&g, now))
+	assert.False(t, (&TransGlobalScanCondition{GidPrefix: "g2"}).Match(&g, now))
+	assert.True(t, (&TransGlobalScanCondition{CreateTimeStart: now.Add(-time.Hour), CreateTimeEnd: now}).Match(&g, now))
+	assert.False(t, (&TransGlobalScanCondition{CreateTimeEnd: now.Add(-time.Hour)}).Match(&g, now))
+	assert.False(t, (&TransGlobalScanCondition{UpdateTimeStart: now}).Match(&g, now))
+	assert.True(t, (&TransGlobalScanCondition{StuckSeconds: 30}).Match(&g, now))
+	assert.False(t, (&TransGlobalScanCondition{StuckSeconds: 120}).Match(&g, now))
+
+	assert.True(t, (&TransGlobalScanCondition{Unfinished: true}).Match(&g, now))
+
+	g.Status = dtmcli.StatusSucceed
+	assert.False(t, (&TransGlobalScanCondition{StuckSeconds: 30}).Match(&g, now))
+	assert.False(t, (&TransGlobalScanCondition{Unfinished: true}).Match(&g, now))
+}
+
+func TestScanConditionFilterSortPage(t *testing.T) {
+	now := time.Now()
+	globals := []TransGlobalStore{
+		newScanTrans("a", dtmcli.StatusSucceed, now, now),
+		newScanTrans("c", dtmcli.StatusSucceed, now.Add(-time.Second), now),
+		newScanTrans("b", dtmcli.StatusSucceed, now, now.Add(-time.Second)),
+		newScanTrans("d", dtmcli.StatusFailed, now, now),
+	}
+	gids := func(globals []TransGlobalStore) []string {
+		r := []string{}
+		for _, g := range globals {
+			r = append(r, g.Gid)
+		}
+		return r
+	}
+
+	cond := TransGlobalScanCondition{Status: dtmcli.StatusSucceed}
+	position := ""
+	page, err := cond.FilterSortPage(globals, &position, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"b", "a"}, gids(page))
+	assert.NotEqual(t, "", position)
+	page, err = cond.FilterSortPage(globals, &position, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"c"}, gids(page))
+	assert.Equal(t, "", position)
+
+	cond = TransGlobalScanCondition{SortBy: SortByUpdateTime, SortAsc: true}
+	page, err = cond.FilterSortPage(globals, &position, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"b", "a", "c", "d"}, gids(page))
+
+	position = "bad position"
+	_, err = cond.FilterSortPage(globals, &position, 10)
+	assert.Error(t, err)
+
+	assert.Error(t, (&TransGlobalScanCondition{SortBy: "gid"}).Validate())
+	assert.Nil(t, (&TransGlobalScanCondition{SortBy: SortByUpdateTime}).Validate())
+}
@@ -0,0 +1,397 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package sharded
+
+import (
+	"context"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+)
+
+// virtualNodes is the number of the points of a shard on the hash ring
+const virtualNodes = 160
+
+// Shard is a named shard. the name is hashed to locate the shard on the ring, so it should be stable, such as host:port
+type Shard struct {
+	Name  string
+	Store storage.Store
+}
+
+// Store implements storage.Store over several shards.
+// the data of a gid, including the branches and events, are saved in the shard located by a consistent hash of the gid.
+// the scans are fanned out to all the shards and merged, and the cron polls the shards in turn.
+// the gids located to other shards after the shards are changed can not be found, so the data should be migrated then
+type Store struct {
+	shards []Shard
+	ring   []ringNode
+	next   uint32 // the shard to be polled first by the next LockOneGlobalTrans
+}
+
+type ringNode struct {
+	hash  uint32
+	shard int
+}
+
+// NewStore returns a store over the shards
+func NewStore(shards []Shard) *Store {
+	s := &Store{shards: shards}
+	for i, shard := range shards {
+		for v := 0; v < virtualNodes; v++ {
+			s.ring = append(s.ring, ringNode{hash: hashOf(fmt.Sprintf("%s#%d", shard.Name, v)), shard: i})
+		}
+	}
+	sort.Slice(s.ring, func(i, j int) bool { return s.ring[i].hash < s.ring[j].hash })
+	return s
+}
+
+func hashOf(key string) uint32 {
+	return crc32.ChecksumIEEE([]byte(key))
+}
+
+// shardOf returns the shard of the gid, which is the first node on the ring clockwise from the hash of gid
+func (s *Store) shardOf(gid string) storage.Store {
+	h := hashOf(gid)
+	i := sort.Search(len(s.ring), func(i int) bool { return s.ring[i].hash >= h })
+	if i == len(s.ring) {
+		i = 0
+	}
+	return s.shards[s.ring[i].shard].Store
+}
+
+// each calls fn for every shard, and stops at the first error
+func (s *Store) each(fn func(shard storage.Store) error) error {
+	for _, shard := range s.shards {
+		if err := fn(shard.Store); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Ping pings all the shards
+func (s *Store) Ping(ctx context.Context) error {
+	return s.each(func(shard storage.Store) error {
+		return shard.Ping(ctx)
+	})
+}
+
+// PopulateData populates data to all the shards
+func (s *Store) PopulateData(ctx context.Context, skipDrop bool) error {
+	return s.each(func(shard storage.Store) error {
+		return shard.PopulateData(ctx, skipDrop)
+	})
+}
+
+// Close closes all the shards which implement io.Closer
+func (s *Store) Close() error {
+	var first error
+	for _, shard := range s.shards {
+		if closer, ok := shard.Store.(io.Closer); ok {
+			if err := closer.Close(); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+// FindTransGlobalStore finds GlobalTrans data by gid
+func (s *Store) FindTransGlobalStore(ctx context.Context, gid string) (*storage.TransGlobalStore, error) {
+	return s.shardOf(gid).FindTransGlobalStore(ctx, gid)
+}
+
+// ScanTransGlobalStores scans every shard from the position, and merges the results in the order of the condition.
+// the position is the same as the one of a single store, so that it can be passed to every shard
+func (s *Store) ScanTransGlobalStores(ctx context.Context, position *string, limit int64, condition storage.TransGlobalScanCondition) ([]storage.TransGlobalStore, error) {
+	merged := []storage.TransGlobalStore{}
+	err := s.each(func(shard storage.Store) error {
+		pos := *position
+		globals, err := shard.ScanTransGlobalStores(ctx, &pos, limit, condition)
+		merged = append(merged, globals...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(merged, func(i, j int) bool { return condition.Less(&merged[i], &merged[j]) })
+	if int64(len(merged)) > limit {
+		merged = merged[:limit]
+	}
+	condition.UpdatePosition(merged, position, limit)
+	return merged, nil
+}
+
+// FindBranches finds Branch data by gid
+func (s *Store) FindBranches(ctx context.Context, gid string) ([]storage.TransBranchStore, error) {
+	return s.shardOf(gid).FindBranches(ctx, gid)
+}
+
+// UpdateBranches updates the branches in the shards of their gids
+func (s *Store) UpdateBranches(ctx context.Context, branches []storage.TransBranchStore, updates []string) (int, error) {
+	groups := map[storage.Store][]storage.TransBranchStore{}
+	for _, b := range branches {
+		shard := s.shardOf(b.Gid)
+		groups[shard] = append(groups[shard], b)
+	}
+	affected := 0
+	err := s.each(func(shard storage.Store) error {
+		if len(groups[shard]) == 0 {
+			return nil
+		}
+		n, err := shard.UpdateBranches(ctx, groups[shard], updates)
+		affected += n
+		return err
+	})
+	return affected, err
+}
+
+// LockGlobalSaveBranches creates branches
+func (s *Store) LockGlobalSaveBranches(ctx context.Context, gid string, status string, branches []storage.TransBranchStore, branchStart int) error {
+	return s.shardOf(gid).LockGlobalSaveBranches(ctx, gid, status, branches, branchStart)
+}
+
+// MaySaveNewTrans creates a new trans
+func (s *Store) MaySaveNewTrans(ctx context.Context, global *storage.TransGlobalStore, branches []storage.TransBranchStore) error {
+	return s.shardOf(global.Gid).MaySaveNewTrans(ctx, global, branches)
+}
+
+// ChangeGlobalStatus changes global trans status
+func (s *Store) ChangeGlobalStatus(ctx context.Context, global *storage.TransGlobalStore, newStatus string, updates []string, finished bool) error {
+	return s.shardOf(global.Gid).ChangeGlobalStatus(ctx, global, newStatus, updates, finished)
+}
+
+// TouchCronTime updates cronTime
+func (s *Store) TouchCronTime(ctx context.Context, global *storage.TransGlobalStore, nextCronInterval int64, nextCronTime *time.Time) error {
+	return s.shardOf(global.Gid).TouchCronTime(ctx, global, nextCronInterval, nextCronTime)
+}
+
+// LockOneGlobalTrans polls the shards in turn, starting from the one after the shard polled first last time,
+// so that a shard with many due transactions does not starve the others
+func (s *Store) LockOneGlobalTrans(ctx context.Context, expireIn time.Duration) (*storage.TransGlobalStore, error) {
+	start := int(atomic.AddUint32(&s.next, 1)-1) % len(s.shards)
+	for i := range s.shards {
+		global, err := s.shards[(start+i)%len(s.shards)].Store.LockOneGlobalTrans(ctx, expireIn)
+		if err != nil || global != nil {
+			return global, err
+		}
+	}
+	return nil, nil
+}
+
+// LockGlobalTransBatch locks at most limit global trans from the shards in turn like LockOneGlobalTrans.
+// each shard is given an even share of the limit first, and the rest of the limit is filled by the shards having more
+func (s *Store) LockGlobalTransBatch(ctx context.Context, expireIn time.Duration, limit int) ([]storage.TransGlobalStore, error) {
+	start := int(atomic.AddUint32(&s.next, 1)-1) % len(s.shards)
+	globals := []storage.TransGlobalStore{}
+	exhausted := map[int]bool{}
+	for _, even := range []bool{true, false} {
+		for i := range s.shards {
+			remaining := limit - len(globals)
+			idx := (start + i) % len(s.shards)
+			if remaining <= 0 || exhausted[idx] {
+				continue
+			}
+			quota := remaining
+			if even {
+				left := len(s.shards) - i
+				quota = (remaining + left - 1) / left
+			}
+			locked, err := storage.LockGlobalTransBatch(ctx, s.shards[idx].Store, expireIn, quota)
+			globals = append(globals, locked...)
+			if err != nil {
+				return globals, err
+			}
+			exhausted[idx] = len(locked) < quota
+		}
+	}
+	return globals, nil
+}
+
+// ResetCronTime resets the cron time of at most limit transactions in all the shards
+func (s *Store) ResetCronTime(ctx context.Context, after time.Duration, limit int64) (int64, bool, error) {
+	total := int64(0)
+	hasRemaining := false
+	err := s.each(func(shard storage.Store) error {
+		if total >= limit {
+			hasRemaining = true // the shard is not checked, and will be reset in the next call
+			return nil
+		}
+		count, remaining, err := shard.ResetCronTime(ctx, after, limit-total)
+		total += count
+		hasRemaining = hasRemaining || remaining
+		return err
+	})
+	return total, hasRemaining, err
+}
+
+// SaveTransEvents saves the events in the shards of their gids
+func (s *Store) SaveTransEvents(ctx context.Context, events []storage.TransEventStore) error {
+	groups := map[storage.Store][]storage.TransEventStore{}
+	for _, e := range events {
+		shard := s.shardOf(e.Gid)
+		groups[shard] = append(groups[shard], e)
+	}
+	return s.each(func(shard storage.Store) error {
+		if len(groups[shard]) == 0 {
+			return nil
+		}
+		return shard.SaveTransEvents(ctx, groups[shard])
+	})
+}
+
+// FindTransEvents finds the status transitions by gid
+func (s *Store) FindTransEvents(ctx context.Context, gid string) ([]storage.TransEventStore, error) {
+	return s.shardOf(gid).FindTransEvents(ctx, gid)
+}
+
+// GetTransStats merges the statistics of all the shards.
+// the failing urls are merged from the top urls of each shard, so the counts of the urls not in the top of a shard are missed
+func (s *Store) GetTransStats(ctx context.Context, topURLs int) (*storage.TransStats, error) {
+	merged := &storage.TransStats{}
+	counts := map[storage.TransCount]int64{}
+	urls := map[string]int64{}
+	err := s.each(func(shard storage.Store) error {
+		stats, err := shard.GetTransStats(ctx, topURLs)
+		if err != nil {
+			return err
+		}
+		for _, c := range stats.Counts {
+			counts[storage.TransCount{Status: c.Status, TransType: c.TransType}] += c.Count
+		}
+		for _, u := range stats.FailingURLs {
+			urls[u.URL] += u.Count
+		}
+		if stats.OldestUnfinishedTime != nil && (merged.OldestUnfinishedTime == nil || stats.OldestUnfinishedTime.Before(*merged.OldestUnfinishedTime)) {
+			merged.OldestUnfinishedTime = stats.OldestUnfinishedTime
+		}
+		merged.CronLagCount += stats.CronLagCount
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	merged.Counts = []storage.TransCount{}
+	for c, count := range counts {
+		c.Count = count
+		merged.Counts = append(merged.Counts, c)
+	}
+	sort.Slice(merged.Counts, func(i, j int) bool {
+		a, b := merged.Counts[i], merged.Counts[j]
+		return a.Status < b.Status || a.Status == b.Status && a.TransType < b.TransType
+	})
+	merged.FailingURLs = storage.TopURLCounts(urls, topURLs)
+	return merged, nil
+}
+
+// PurgeFinished purges at most limit finished transactions in all the shards which implement storage.FinishedPurger
+func (s *Store) PurgeFinished(ctx context.Context, before time.Time, limit int64, archive bool) (int64, error) {
+	total := int64(0)
+	err := s.each(func(shard storage.Store) error {
+		purger, ok := shard.(storage.FinishedPurger)
+		if !ok || total >= limit {
+			return nil
+		}
+		n, err := purger.PurgeFinished(ctx, before, limit-total, archive)
+		total += n
+		return err
+	})
+	return total, err
+}
+
+// SchemaVersion returns the newest schema version of the shards if any shard is newer than this binary, otherwise the oldest one,
+// so that dtm fails fast if any shard is migrated by a newer dtm, and migrates the schema if any shard is older
+func (s *Store) SchemaVersion(ctx context.Context) (int, int, error) {
+	oldest, newest, latest := -1, -1, 0
+	err := s.each(func(shard storage.Store) error {
+		migrator, ok := shard.(storage.SchemaMigrator)
+		if !ok {
+			return nil
+		}
+		current, l, err := migrator.SchemaVersion(ctx)
+		if oldest == -1 || current < oldest {
+			oldest = current
+		}
+		if current > newest {
+			newest = current
+		}
+		latest = l
+		return err
+	})
+	if newest > latest {
+		return newest, latest, err
+	} else if oldest == -1 { // no shard has a versioned schema
+		oldest = latest
+	}
+	return oldest, latest, err
+}
+
+// MigrateSchema migrates the schema of all the shards which implement storage.SchemaMigrator
+func (s *Store) MigrateSchema(ctx context.Context) error {
+	return s.each(func(shard storage.Store) error {
+		if migrator, ok := shard.(storage.SchemaMigrator); ok {
+			return migrator.MigrateSchema(ctx)
+		}
+		return nil
+	})
+}
+
+// nodeRegistry returns the first shard, which keeps the nodes
+func (s *Store) nodeRegistry() (storage.NodeRegistry, error) {
+	registry, ok := s.shards[0].Store.(storage.NodeRegistry)
+	if !ok {
+		return nil, fmt.Errorf("shard %s can not keep the nodes", s.shards[0].Name)
+	}
+	return registry, nil
+}
+
+// SaveNode saves the node in the first shard
+func (s *Store) SaveNode(ctx context.Context, node *storage.NodeStore) error {
+	registry, err := s.nodeRegistry()
+	if err != nil {
+		return err
+	}
+	return registry.SaveNode(ctx, node)
+}
+
+// FindNodes returns the nodes in the first shard
+func (s *Store) FindNodes(ctx context.Context) ([]storage.NodeStore, error) {
+	registry, err := s.nodeRegistry()
+	if err != nil {
+		return nil, err
+	}
+	return registry.FindNodes(ctx)
+}
+
+// RemoveNode removes the node from the first shard
+func (s *Store) RemoveNode(ctx context.Context, id string) error {
+	registry, err := s.nodeRegistry()
+	if err != nil {
+		return err
+	}
+	return registry.RemoveNode(ctx, id)
+}
+
+// ReclaimTrans reclaims the trans locked by the node in all the shards which implement storage.NodeRegistry
+func (s *Store) ReclaimTrans(ctx context.Context, nodeID string) (int64, error) {
+	total := int64(0)
+	err := s.each(func(shard storage.Store) error {
+		registry, ok := shard.(storage.NodeRegistry)
+		if !ok {
+			return nil
+		}
+		n, err := registry.ReclaimTrans(ctx, nodeID)
+		total += n
+		return err
+	})
+	return total, err
+}
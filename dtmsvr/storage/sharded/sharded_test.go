@@ -0,0 +1,165 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package sharded
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/dtm/dtmsvr/storage/memory"
+	"github.com/dtm-labs/dtm/dtmsvr/storage/storagetest"
+	"github.com/stretchr/testify/assert"
+)
+
+var ctx = context.Background()
+
+func newShards(n int) ([]Shard, []*memory.Store) {
+	shards := []Shard{}
+	stores := []*memory.Store{}
+	for i := 0; i < n; i++ {
+		s := memory.NewStore(100, 100, 10)
+		shards = append(shards, Shard{Name: fmt.Sprintf("db%d:3306", i), Store: s})
+		stores = append(stores, s)
+	}
+	return shards, stores
+}
+
+func saveTrans(t *testing.T, s storage.Store, gid string, next time.Duration) {
+	now := time.Now()
+	nextCronTime := now.Add(next)
+	global := &storage.TransGlobalStore{Gid: gid, TransType: "saga", Status: "submitted", NextCronTime: &nextCronTime}
+	global.CreateTime = &now
+	global.UpdateTime = &now
+	branches := []storage.TransBranchStore{{Gid: gid, BranchID: "01", Op: "action", URL: "url1", Status: "prepared"}}
+	assert.Nil(t, s.MaySaveNewTrans(ctx, global, branches))
+}
+
+func countOf(t *testing.T, s storage.Store) int {
+	position := ""
+	globals, err := s.ScanTransGlobalStores(ctx, &position, 1000, storage.TransGlobalScanCondition{})
+	assert.Nil(t, err)
+	return len(globals)
+}
+
+func TestConformance(t *testing.T) {
+	storagetest.Run(t, func() storage.Store {
+		shards, _ := newShards(3)
+		return NewStore(shards)
+	})
+}
+
+func TestRouting(t *testing.T) {
+	shards, stores := newShards(3)
+	s := NewStore(shards)
+	for i := 0; i < 300; i++ {
+		saveTrans(t, s, fmt.Sprintf("gid%d", i), time.Hour)
+	}
+	for _, store := range stores {
+		assert.InDelta(t, 100, countOf(t, store), 50) // the gids are spread over the shards
+	}
+	for i := 0; i < 300; i++ {
+		gid := fmt.Sprintf("gid%d", i)
+		_, err := s.shardOf(gid).FindTransGlobalStore(ctx, gid)
+		assert.Nil(t, err)
+	}
+
+	// only the gids of the removed shard are located to other shards
+	s2 := NewStore(shards[:2])
+	for i := 0; i < 300; i++ {
+		gid := fmt.Sprintf("gid%d", i)
+		if s.shardOf(gid) != stores[2] {
+			assert.Equal(t, s.shardOf(gid), s2.shardOf(gid))
+		}
+	}
+}
+
+func TestScanMerge(t *testing.T) {
+	shards, _ := newShards(3)
+	s := NewStore(shards)
+	for i := 0; i < 10; i++ {
+		saveTrans(t, s, fmt.Sprintf("gid%02d", i), time.Hour)
+		time.Sleep(time.Millisecond)
+	}
+	condition := storage.TransGlobalScanCondition{SortBy: storage.SortByCreateTime, SortAsc: true}
+	gids := []string{}
+	for position := ""; ; {
+		globals, err := s.ScanTransGlobalStores(ctx, &position, 3, condition)
+		assert.Nil(t, err)
+		for _, g := range globals {
+			gids = append(gids, g.Gid)
+		}
+		if position == "" {
+			break
+		}
+	}
+	expected := []string{}
+	for i := 0; i < 10; i++ {
+		expected = append(expected, fmt.Sprintf("gid%02d", i))
+	}
+	assert.Equal(t, expected, gids) // in the order of the creation across the shards
+}
+
+func TestLockFairly(t *testing.T) {
+	shards, stores := newShards(2)
+	s := NewStore(shards)
+	for i := 0; i < 4; i++ {
+		saveTrans(t, stores[0], fmt.Sprintf("gid0-%d", i), 0)
+	}
+	saveTrans(t, stores[1], "gid1-0", 0)
+	locked := []string{}
+	for g, err := s.LockOneGlobalTrans(ctx, time.Second); g != nil; g, err = s.LockOneGlobalTrans(ctx, time.Second) {
+		assert.Nil(t, err)
+		locked = append(locked, g.Gid)
+	}
+	assert.Len(t, locked, 5)
+	assert.Contains(t, locked[:2], "gid1-0") // the shard with less due transactions is not starved
+}
+
+func TestLockBatchFairly(t *testing.T) {
+	shards, stores := newShards(2)
+	s := NewStore(shards)
+	for i := 0; i < 4; i++ {
+		saveTrans(t, stores[0], fmt.Sprintf("gid0-%d", i), 0)
+	}
+	saveTrans(t, stores[1], "gid1-0", 0)
+	globals, err := s.LockGlobalTransBatch(ctx, time.Second, 2)
+	assert.Nil(t, err)
+	assert.Len(t, globals, 2)
+	assert.Contains(t, []string{globals[0].Gid, globals[1].Gid}, "gid1-0") // each shard has a share of the batch
+
+	globals, err = s.LockGlobalTransBatch(ctx, time.Second, 10)
+	assert.Nil(t, err)
+	assert.Len(t, globals, 3) // the rest of the limit is filled by the shard having more
+}
+
+func TestResetCronTimeAndStats(t *testing.T) {
+	shards, stores := newShards(2)
+	s := NewStore(shards)
+	saveTrans(t, stores[0], "gid0", time.Hour)
+	saveTrans(t, stores[1], "gid1", time.Hour)
+	count, hasRemaining, err := s.ResetCronTime(ctx, time.Minute, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.True(t, hasRemaining)
+	count, hasRemaining, err = s.ResetCronTime(ctx, time.Minute, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.False(t, hasRemaining)
+
+	stats, err := s.GetTransStats(ctx, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []storage.TransCount{{Status: "submitted", TransType: "saga", Count: 2}}, stats.Counts)
+	assert.Equal(t, int64(2), stats.CronLagCount)
+	assert.NotNil(t, stats.OldestUnfinishedTime)
+
+	current, latest, err := s.SchemaVersion(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, latest, current) // the memory shards have no schema
+}
@@ -0,0 +1,2 @@
+ALTER TABLE trans_global ADD COLUMN `notify_status` varchar(12) NOT NULL DEFAULT '' COMMENT 'status of calling notify url: prepared | succeed, empty if no notify url';
+ALTER TABLE trans_global ADD KEY `notify_status_next_cron_time` (`notify_status`, `next_cron_time`) comment 'cron job will use this index to query finished trans to notify';
@@ -0,0 +1,2 @@
+ALTER TABLE trans_global ADD KEY `create_time` (`create_time`) comment 'used by the transaction search api';
+ALTER TABLE trans_global ADD KEY `update_time` (`update_time`) comment 'used by the transaction search api';
@@ -0,0 +1,4 @@
+ALTER TABLE trans_branch_op ADD COLUMN `attempts` int(11) NOT NULL DEFAULT 0 COMMENT 'how many times the url of this op is called';
+ALTER TABLE trans_branch_op ADD COLUMN `last_error` varchar(1024) DEFAULT '' COMMENT 'error of the last call';
+ALTER TABLE trans_branch_op ADD COLUMN `last_status_code` int(11) NOT NULL DEFAULT 0 COMMENT 'http status code or grpc code of the last call';
+ALTER TABLE trans_branch_op ADD COLUMN `last_response` varchar(1024) DEFAULT '' COMMENT 'truncated response body of the last call';
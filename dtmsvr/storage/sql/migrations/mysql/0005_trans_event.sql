@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS trans_event (
+  `id` bigint(22) NOT NULL AUTO_INCREMENT,
+  `gid` varchar(128) NOT NULL COMMENT 'global transaction id',
+  `branch_id` VARCHAR(128) NOT NULL DEFAULT '' COMMENT 'transaction branch ID, empty for global transaction',
+  `op` varchar(45) NOT NULL DEFAULT '' COMMENT 'transaction operation type of the branch',
+  `old_status` varchar(45) NOT NULL DEFAULT '' COMMENT 'status before the transition',
+  `new_status` varchar(45) NOT NULL COMMENT 'status after the transition',
+  `source` varchar(45) NOT NULL DEFAULT '' COMMENT 'what triggers the transition: api | cron | timeout | retry_limit | force_stop | retry_branch | resolve_branch | update_branch',
+  `owner` varchar(128) NOT NULL DEFAULT '' COMMENT 'the dtm server that makes the transition',
+  `operator` varchar(128) NOT NULL DEFAULT '' COMMENT 'the operator who makes the transition manually',
+  `error` varchar(1024) DEFAULT '' COMMENT 'error message of the transition',
+  `create_time` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  key `gid`(`gid`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
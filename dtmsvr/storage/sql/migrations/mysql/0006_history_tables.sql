@@ -0,0 +1,44 @@
+-- the history tables keep the finished transactions archived by dtm. the columns should be the same as the original tables
+CREATE TABLE IF NOT EXISTS trans_global_history (
+  `id` bigint(22) NOT NULL,
+  `gid` varchar(128) NOT NULL COMMENT 'global transaction id',
+  `trans_type` varchar(45) not null COMMENT 'transaction type: saga | xa | tcc | msg',
+  `status` varchar(12) NOT NULL COMMENT 'tranaction status: prepared | submitted | aborting | finished | rollbacked',
+  `query_prepared` varchar(1024) NOT NULL COMMENT 'url to check for msg|workflow',
+  `protocol` varchar(45) not null comment 'protocol: http | grpc | json-rpc',
+  `create_time` datetime DEFAULT NULL,
+  `update_time` datetime DEFAULT NULL,
+  `finish_time` datetime DEFAULT NULL,
+  `rollback_time` datetime DEFAULT NULL,
+  `options` varchar(1024) DEFAULT 'options for transaction like: TimeoutToFail, RequestTimeout',
+  `custom_data` varchar(1024) DEFAULT '' COMMENT 'custom data for transaction',
+  `next_cron_interval` int(11) default null comment 'next cron interval. for use of cron job',
+  `next_cron_time` datetime default null comment 'next time to process this trans. for use of cron job',
+  `owner` varchar(128) not null default '' comment 'who is locking this trans',
+  `ext_data` TEXT comment 'result for this trans. currently used in workflow pattern',
+  `result` varchar(1024) DEFAULT '' COMMENT 'rollback reason for transaction',
+  `rollback_reason` varchar(1024) DEFAULT '' COMMENT 'rollback reason for transaction',
+  `notify_status` varchar(12) NOT NULL DEFAULT '' COMMENT 'status of calling notify url: prepared | succeed, empty if no notify url',
+  PRIMARY KEY (`id`),
+  key `gid`(`gid`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
+CREATE TABLE IF NOT EXISTS trans_branch_op_history (
+  `id` bigint(22) NOT NULL,
+  `gid` varchar(128) NOT NULL COMMENT 'global transaction id',
+  `url` varchar(1024) NOT NULL COMMENT 'the url of this op',
+  `data` TEXT COMMENT 'request body, depreceated',
+  `bin_data` BLOB COMMENT 'request body',
+  `branch_id` VARCHAR(128) NOT NULL COMMENT 'transaction branch ID',
+  `op` varchar(45) NOT NULL COMMENT 'transaction operation type like: action | compensate | try | confirm | cancel',
+  `status` varchar(45) NOT NULL COMMENT 'transaction op status: prepared | succeed | failed',
+  `finish_time` datetime DEFAULT NULL,
+  `rollback_time` datetime DEFAULT NULL,
+  `create_time` datetime DEFAULT NULL,
+  `update_time` datetime DEFAULT NULL,
+  `attempts` int(11) NOT NULL DEFAULT 0 COMMENT 'how many times the url of this op is called',
+  `last_error` varchar(1024) DEFAULT '' COMMENT 'error of the last call',
+  `last_status_code` int(11) NOT NULL DEFAULT 0 COMMENT 'http status code or grpc code of the last call',
+  `last_response` varchar(1024) DEFAULT '' COMMENT 'truncated response body of the last call',
+  PRIMARY KEY (`id`),
+  key `gid`(`gid`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
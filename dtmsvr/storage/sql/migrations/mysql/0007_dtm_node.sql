@@ -0,0 +1,9 @@
+-- the dtm servers sharing the store, each saves its heartbeat periodically
+CREATE TABLE IF NOT EXISTS dtm_node (
+  `id` varchar(128) NOT NULL COMMENT 'the dtm server: hostname-pid',
+  `version` varchar(45) NOT NULL DEFAULT '' COMMENT 'the version of the dtm server',
+  `load` bigint(22) NOT NULL DEFAULT 0 COMMENT 'the num of the transactions being processed by the dtm server',
+  `start_time` datetime DEFAULT NULL,
+  `heartbeat_time` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4;
@@ -0,0 +1,2 @@
+ALTER TABLE trans_global ADD COLUMN IF NOT EXISTS notify_status varchar(12) NOT NULL DEFAULT '';
+create index if not EXISTS notify_status_next_cron_time on trans_global (notify_status, next_cron_time);
@@ -0,0 +1,2 @@
+create index if not EXISTS create_time on trans_global (create_time);
+create index if not EXISTS update_time on trans_global (update_time);
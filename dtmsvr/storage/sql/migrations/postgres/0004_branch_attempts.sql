@@ -0,0 +1,4 @@
+ALTER TABLE trans_branch_op ADD COLUMN IF NOT EXISTS attempts int NOT NULL DEFAULT 0;
+ALTER TABLE trans_branch_op ADD COLUMN IF NOT EXISTS last_error varchar(1024) DEFAULT '';
+ALTER TABLE trans_branch_op ADD COLUMN IF NOT EXISTS last_status_code int NOT NULL DEFAULT 0;
+ALTER TABLE trans_branch_op ADD COLUMN IF NOT EXISTS last_response varchar(1024) DEFAULT '';
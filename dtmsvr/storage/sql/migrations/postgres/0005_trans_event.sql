@@ -0,0 +1,16 @@
+CREATE SEQUENCE if not EXISTS trans_event_seq;
+CREATE TABLE IF NOT EXISTS trans_event (
+  id bigint NOT NULL DEFAULT NEXTVAL ('trans_event_seq'),
+  gid varchar(128) NOT NULL,
+  branch_id VARCHAR(128) NOT NULL DEFAULT '',
+  op varchar(45) NOT NULL DEFAULT '',
+  old_status varchar(45) NOT NULL DEFAULT '',
+  new_status varchar(45) NOT NULL,
+  source varchar(45) NOT NULL DEFAULT '',
+  owner varchar(128) NOT NULL DEFAULT '',
+  operator varchar(128) NOT NULL DEFAULT '',
+  error varchar(1024) DEFAULT '',
+  create_time timestamp(0) with time zone DEFAULT NULL,
+  PRIMARY KEY (id)
+);
+create index if not EXISTS trans_event_gid on trans_event(gid);
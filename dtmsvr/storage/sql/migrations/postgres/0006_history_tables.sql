@@ -0,0 +1,44 @@
+-- the history tables keep the finished transactions archived by dtm. the columns should be the same as the original tables
+CREATE TABLE IF NOT EXISTS trans_global_history (
+  id bigint NOT NULL,
+  gid varchar(128) NOT NULL,
+  trans_type varchar(45) not null,
+  status varchar(45) NOT NULL,
+  query_prepared varchar(1024) NOT NULL,
+  protocol varchar(45) not null,
+  create_time timestamp(0) with time zone DEFAULT NULL,
+  update_time timestamp(0) with time zone DEFAULT NULL,
+  finish_time timestamp(0) with time zone DEFAULT NULL,
+  rollback_time timestamp(0) with time zone DEFAULT NULL,
+  options varchar(1024) DEFAULT '',
+  custom_data varchar(1024) DEFAULT '',
+  next_cron_interval int default null,
+  next_cron_time timestamp(0) with time zone default null,
+  owner varchar(128) not null default '',
+  ext_data text,
+  result varchar(1024) DEFAULT '',
+  rollback_reason varchar(1024) DEFAULT '',
+  notify_status varchar(12) NOT NULL DEFAULT '',
+  PRIMARY KEY (id)
+);
+create index if not EXISTS trans_global_history_gid on trans_global_history(gid);
+CREATE TABLE IF NOT EXISTS trans_branch_op_history (
+  id bigint NOT NULL,
+  gid varchar(128) NOT NULL,
+  url varchar(1024) NOT NULL,
+  data TEXT,
+  bin_data bytea,
+  branch_id VARCHAR(128) NOT NULL,
+  op varchar(45) NOT NULL,
+  status varchar(45) NOT NULL,
+  finish_time timestamp(0) with time zone DEFAULT NULL,
+  rollback_time timestamp(0) with time zone DEFAULT NULL,
+  create_time timestamp(0) with time zone DEFAULT NULL,
+  update_time timestamp(0) with time zone DEFAULT NULL,
+  attempts int NOT NULL DEFAULT 0,
+  last_error varchar(1024) DEFAULT '',
+  last_status_code int NOT NULL DEFAULT 0,
+  last_response varchar(1024) DEFAULT '',
+  PRIMARY KEY (id)
+);
+create index if not EXISTS trans_branch_op_history_gid on trans_branch_op_history(gid);
@@ -0,0 +1,9 @@
+-- the dtm servers sharing the store, each saves its heartbeat periodically
+CREATE TABLE IF NOT EXISTS dtm_node (
+  id varchar(128) NOT NULL,
+  version varchar(45) NOT NULL DEFAULT '',
+  load bigint NOT NULL DEFAULT 0,
+  start_time timestamp(0) with time zone DEFAULT NULL,
+  heartbeat_time timestamp(0) with time zone DEFAULT NULL,
+  PRIMARY KEY (id)
+);
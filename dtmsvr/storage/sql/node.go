@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package sql
+
+import (
+	"context"
+
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/lithammer/shortuuid/v3"
+	"gorm.io/gorm/clause"
+)
+
+// the owner of a locked trans is node/uuid, so that the trans locked in one call can be found by the owner,
+// and the trans locked by a node can be found by the prefix
+func lockOwner(nodeID string) string {
+	if nodeID == "" {
+		return shortuuid.New()
+	}
+	return nodeID + "/" + shortuuid.New()
+}
+
+// SaveNode creates or updates the node
+func (s *Store) SaveNode(ctx context.Context, node *storage.NodeStore) error {
+	return s.dbWith(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"version", "load", "start_time", "heartbeat_time"}),
+	}).Create(node).Error
+}
+
+// FindNodes returns all the nodes, sorted by id
+func (s *Store) FindNodes(ctx context.Context) ([]storage.NodeStore, error) {
+	nodes := []storage.NodeStore{}
+	err := s.dbWith(ctx).Order("id").Find(&nodes).Error
+	return nodes, err
+}
+
+// RemoveNode removes the node
+func (s *Store) RemoveNode(ctx context.Context, id string) error {
+	return s.dbWith(ctx).Where("id = ?", id).Delete(&storage.NodeStore{}).Error
+}
+
+// ReclaimTrans makes the unfinished global trans locked by the node due now
+func (s *Store) ReclaimTrans(ctx context.Context, nodeID string) (int64, error) {
+	sql := `UPDATE trans_global SET update_time=?, next_cron_time=?, owner='' WHERE owner like ? and (status in ('prepared', 'aborting', 'submitted') or notify_status = 'prepared')`
+	dbr := s.dbWith(ctx).Exec(sql, getTimeStr(0), getTimeStr(0), escapeLike(nodeID)+"/%")
+	return dbr.RowsAffected, dbr.Error
+}
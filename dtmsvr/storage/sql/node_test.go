@@ -0,0 +1,21 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package sql
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockOwner(t *testing.T) {
+	assert.NotContains(t, lockOwner(""), "/")
+	assert.True(t, strings.HasPrefix(lockOwner("host-1"), "host-1/"))
+	assert.NotEqual(t, lockOwner("host-1"), lockOwner("host-1")) // the trans locked in different calls are distinguished
+	assert.Equal(t, `my\_host\%\\-1`, escapeLike(`my_host%\-1`))
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package sql
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dtm-labs/logger"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// the migrations of each driver are named as NNNN_name.sql, and applied in the order of the version NNNN.
+// the statements should be idempotent, so that a half applied migration can be rerun.
+// a column added to trans_global or trans_branch_op should also be added to the history tables
+//
+//go:embed migrations
+var migrationFS embed.FS
+
+const (
+	schemaCat = "dtm"
+	schemaKey = "schema_version"
+)
+
+// the errors of mysql returned when a migration is rerun: table exists, duplicate column, duplicate key
+var mysqlIgnoredErrors = map[uint16]bool{1050: true, 1060: true, 1061: true}
+
+type migration struct {
+	Version    int
+	Name       string
+	Statements []string
+}
+
+type kvStore struct {
+	ID         uint64
+	Cat        string
+	K          string
+	V          string
+	Version    int64
+	CreateTime *time.Time
+	UpdateTime *time.Time
+}
+
+// TableName TableName
+func (*kvStore) TableName() string {
+	return "kv"
+}
+
+// loadMigrations returns the migrations of the driver, sorted by version
+func loadMigrations(driver string) ([]migration, error) {
+	dir := path.Join("migrations", driver)
+	entries, err := migrationFS.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("no schema migrations for driver %s: %w", driver, err)
+	}
+	migrations := []migration{}
+	for _, entry := range entries {
+		name := entry.Name()
+		sep := strings.Index(name, "_")
+		if sep < 0 || !strings.HasSuffix(name, ".sql") {
+			return nil, fmt.Errorf("bad migration file name: %s", name)
+		}
+		version, err := strconv.Atoi(name[:sep])
+		if err != nil {
+			return nil, fmt.Errorf("bad migration file name: %s", name)
+		}
+		content, err := migrationFS.ReadFile(path.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, migration{Version: version, Name: name, Statements: splitStatements(string(content))})
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration versions of %s should be continuous from 1, but got %s", driver, m.Name)
+		}
+	}
+	return migrations, nil
+}
+
+// splitStatements splits the sql script by ";", the comment lines are removed
+func splitStatements(script string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(script, "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "--") {
+			lines = append(lines, line)
+		}
+	}
+	statements := []string{}
+	for _, s := range strings.Split(strings.Join(lines, "\n"), ";") {
+		if s = strings.TrimSpace(s); s != "" {
+			statements = append(statements, s)
+		}
+	}
+	return statements
+}
+
+func isIgnoredMigrationError(err error) bool {
+	var me *mysql.MySQLError
+	return errors.As(err, &me) && mysqlIgnoredErrors[me.Number]
+}
+
+// SchemaVersion returns the schema version recorded in the kv table, and the latest version of the migrations.
+// the current version is 0 if the schema is created by a dtm without migrations, or not created at all
+func (s *Store) SchemaVersion(ctx context.Context) (int, int, error) {
+	migrations, err := loadMigrations(s.storeConf.Driver)
+	if err != nil {
+		return 0, 0, err
+	}
+	current, err := s.currentSchemaVersion(ctx)
+	return current, len(migrations), err
+}
+
+// MigrateSchema applies the migrations newer than the recorded schema version, the version is recorded after each migration.
+// nothing is done if the schema is already the latest, or newer than the latest
+func (s *Store) MigrateSchema(ctx context.Context) error {
+	migrations, err := loadMigrations(s.storeConf.Driver)
+	if err != nil {
+		return err
+	}
+	current, err := s.currentSchemaVersion(ctx)
+	if err != nil || current >= len(migrations) {
+		return err
+	}
+	for _, m := range migrations[current:] {
+		logger.Infof("applying schema migration %s", m.Name)
+		for _, stmt := range m.Statements {
+			err := s.dbWith(ctx).Exec(stmt).Error
+			if err != nil && !isIgnoredMigrationError(err) {
+				return fmt.Errorf("schema migration %s failed: %w", m.Name, err)
+			}
+		}
+		if err := s.saveSchemaVersion(ctx, m.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) currentSchemaVersion(ctx context.Context) (int, error) {
+	db := s.dbWith(ctx)
+	if !db.Migrator().HasTable(&kvStore{}) {
+		return 0, ctx.Err()
+	}
+	kv := &kvStore{}
+	err := db.Where("cat = ? and k = ?", schemaCat, schemaKey).First(kv).Error
+	if err == gorm.ErrRecordNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	version, err := strconv.Atoi(kv.V)
+	if err != nil {
+		return 0, fmt.Errorf("bad schema version %q in kv table", kv.V)
+	}
+	return version, nil
+}
+
+func (s *Store) saveSchemaVersion(ctx context.Context, version int) error {
+	now := time.Now()
+	kv := &kvStore{Cat: schemaCat, K: schemaKey, V: strconv.Itoa(version), Version: 1, CreateTime: &now, UpdateTime: &now}
+	return s.dbWith(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "cat"}, {Name: "k"}},
+		DoUpdates: clause.AssignmentColumns([]string{"v", "update_time"}),
+	}).Create(kv).Error
+}
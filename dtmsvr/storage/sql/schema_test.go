@@ -0,0 +1,58 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package sql
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	ms, err := loadMigrations("mysql")
+	assert.Nil(t, err)
+	ps, err := loadMigrations("postgres")
+	assert.Nil(t, err)
+	assert.Equal(t, len(ms), len(ps)) // the versions of the drivers are the same
+	for i := range ms {
+		assert.Equal(t, i+1, ms[i].Version)
+		assert.Equal(t, ms[i].Name, ps[i].Name)
+		assert.NotEmpty(t, ms[i].Statements)
+		assert.NotEmpty(t, ps[i].Statements)
+	}
+	_, err = loadMigrations("redis")
+	assert.Error(t, err)
+}
+
+func TestSplitStatements(t *testing.T) {
+	stmts := splitStatements("-- comment; with semicolon\nCREATE TABLE a (\n  id int\n);\n\nALTER TABLE a ADD COLUMN b int;\n")
+	assert.Equal(t, []string{"CREATE TABLE a (\n  id int\n)", "ALTER TABLE a ADD COLUMN b int"}, stmts)
+}
+
+func TestIgnoredMigrationError(t *testing.T) {
+	assert.True(t, isIgnoredMigrationError(&mysql.MySQLError{Number: 1060, Message: "Duplicate column name"}))
+	assert.False(t, isIgnoredMigrationError(&mysql.MySQLError{Number: 1064, Message: "syntax error"}))
+	assert.False(t, isIgnoredMigrationError(errors.New("other")))
+}
+
+func TestHistoryColumns(t *testing.T) {
+	for _, driver := range []string{"mysql", "postgres"} {
+		content, err := migrationFS.ReadFile("migrations/" + driver + "/0006_history_tables.sql")
+		assert.Nil(t, err)
+		tables := strings.Split(string(content), "CREATE TABLE")
+		assert.Len(t, tables, 3)
+		for i, table := range []string{"trans_global", "trans_branch_op"} {
+			assert.Contains(t, tables[i+1], table+"_history")
+			for _, column := range strings.Split(historyColumns[table], ", ") {
+				assert.Regexp(t, "\n  `?"+column+"`? ", tables[i+1], "%s %s", driver, column) // every archived column is in the history table
+			}
+		}
+	}
+}
@@ -7,15 +7,16 @@
 package sql
 
 import (
+	"context"
 	"fmt"
-	"math"
+	"strings"
 	"time"
 
+	"github.com/dtm-labs/dtm/client/dtmcli"
 	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
 	"github.com/dtm-labs/dtm/dtmsvr/config"
 	"github.com/dtm-labs/dtm/dtmsvr/storage"
 	"github.com/dtm-labs/dtm/dtmutil"
-	"github.com/lithammer/shortuuid/v3"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
@@ -24,59 +25,113 @@ var conf = &config.Config
 
 // Store implements storage.Store, and storage with db
 type Store struct {
+	storeConf *config.Store
+}
+
+// NewStore returns the db store with the conf
+func NewStore(storeConf *config.Store) *Store {
+	return &Store{storeConf: storeConf}
 }
 
 // Ping execs ping cmd to db
-func (s *Store) Ping() error {
-	db, err := dtmimp.StandaloneDB(conf.Store.GetDBConf())
-	dtmimp.E2P(err)
-	_, err = db.Exec("select 1")
+func (s *Store) Ping(ctx context.Context) error {
+	db, err := dtmimp.StandaloneDB(s.storeConf.GetDBConf())
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	_, err = db.ExecContext(ctx, "select 1")
 	return err
 }
 
-// PopulateData populates data to db
-func (s *Store) PopulateData(skipDrop bool) {
-	file := fmt.Sprintf("%s/dtmsvr.storage.%s.sql", dtmutil.GetSQLDir(), conf.Store.Driver)
-	dtmutil.RunSQLScript(conf.Store.GetDBConf(), file, skipDrop)
+// PopulateData populates data to db, and then migrates the schema, so that the missing columns of the existing tables are added if skipDrop
+func (s *Store) PopulateData(ctx context.Context, skipDrop bool) error {
+	file := fmt.Sprintf("%s/dtmsvr.storage.%s.sql", dtmutil.GetSQLDir(), s.storeConf.Driver)
+	err := dtmimp.CatchP(func() {
+		dtmutil.RunSQLScript(s.storeConf.GetDBConf(), file, skipDrop)
+	})
+	if err != nil {
+		return err
+	}
+	if s.storeConf.Driver == config.Mysql && s.storeConf.Db == "" { // the mysql script populates the database dtm
+		storeConf := *s.storeConf
+		storeConf.Db = "dtm"
+		return NewStore(&storeConf).MigrateSchema(ctx)
+	}
+	return s.MigrateSchema(ctx)
 }
 
 // FindTransGlobalStore finds GlobalTrans data by gid
-func (s *Store) FindTransGlobalStore(gid string) *storage.TransGlobalStore {
+func (s *Store) FindTransGlobalStore(ctx context.Context, gid string) (*storage.TransGlobalStore, error) {
 	trans := &storage.TransGlobalStore{}
-	dbr := dbGet().Model(trans).Where("gid=?", gid).First(trans)
-	if dbr.Error == gorm.ErrRecordNotFound {
-		return nil
+	err := s.dbWith(ctx).Model(trans).Where("gid=?", gid).First(trans).Error
+	if err != nil {
+		return nil, wrapError(err)
 	}
-	dtmimp.E2P(dbr.Error)
-	return trans
+	return trans, nil
 }
 
 // ScanTransGlobalStores lists GlobalTrans data
-func (s *Store) ScanTransGlobalStores(position *string, limit int64) []storage.TransGlobalStore {
+func (s *Store) ScanTransGlobalStores(ctx context.Context, position *string, limit int64, condition storage.TransGlobalScanCondition) ([]storage.TransGlobalStore, error) {
 	globals := []storage.TransGlobalStore{}
-	lid := math.MaxInt64
+	query := s.dbWith(ctx)
+	if condition.Status != "" {
+		query = query.Where("status = ?", condition.Status)
+	}
+	if condition.TransType != "" {
+		query = query.Where("trans_type = ?", condition.TransType)
+	}
+	if condition.GidPrefix != "" {
+		query = query.Where("gid like ?", escapeLike(condition.GidPrefix)+"%")
+	}
+	if !condition.CreateTimeStart.IsZero() {
+		query = query.Where("create_time >= ?", condition.CreateTimeStart)
+	}
+	if !condition.CreateTimeEnd.IsZero() {
+		query = query.Where("create_time < ?", condition.CreateTimeEnd)
+	}
+	if !condition.UpdateTimeStart.IsZero() {
+		query = query.Where("update_time >= ?", condition.UpdateTimeStart)
+	}
+	if !condition.UpdateTimeEnd.IsZero() {
+		query = query.Where("update_time < ?", condition.UpdateTimeEnd)
+	}
+	if condition.StuckSeconds > 0 {
+		query = query.Where("status not in ? and update_time < ?",
+			[]string{dtmcli.StatusSucceed, dtmcli.StatusFailed}, condition.StuckBefore(time.Now()))
+	}
+	if condition.Unfinished {
+		query = query.Where("status not in ?", []string{dtmcli.StatusSucceed, dtmcli.StatusFailed})
+	}
+	sortBy, cmp, order := condition.GetSortBy(), "<", "desc"
+	if condition.SortAsc {
+		cmp, order = ">", "asc"
+	}
 	if *position != "" {
-		lid = dtmimp.MustAtoi(*position)
+		posTime, posGid, err := storage.DecodePosition(*position)
+		if err != nil {
+			return nil, err
+		}
+		query = query.Where(fmt.Sprintf("(%s %s ? or (%s = ? and gid %s ?))", sortBy, cmp, sortBy, cmp), posTime, posTime, posGid)
 	}
-	dbr := dbGet().Must().Where("id < ?", lid).Order("id desc").Limit(int(limit)).Find(&globals)
-	if dbr.RowsAffected < limit {
-		*position = ""
-	} else {
-		*position = fmt.Sprintf("%d", globals[len(globals)-1].ID)
+	err := query.Order(sortBy + " " + order).Order("gid " + order).Limit(int(limit)).Find(&globals).Error
+	if err != nil {
+		return nil, err
 	}
-	return globals
+	condition.UpdatePosition(globals, position, limit)
+	return globals, nil
 }
 
 // FindBranches finds Branch data by gid
-func (s *Store) FindBranches(gid string) []storage.TransBranchStore {
+func (s *Store) FindBranches(ctx context.Context, gid string) ([]storage.TransBranchStore, error) {
 	branches := []storage.TransBranchStore{}
-	dbGet().Must().Where("gid=?", gid).Order("id asc").Find(&branches)
-	return branches
+	err := s.dbWith(ctx).Where("gid=?", gid).Order("id asc").Find(&branches).Error
+	return branches, err
 }
 
 // UpdateBranches update branches info
-func (s *Store) UpdateBranches(branches []storage.TransBranchStore, updates []string) (int, error) {
-	db := dbGet().Clauses(clause.OnConflict{
+func (s *Store) UpdateBranches(ctx context.Context, branches []storage.TransBranchStore, updates []string) (int, error) {
+	db := s.dbWith(ctx).Clauses(clause.OnConflict{
 		OnConstraint: "gid_branch_uniq",
 		DoUpdates:    clause.AssignmentColumns(updates),
 	}).Create(branches)
@@ -84,8 +139,8 @@ func (s *Store) UpdateBranches(branches []storage.TransBranchStore, updates []st
 }
 
 // LockGlobalSaveBranches creates branches
-func (s *Store) LockGlobalSaveBranches(gid string, status string, branches []storage.TransBranchStore, branchStart int) {
-	err := dbGet().Transaction(func(tx *gorm.DB) error {
+func (s *Store) LockGlobalSaveBranches(ctx context.Context, gid string, status string, branches []storage.TransBranchStore, branchStart int) error {
+	return s.dbWith(ctx).Transaction(func(tx *gorm.DB) error {
 		g := &storage.TransGlobalStore{}
 		dbr := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Model(g).Where("gid=? and status=?", gid, status).First(g)
 		if dbr.Error == nil {
@@ -97,110 +152,225 @@ func (s *Store) LockGlobalSaveBranches(gid string, status string, branches []sto
 		}
 		return wrapError(dbr.Error)
 	})
-	dtmimp.E2P(err)
 }
 
 // MaySaveNewTrans creates a new trans
-func (s *Store) MaySaveNewTrans(global *storage.TransGlobalStore, branches []storage.TransBranchStore) error {
-	return dbGet().Transaction(func(db1 *gorm.DB) error {
-		db := &dtmutil.DB{DB: db1}
-		dbr := db.Must().Clauses(clause.OnConflict{
+func (s *Store) MaySaveNewTrans(ctx context.Context, global *storage.TransGlobalStore, branches []storage.TransBranchStore) error {
+	return s.dbWith(ctx).Transaction(func(db *gorm.DB) error {
+		dbr := db.Clauses(clause.OnConflict{
 			DoNothing: true,
 		}).Create(global)
+		if dbr.Error != nil {
+			return dbr.Error
+		}
 		if dbr.RowsAffected <= 0 { // not a new trans, return
 			return storage.ErrUniqueConflict
 		}
 		if len(branches) > 0 {
-			db.Must().Clauses(clause.OnConflict{
+			return db.Clauses(clause.OnConflict{
 				DoNothing: true,
-			}).Create(&branches)
+			}).Create(&branches).Error
 		}
 		return nil
 	})
 }
 
 // ChangeGlobalStatus changes global trans status
-func (s *Store) ChangeGlobalStatus(global *storage.TransGlobalStore, newStatus string, updates []string, finished bool) {
+func (s *Store) ChangeGlobalStatus(ctx context.Context, global *storage.TransGlobalStore, newStatus string, updates []string, finished bool) error {
 	old := global.Status
 	global.Status = newStatus
-	dbr := dbGet().Must().Model(global).Where("status=? and gid=?", old, global.Gid).Select(updates).Updates(global)
+	dbr := s.dbWith(ctx).Model(global).Where("status=? and gid=?", old, global.Gid).Select(updates).Updates(global)
+	if dbr.Error != nil {
+		return dbr.Error
+	}
 	if dbr.RowsAffected == 0 {
-		dtmimp.E2P(storage.ErrNotFound)
+		return storage.ErrNotFound
 	}
+	return nil
 }
 
 // TouchCronTime updates cronTime
-func (s *Store) TouchCronTime(global *storage.TransGlobalStore, nextCronInterval int64, nextCronTime *time.Time) {
+func (s *Store) TouchCronTime(ctx context.Context, global *storage.TransGlobalStore, nextCronInterval int64, nextCronTime *time.Time) error {
 	global.UpdateTime = dtmutil.GetNextTime(0)
 	global.NextCronTime = nextCronTime
 	global.NextCronInterval = nextCronInterval
-	dbGet().Must().Model(global).Where("status=? and gid=?", global.Status, global.Gid).
-		Select([]string{"next_cron_time", "update_time", "next_cron_interval"}).Updates(global)
+	return s.dbWith(ctx).Model(global).Where("status=? and gid=?", global.Status, global.Gid).
+		Select([]string{"next_cron_time", "update_time", "next_cron_interval"}).Updates(global).Error
 }
 
 // LockOneGlobalTrans finds GlobalTrans
-func (s *Store) LockOneGlobalTrans(expireIn time.Duration) *storage.TransGlobalStore {
-	db := dbGet()
-	owner := shortuuid.New()
-	nextCronTime := getTimeStr(int64(expireIn / time.Second))
-	where := map[string]string{
-		dtmimp.DBTypeMysql:    fmt.Sprintf(`next_cron_time < '%s' and status in ('prepared', 'aborting', 'submitted') limit 1`, nextCronTime),
-		dtmimp.DBTypePostgres: fmt.Sprintf(`id in (select id from trans_global where next_cron_time < '%s' and status in ('prepared', 'aborting', 'submitted') limit 1 )`, nextCronTime),
-	}[conf.Store.Driver]
+func (s *Store) LockOneGlobalTrans(ctx context.Context, expireIn time.Duration) (*storage.TransGlobalStore, error) {
+	globals, err := s.LockGlobalTransBatch(ctx, expireIn, 1)
+	if len(globals) == 0 {
+		return nil, err
+	}
+	return &globals[0], err
+}
 
-	sql := fmt.Sprintf(`UPDATE trans_global SET update_time='%s',next_cron_time='%s', owner='%s' WHERE %s`,
-		getTimeStr(0),
-		getTimeStr(conf.RetryInterval),
-		owner,
-		where)
-	affected, err := dtmimp.DBExec(conf.Store.Driver, db.ToSQLDB(), sql)
+// LockGlobalTransBatch locks the global trans by updating their owner in one statement, then finds them by the owner
+func (s *Store) LockGlobalTransBatch(ctx context.Context, expireIn time.Duration, limit int) ([]storage.TransGlobalStore, error) {
+	db := s.dbWith(ctx)
+	owner := lockOwner(storage.LockOwnerFromContext(ctx))
+	// only the limit clause differs by the driver, the values are bound as parameters
+	where := map[string]string{
+		dtmimp.DBTypeMysql:    fmt.Sprintf(`next_cron_time < ? and (status in ('prepared', 'aborting', 'submitted') or notify_status = 'prepared') limit %d`, limit),
+		dtmimp.DBTypePostgres: fmt.Sprintf(`id in (select id from trans_global where next_cron_time < ? and (status in ('prepared', 'aborting', 'submitted') or notify_status = 'prepared') limit %d )`, limit),
+	}[s.storeConf.Driver]
 
-	dtmimp.PanicIf(err != nil, err)
-	if affected == 0 {
-		return nil
+	sql := `UPDATE trans_global SET update_time=?, next_cron_time=?, owner=? WHERE ` + where
+	globals := []storage.TransGlobalStore{}
+	dbr := db.Exec(sql, getTimeStr(0), getTimeStr(conf.RetryInterval), owner, getTimeStr(int64(expireIn/time.Second)))
+	if dbr.Error != nil || dbr.RowsAffected == 0 {
+		return globals, dbr.Error
 	}
-	global := &storage.TransGlobalStore{}
-	db.Must().Where("owner=?", owner).First(global)
-	return global
+	err := db.Where("owner=?", owner).Order("id").Find(&globals).Error
+	return globals, err
 }
 
 // ResetCronTime reset nextCronTime
 // unfinished transactions need to be retried as soon as possible after business downtime is recovered
-func (s *Store) ResetCronTime(after time.Duration, limit int64) (succeedCount int64, hasRemaining bool, err error) {
+func (s *Store) ResetCronTime(ctx context.Context, after time.Duration, limit int64) (succeedCount int64, hasRemaining bool, err error) {
 	nextCronTime := getTimeStr(int64(after / time.Second))
 	where := map[string]string{
 		dtmimp.DBTypeMysql:    fmt.Sprintf(`next_cron_time > '%s' and status in ('prepared', 'aborting', 'submitted') limit %d`, nextCronTime, limit),
 		dtmimp.DBTypePostgres: fmt.Sprintf(`id in (select id from trans_global where next_cron_time > '%s' and status in ('prepared', 'aborting', 'submitted') limit %d )`, nextCronTime, limit),
-	}[conf.Store.Driver]
+	}[s.storeConf.Driver]
 
 	sql := fmt.Sprintf(`UPDATE trans_global SET update_time='%s',next_cron_time='%s' WHERE %s`,
 		getTimeStr(0),
 		getTimeStr(0),
 		where)
-	affected, err := dtmimp.DBExec(conf.Store.Driver, dbGet().ToSQLDB(), sql)
-	return affected, affected == limit, err
+	dbr := s.dbWith(ctx).Exec(sql)
+	return dbr.RowsAffected, dbr.RowsAffected == limit, dbr.Error
+}
+
+// SaveTransEvents saves the status transitions
+func (s *Store) SaveTransEvents(ctx context.Context, events []storage.TransEventStore) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return s.dbWith(ctx).Create(&events).Error
+}
+
+// FindTransEvents finds the status transitions by gid, in the order of occurrence
+func (s *Store) FindTransEvents(ctx context.Context, gid string) ([]storage.TransEventStore, error) {
+	events := []storage.TransEventStore{}
+	err := s.dbWith(ctx).Where("gid=?", gid).Order("id asc").Find(&events).Error
+	return events, err
+}
+
+// GetTransStats aggregates the statistics of transactions in db
+func (s *Store) GetTransStats(ctx context.Context, topURLs int) (*storage.TransStats, error) {
+	stats := &storage.TransStats{Counts: []storage.TransCount{}, FailingURLs: []storage.URLCount{}}
+	db := s.dbWith(ctx)
+	err := db.Model(&storage.TransGlobalStore{}).Select("status, trans_type, count(*) as count").
+		Group("status, trans_type").Order("status, trans_type").Scan(&stats.Counts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	oldest := []storage.TransGlobalStore{}
+	err = db.Select("create_time").Where("status in ?", storage.UnfinishedStatuses).Order("create_time asc").Limit(1).Find(&oldest).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(oldest) > 0 {
+		stats.OldestUnfinishedTime = oldest[0].CreateTime
+	}
+
+	err = db.Model(&storage.TransGlobalStore{}).
+		Where("next_cron_time < ? and (status in ? or notify_status = ?)", dtmutil.GetNextTime(0), storage.UnfinishedStatuses, dtmcli.StatusPrepared).
+		Count(&stats.CronLagCount).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Table("trans_branch_op b").Select("b.url, count(*) as count").
+		Joins("join trans_global g on g.gid = b.gid").
+		Where("g.status in ? and b.status = ? and b.last_error <> ''", storage.UnfinishedStatuses, dtmcli.StatusPrepared).
+		Group("b.url").Order("count desc, b.url").Limit(topURLs).Scan(&stats.FailingURLs).Error
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// historyColumns are the columns copied to the history tables. they are listed explicitly,
+// so that the archiving does not depend on the physical order of the columns
+var historyColumns = map[string]string{
+	"trans_global": "id, gid, trans_type, status, query_prepared, protocol, create_time, update_time, finish_time, rollback_time, " +
+		"options, custom_data, next_cron_interval, next_cron_time, owner, ext_data, result, rollback_reason, notify_status",
+	"trans_branch_op": "id, gid, url, data, bin_data, branch_id, op, status, finish_time, rollback_time, create_time, update_time, " +
+		"attempts, last_error, last_status_code, last_response",
+}
+
+// PurgeFinished removes the finished transactions with their branches and events.
+// the global transactions and branches are moved to trans_global_history and trans_branch_op_history if archive is true
+func (s *Store) PurgeFinished(ctx context.Context, before time.Time, limit int64, archive bool) (int64, error) {
+	gids := []string{}
+	err := s.dbWith(ctx).Model(&storage.TransGlobalStore{}).
+		Where("status in ? and notify_status <> ? and update_time < ?",
+			[]string{dtmcli.StatusSucceed, dtmcli.StatusFailed}, dtmcli.StatusPrepared, before).
+		Order("update_time").Limit(int(limit)).Pluck("gid", &gids).Error
+	if err != nil || len(gids) == 0 {
+		return 0, err
+	}
+	err = s.dbWith(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, table := range []string{"trans_global", "trans_branch_op", "trans_event"} {
+			if archive && table != "trans_event" {
+				columns := historyColumns[table]
+				err := tx.Exec(fmt.Sprintf("insert into %s_history (%s) select %s from %s where gid in ?", table, columns, columns, table), gids).Error
+				if err != nil {
+					return err
+				}
+			}
+			err := tx.Exec(fmt.Sprintf("delete from %s where gid in ?", table), gids).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(gids)), nil
 }
 
 // SetDBConn sets db conn pool
 func SetDBConn(db *gorm.DB) {
+	setDBConn(db, &conf.Store)
+}
+
+func setDBConn(db *gorm.DB, storeConf *config.Store) {
 	sqldb, _ := db.DB()
-	sqldb.SetMaxOpenConns(int(conf.Store.MaxOpenConns))
-	sqldb.SetMaxIdleConns(int(conf.Store.MaxIdleConns))
-	sqldb.SetConnMaxLifetime(time.Duration(conf.Store.ConnMaxLifeTime) * time.Minute)
+	sqldb.SetMaxOpenConns(int(storeConf.MaxOpenConns))
+	sqldb.SetMaxIdleConns(int(storeConf.MaxIdleConns))
+	sqldb.SetConnMaxLifetime(time.Duration(storeConf.ConnMaxLifeTime) * time.Minute)
 }
 
-func dbGet() *dtmutil.DB {
-	return dtmutil.DbGet(conf.Store.GetDBConf(), SetDBConn)
+func (s *Store) dbGet() *dtmutil.DB {
+	return dtmutil.DbGet(s.storeConf.GetDBConf(), func(db *gorm.DB) {
+		setDBConn(db, s.storeConf)
+	})
+}
+
+// dbWith returns the db which is canceled with ctx
+func (s *Store) dbWith(ctx context.Context) *gorm.DB {
+	return s.dbGet().WithContext(ctx)
 }
 
 func wrapError(err error) error {
 	if err == gorm.ErrRecordNotFound {
 		return storage.ErrNotFound
 	}
-	dtmimp.E2P(err)
 	return err
 }
 
 func getTimeStr(afterSecond int64) string {
 	return dtmutil.GetNextTime(afterSecond).Format("2006-01-02 15:04:05")
 }
+
+func escapeLike(s string) string {
+	return strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_").Replace(s)
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dtm-labs/dtm/client/dtmcli"
+)
+
+// TransStats is the statistics of the transactions in the store
+type TransStats struct {
+	Counts               []TransCount `json:"counts"`                           // grouped by status and trans_type
+	OldestUnfinishedTime *time.Time   `json:"oldest_unfinished_time,omitempty"` // create time of the oldest unfinished transaction
+	CronLagCount         int64        `json:"cron_lag_count"`                   // number of transactions past their next_cron_time
+	FailingURLs          []URLCount   `json:"failing_urls"`                     // the urls with the most failing branches, in descending order
+}
+
+// TransCount is the number of transactions with the status and trans_type
+type TransCount struct {
+	Status    string `json:"status"`
+	TransType string `json:"trans_type"`
+	Count     int64  `json:"count"`
+}
+
+// URLCount is the number of failing branches with the url
+type URLCount struct {
+	URL   string `json:"url"`
+	Count int64  `json:"count"`
+}
+
+// UnfinishedStatuses are the statuses of the transactions that will be processed by dtm
+var UnfinishedStatuses = []string{dtmcli.StatusPrepared, dtmcli.StatusSubmitted, dtmcli.StatusAborting}
+
+// IsFailingBranch returns true if the last call of the branch failed, and dtm will call it again
+func IsFailingBranch(b *TransBranchStore) bool {
+	return b.Status == dtmcli.StatusPrepared && b.LastError != ""
+}
+
+// TopURLCounts returns the top n urls in descending order of count. urls with zero count are ignored
+func TopURLCounts(counts map[string]int64, n int) []URLCount {
+	urls := []URLCount{}
+	for url, count := range counts {
+		if count > 0 {
+			urls = append(urls, URLCount{URL: url, Count: count})
+		}
+	}
+	sort.Slice(urls, func(i, j int) bool {
+		if urls[i].Count != urls[j].Count {
+			return urls[i].Count > urls[j].Count
+		}
+		return urls[i].URL < urls[j].URL
+	})
+	if len(urls) > n {
+		urls = urls[:n]
+	}
+	return urls
+}
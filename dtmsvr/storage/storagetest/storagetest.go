@@ -0,0 +1,383 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+// Package storagetest provides the conformance tests of storage.Store, which any store implementation can run:
+//
+//	func TestConformance(t *testing.T) {
+//		storagetest.Run(t, func() storage.Store { return mystore.NewStore(...) })
+//	}
+//
+// the data of the store is reset by PopulateData before each test, so do not run it against a store in use.
+// the retry interval of the store should be greater than 2 seconds, so that a locked trans is not locked again in the tests
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/dtm-labs/dtm/client/dtmcli"
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/dtm/dtmutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var ctx = context.Background()
+
+// lockExpireIn is used to lock the trans to be processed in 2 seconds
+const lockExpireIn = 2 * time.Second
+
+var cases = []struct {
+	name string
+	fn   func(t *testing.T, s storage.Store)
+}{
+	{"SaveAndFind", testSaveAndFind},
+	{"SaveBranches", testSaveBranches},
+	{"UpdateBranches", testUpdateBranches},
+	{"ChangeGlobalStatus", testChangeGlobalStatus},
+	{"LockOneGlobalTrans", testLockOneGlobalTrans},
+	{"LockGlobalTransBatch", testLockGlobalTransBatch},
+	{"ResetCronTime", testResetCronTime},
+	{"Scan", testScan},
+	{"Events", testEvents},
+	{"Stats", testStats},
+	{"Canceled", testCanceled},
+	{"Nodes", testNodes},
+}
+
+// Run runs the conformance tests against the stores created by newStore, each test is run with a store reset by PopulateData.
+// the store is closed after the test if it implements io.Closer
+func Run(t *testing.T, newStore func() storage.Store) {
+	for _, c := range cases {
+		fn := c.fn
+		t.Run(c.name, func(t *testing.T) {
+			s := newStore()
+			if closer, ok := s.(io.Closer); ok {
+				defer func() { _ = closer.Close() }()
+			}
+			require.Nil(t, s.Ping(ctx))
+			require.Nil(t, s.PopulateData(ctx, false))
+			fn(t, s)
+		})
+	}
+}
+
+func saveTrans(t *testing.T, s storage.Store, gid string, next time.Duration) *storage.TransGlobalStore {
+	now := time.Now()
+	nextCronTime := now.Add(next)
+	g := &storage.TransGlobalStore{Gid: gid, TransType: "saga", Status: dtmcli.StatusSubmitted, Protocol: "http",
+		NextCronTime: &nextCronTime, NextCronInterval: 10, Options: `{"retry_interval":10}`, ExtData: `{"headers":{"k":"v"}}`}
+	g.CreateTime = &now
+	g.UpdateTime = &now
+	branches := []storage.TransBranchStore{
+		{Gid: gid, BranchID: "01", Op: dtmimp.OpCompensate, URL: "http://localhost/compensate", Status: dtmcli.StatusPrepared},
+		{Gid: gid, BranchID: "01", Op: dtmimp.OpAction, URL: "http://localhost/action", Status: dtmcli.StatusPrepared},
+	}
+	require.Nil(t, s.MaySaveNewTrans(ctx, g, branches))
+	return g
+}
+
+func mustLockOne(t *testing.T, s storage.Store) *storage.TransGlobalStore {
+	g, err := s.LockOneGlobalTrans(ctx, lockExpireIn)
+	assert.Nil(t, err)
+	return g
+}
+
+func testSaveAndFind(t *testing.T, s storage.Store) {
+	g := saveTrans(t, s, "gid1", time.Hour)
+	err := s.MaySaveNewTrans(ctx, g, nil)
+	assert.True(t, errors.Is(err, storage.ErrUniqueConflict), "saving an existing gid should return ErrUniqueConflict, got: %v", err)
+
+	g2, err := s.FindTransGlobalStore(ctx, "gid1")
+	require.Nil(t, err)
+	assert.Equal(t, g.Gid, g2.Gid)
+	assert.Equal(t, g.TransType, g2.TransType)
+	assert.Equal(t, g.Status, g2.Status)
+	assert.Equal(t, g.Protocol, g2.Protocol)
+	assert.Equal(t, g.Options, g2.Options)
+	assert.Equal(t, g.ExtData, g2.ExtData)
+	assert.Equal(t, g.NextCronInterval, g2.NextCronInterval)
+	require.NotNil(t, g2.NextCronTime)
+	assert.Equal(t, g.NextCronTime.Unix(), g2.NextCronTime.Unix())
+
+	_, err = s.FindTransGlobalStore(ctx, "gid-none")
+	assert.True(t, errors.Is(err, storage.ErrNotFound), "finding a missing gid should return ErrNotFound, got: %v", err)
+
+	branches, err := s.FindBranches(ctx, "gid1")
+	assert.Nil(t, err)
+	require.Len(t, branches, 2)
+	assert.Equal(t, dtmimp.OpCompensate, branches[0].Op) // in the order of saving
+	assert.Equal(t, dtmimp.OpAction, branches[1].Op)
+	assert.Equal(t, "http://localhost/action", branches[1].URL)
+	branches, err = s.FindBranches(ctx, "gid-none")
+	assert.Nil(t, err)
+	assert.Empty(t, branches)
+}
+
+func testSaveBranches(t *testing.T, s storage.Store) {
+	g := saveTrans(t, s, "gid1", time.Hour)
+	added := []storage.TransBranchStore{{Gid: g.Gid, BranchID: "02", Op: dtmimp.OpAction, URL: "http://localhost/action2", Status: dtmcli.StatusPrepared}}
+	err := s.LockGlobalSaveBranches(ctx, g.Gid, dtmcli.StatusPrepared, added, -1)
+	assert.True(t, errors.Is(err, storage.ErrNotFound), "saving branches with a mismatched status should return ErrNotFound, got: %v", err)
+	assert.Nil(t, s.LockGlobalSaveBranches(ctx, g.Gid, g.Status, added, -1))
+
+	branches, err := s.FindBranches(ctx, g.Gid)
+	assert.Nil(t, err)
+	require.Len(t, branches, 3)
+	assert.Equal(t, "02", branches[2].BranchID)
+}
+
+func testUpdateBranches(t *testing.T, s storage.Store) {
+	g := saveTrans(t, s, "gid1", time.Hour)
+	now := time.Now()
+	updated := []storage.TransBranchStore{{Gid: g.Gid, BranchID: "01", Op: dtmimp.OpAction, Status: dtmcli.StatusSucceed,
+		FinishTime: &now, Attempts: 1, LastStatusCode: 200}}
+	_, err := s.UpdateBranches(ctx, updated, []string{"status", "finish_time", "attempts", "last_status_code", "update_time"})
+	assert.Nil(t, err)
+
+	branches, err := s.FindBranches(ctx, g.Gid)
+	assert.Nil(t, err)
+	require.Len(t, branches, 2)
+	assert.Equal(t, dtmcli.StatusPrepared, branches[0].Status)
+	assert.Equal(t, dtmcli.StatusSucceed, branches[1].Status)
+	assert.Equal(t, "http://localhost/action", branches[1].URL) // the columns not updated are kept
+	assert.Equal(t, int64(1), branches[1].Attempts)
+	assert.Equal(t, 200, branches[1].LastStatusCode)
+	require.NotNil(t, branches[1].FinishTime)
+	assert.Equal(t, now.Unix(), branches[1].FinishTime.Unix())
+
+	_, err = s.UpdateBranches(ctx, nil, nil)
+	assert.Nil(t, err)
+}
+
+func testChangeGlobalStatus(t *testing.T, s storage.Store) {
+	g := saveTrans(t, s, "gid1", -time.Second)
+	g.Status = dtmcli.StatusPrepared // the status is changed by others
+	err := s.ChangeGlobalStatus(ctx, g, dtmcli.StatusAborting, []string{"status"}, false)
+	assert.True(t, errors.Is(err, storage.ErrNotFound), "changing a trans with a mismatched status should return ErrNotFound, got: %v", err)
+
+	g.Status = dtmcli.StatusSubmitted
+	g.RollbackReason = "reason"
+	assert.Nil(t, s.ChangeGlobalStatus(ctx, g, dtmcli.StatusAborting, []string{"status", "rollback_reason"}, false))
+	g2, err := s.FindTransGlobalStore(ctx, g.Gid)
+	require.Nil(t, err)
+	assert.Equal(t, dtmcli.StatusAborting, g2.Status)
+	assert.Equal(t, "reason", g2.RollbackReason)
+
+	now := time.Now()
+	g.FinishTime = &now
+	assert.Nil(t, s.ChangeGlobalStatus(ctx, g, dtmcli.StatusFailed, []string{"status", "finish_time"}, true))
+	g2, err = s.FindTransGlobalStore(ctx, g.Gid)
+	require.Nil(t, err)
+	assert.Equal(t, dtmcli.StatusFailed, g2.Status)
+	assert.Nil(t, mustLockOne(t, s)) // the finished trans is not locked by cron
+}
+
+func testLockOneGlobalTrans(t *testing.T, s storage.Store) {
+	assert.Nil(t, mustLockOne(t, s))
+	g := saveTrans(t, s, "gid1", time.Hour)
+	assert.Nil(t, mustLockOne(t, s))
+
+	next := time.Now().Add(-time.Second)
+	assert.Nil(t, s.TouchCronTime(ctx, g, 20, &next))
+	g2 := mustLockOne(t, s)
+	require.NotNil(t, g2)
+	assert.Equal(t, g.Gid, g2.Gid)
+	assert.Equal(t, int64(20), g2.NextCronInterval)
+	assert.Nil(t, mustLockOne(t, s)) // the locked trans is delayed by the retry interval
+}
+
+func testLockGlobalTransBatch(t *testing.T, s storage.Store) {
+	for i := 0; i < 5; i++ {
+		saveTrans(t, s, fmt.Sprintf("batch-%d", i), -time.Second)
+	}
+	saveTrans(t, s, "batch-later", time.Hour)
+	gids := map[string]bool{}
+	for _, limit := range []int{2, 2, 2} {
+		globals, err := storage.LockGlobalTransBatch(ctx, s, lockExpireIn, limit)
+		require.Nil(t, err)
+		assert.LessOrEqual(t, len(globals), limit)
+		for _, g := range globals {
+			assert.False(t, gids[g.Gid], "%s is locked twice", g.Gid)
+			gids[g.Gid] = true
+		}
+	}
+	assert.Len(t, gids, 5)
+	assert.False(t, gids["batch-later"])
+	globals, err := storage.LockGlobalTransBatch(ctx, s, lockExpireIn, 2)
+	assert.Nil(t, err)
+	assert.Empty(t, globals) // the locked trans are delayed by the retry interval
+}
+
+func testResetCronTime(t *testing.T, s storage.Store) {
+	saveTrans(t, s, "gid1", 110*time.Second)
+	saveTrans(t, s, "gid2", 120*time.Second)
+	saveTrans(t, s, "gid3", 90*time.Second)
+	assert.Nil(t, mustLockOne(t, s))
+
+	count, hasRemaining, err := s.ResetCronTime(ctx, 100*time.Second, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.True(t, hasRemaining)
+	count, hasRemaining, err = s.ResetCronTime(ctx, 100*time.Second, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.False(t, hasRemaining)
+
+	gids := map[string]bool{}
+	for g := mustLockOne(t, s); g != nil; g = mustLockOne(t, s) {
+		gids[g.Gid] = true
+	}
+	assert.Equal(t, map[string]bool{"gid1": true, "gid2": true}, gids) // gid3 is not reset
+}
+
+func testScan(t *testing.T, s storage.Store) {
+	for i := 0; i < 3; i++ {
+		saveTrans(t, s, fmt.Sprintf("scan-%d", i), time.Hour)
+		time.Sleep(time.Millisecond)
+	}
+	saveTrans(t, s, "other", time.Hour)
+	condition := storage.TransGlobalScanCondition{GidPrefix: "scan-", SortBy: storage.SortByCreateTime, SortAsc: true}
+	gids := []string{}
+	for position, pages := "", 0; ; pages++ {
+		require.True(t, pages < 3, "too many pages scanned")
+		globals, err := s.ScanTransGlobalStores(ctx, &position, 2, condition)
+		require.Nil(t, err)
+		assert.True(t, len(globals) <= 2)
+		for _, g := range globals {
+			gids = append(gids, g.Gid)
+		}
+		if position == "" {
+			break
+		}
+	}
+	assert.Equal(t, []string{"scan-0", "scan-1", "scan-2"}, gids)
+
+	// the desc pages are seeked from the position
+	condition.SortAsc = false
+	gids = []string{}
+	for position := ""; ; {
+		globals, err := s.ScanTransGlobalStores(ctx, &position, 1, condition)
+		require.Nil(t, err)
+		for _, g := range globals {
+			gids = append(gids, g.Gid)
+		}
+		if position == "" {
+			break
+		}
+	}
+	assert.Equal(t, []string{"scan-2", "scan-1", "scan-0"}, gids)
+
+	// the index of update_time follows the updates
+	g, err := s.FindTransGlobalStore(ctx, "scan-0")
+	require.Nil(t, err)
+	time.Sleep(time.Millisecond)
+	require.Nil(t, s.TouchCronTime(ctx, g, 10, dtmutil.GetNextTime(10)))
+	position := ""
+	globals, err := s.ScanTransGlobalStores(ctx, &position, 1, storage.TransGlobalScanCondition{GidPrefix: "scan-", SortBy: storage.SortByUpdateTime})
+	require.Nil(t, err)
+	require.Len(t, globals, 1)
+	assert.Equal(t, "scan-0", globals[0].Gid)
+
+	position = ""
+	globals, err = s.ScanTransGlobalStores(ctx, &position, 10, storage.TransGlobalScanCondition{Status: dtmcli.StatusSucceed})
+	assert.Nil(t, err)
+	assert.Empty(t, globals)
+	assert.Equal(t, "", position)
+
+	globals, err = s.ScanTransGlobalStores(ctx, &position, 10, storage.TransGlobalScanCondition{GidPrefix: "scan-", Unfinished: true})
+	assert.Nil(t, err)
+	assert.Len(t, globals, 3)
+}
+
+func testEvents(t *testing.T, s storage.Store) {
+	saveTrans(t, s, "gid1", time.Hour)
+	now := time.Now()
+	events := []storage.TransEventStore{
+		{Gid: "gid1", NewStatus: dtmcli.StatusSubmitted, Source: "api", CreateTime: &now},
+		{Gid: "gid1", BranchID: "01", Op: dtmimp.OpAction, OldStatus: dtmcli.StatusPrepared, NewStatus: dtmcli.StatusSucceed, CreateTime: &now},
+		{Gid: "gid1", OldStatus: dtmcli.StatusSubmitted, NewStatus: dtmcli.StatusSucceed, CreateTime: &now},
+	}
+	assert.Nil(t, s.SaveTransEvents(ctx, events))
+	assert.Nil(t, s.SaveTransEvents(ctx, nil))
+
+	found, err := s.FindTransEvents(ctx, "gid1")
+	assert.Nil(t, err)
+	require.Len(t, found, 3)
+	for i := range events {
+		assert.Equal(t, events[i].BranchID, found[i].BranchID) // in the order of occurrence
+		assert.Equal(t, events[i].NewStatus, found[i].NewStatus)
+	}
+	found, err = s.FindTransEvents(ctx, "gid-none")
+	assert.Nil(t, err)
+	assert.Empty(t, found)
+}
+
+func testStats(t *testing.T, s storage.Store) {
+	saveTrans(t, s, "gid1", time.Hour)
+	saveTrans(t, s, "gid2", time.Hour)
+	stats, err := s.GetTransStats(ctx, 10)
+	require.Nil(t, err)
+	total := int64(0)
+	for _, c := range stats.Counts {
+		if c.Status == dtmcli.StatusSubmitted && c.TransType == "saga" {
+			total += c.Count
+		}
+	}
+	assert.Equal(t, int64(2), total)
+	assert.NotNil(t, stats.OldestUnfinishedTime)
+}
+
+func testCanceled(t *testing.T, s storage.Store) {
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := s.FindTransGlobalStore(canceled, "gid1")
+	assert.True(t, errors.Is(err, context.Canceled), "the store should be canceled by ctx, got: %v", err)
+}
+
+func testNodes(t *testing.T, s storage.Store) {
+	registry, ok := s.(storage.NodeRegistry)
+	if !ok {
+		t.Skip("the store does not implement storage.NodeRegistry")
+	}
+	now := time.Now().Truncate(time.Second)
+	node := storage.NodeStore{ID: "node1", Version: "v1", Load: 1, StartTime: &now, HeartbeatTime: &now}
+	assert.Nil(t, registry.SaveNode(ctx, &node))
+	node.Load = 3
+	assert.Nil(t, registry.SaveNode(ctx, &node)) // updated
+	assert.Nil(t, registry.SaveNode(ctx, &storage.NodeStore{ID: "node2", StartTime: &now, HeartbeatTime: &now}))
+	nodes, err := registry.FindNodes(ctx)
+	assert.Nil(t, err)
+	require.Len(t, nodes, 2)
+	assert.Equal(t, "node1", nodes[0].ID)
+	assert.Equal(t, int64(3), nodes[0].Load)
+	assert.Equal(t, "v1", nodes[0].Version)
+	assert.Nil(t, registry.RemoveNode(ctx, "node2"))
+	nodes, err = registry.FindNodes(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, nodes, 1)
+
+	saveTrans(t, s, "gid1", -time.Second)
+	saveTrans(t, s, "gid2", -time.Second)
+	g, err := s.LockOneGlobalTrans(storage.WithLockOwner(ctx, "node1"), lockExpireIn)
+	require.Nil(t, err)
+	require.NotNil(t, g)
+	require.NotNil(t, mustLockOne(t, s)) // the other trans is locked without an owner, so it is not reclaimed
+	reclaimed, err := registry.ReclaimTrans(ctx, "node2")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), reclaimed)
+	reclaimed, err = registry.ReclaimTrans(ctx, "node1")
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), reclaimed)
+	g2 := mustLockOne(t, s)
+	require.NotNil(t, g2)
+	assert.Equal(t, g.Gid, g2.Gid) // the reclaimed trans is due now
+}
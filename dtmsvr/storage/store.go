@@ -7,6 +7,7 @@
 package storage
 
 import (
+	"context"
 	"errors"
 	"time"
 )
@@ -17,18 +18,65 @@ var ErrNotFound = errors.New("storage: NotFound")
 // ErrUniqueConflict defines the item is conflict with unique key in storage implement.
 var ErrUniqueConflict = errors.New("storage: UniqueKeyConflict")
 
-// Store defines storage relevant interface
+// Store defines storage relevant interface.
+// the methods are canceled with ctx, and the errors of the store are returned, ErrNotFound and ErrUniqueConflict can be checked by errors.Is
 type Store interface {
-	Ping() error
-	PopulateData(skipDrop bool)
-	FindTransGlobalStore(gid string) *TransGlobalStore
-	ScanTransGlobalStores(position *string, limit int64) []TransGlobalStore
-	FindBranches(gid string) []TransBranchStore
-	UpdateBranches(branches []TransBranchStore, updates []string) (int, error)
-	LockGlobalSaveBranches(gid string, status string, branches []TransBranchStore, branchStart int)
-	MaySaveNewTrans(global *TransGlobalStore, branches []TransBranchStore) error
-	ChangeGlobalStatus(global *TransGlobalStore, newStatus string, updates []string, finished bool)
-	TouchCronTime(global *TransGlobalStore, nextCronInterval int64, nextCronTime *time.Time)
-	LockOneGlobalTrans(expireIn time.Duration) *TransGlobalStore
-	ResetCronTime(after time.Duration, limit int64) (succeedCount int64, hasRemaining bool, err error)
+	Ping(ctx context.Context) error
+	PopulateData(ctx context.Context, skipDrop bool) error
+	// FindTransGlobalStore returns ErrNotFound if the global trans does not exist
+	FindTransGlobalStore(ctx context.Context, gid string) (*TransGlobalStore, error)
+	ScanTransGlobalStores(ctx context.Context, position *string, limit int64, condition TransGlobalScanCondition) ([]TransGlobalStore, error)
+	FindBranches(ctx context.Context, gid string) ([]TransBranchStore, error)
+	UpdateBranches(ctx context.Context, branches []TransBranchStore, updates []string) (int, error)
+	// LockGlobalSaveBranches returns ErrNotFound if the status of the global trans is not status
+	LockGlobalSaveBranches(ctx context.Context, gid string, status string, branches []TransBranchStore, branchStart int) error
+	MaySaveNewTrans(ctx context.Context, global *TransGlobalStore, branches []TransBranchStore) error
+	// ChangeGlobalStatus returns ErrNotFound if the status of the global trans is changed by others
+	ChangeGlobalStatus(ctx context.Context, global *TransGlobalStore, newStatus string, updates []string, finished bool) error
+	TouchCronTime(ctx context.Context, global *TransGlobalStore, nextCronInterval int64, nextCronTime *time.Time) error
+	// LockOneGlobalTrans returns nil if there is no global trans to be processed
+	LockOneGlobalTrans(ctx context.Context, expireIn time.Duration) (*TransGlobalStore, error)
+	ResetCronTime(ctx context.Context, after time.Duration, limit int64) (succeedCount int64, hasRemaining bool, err error)
+	SaveTransEvents(ctx context.Context, events []TransEventStore) error
+	FindTransEvents(ctx context.Context, gid string) ([]TransEventStore, error)
+	GetTransStats(ctx context.Context, topURLs int) (*TransStats, error)
+}
+
+// FinishedPurger is implemented by the stores that keep the finished transactions until they are purged by dtm, such as the sql store.
+// the other stores expire the data by themselves
+type FinishedPurger interface {
+	// PurgeFinished removes at most limit finished transactions which are not updated since before, and returns the number removed.
+	// the transactions are moved to the history tables if archive is true
+	PurgeFinished(ctx context.Context, before time.Time, limit int64, archive bool) (int64, error)
+}
+
+// SchemaMigrator is implemented by the stores with a versioned schema, such as the sql store
+type SchemaMigrator interface {
+	// SchemaVersion returns the schema version recorded in the store, and the latest version known by this binary
+	SchemaVersion(ctx context.Context) (current int, latest int, err error)
+	// MigrateSchema migrates the schema to the latest version
+	MigrateSchema(ctx context.Context) error
+}
+
+// BatchLocker is implemented by the stores which can lock several global trans in one round trip
+type BatchLocker interface {
+	// LockGlobalTransBatch locks at most limit global trans to be processed in expireIn, like LockOneGlobalTrans
+	LockGlobalTransBatch(ctx context.Context, expireIn time.Duration, limit int) ([]TransGlobalStore, error)
+}
+
+// LockGlobalTransBatch locks at most limit global trans of the store in one round trip if the store implements BatchLocker,
+// otherwise they are locked one by one
+func LockGlobalTransBatch(ctx context.Context, s Store, expireIn time.Duration, limit int) ([]TransGlobalStore, error) {
+	if locker, ok := s.(BatchLocker); ok {
+		return locker.LockGlobalTransBatch(ctx, expireIn, limit)
+	}
+	globals := []TransGlobalStore{}
+	for len(globals) < limit {
+		global, err := s.LockOneGlobalTrans(ctx, expireIn)
+		if err != nil || global == nil {
+			return globals, err
+		}
+		globals = append(globals, *global)
+	}
+	return globals, nil
 }
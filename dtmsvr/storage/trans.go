@@ -7,6 +7,7 @@
 package storage
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/dtm-labs/dtm/client/dtmcli"
@@ -40,7 +41,8 @@ type TransGlobalStore struct {
 	NextCronTime     *time.Time          `json:"next_cron_time,omitempty"`
 	Owner            string              `json:"owner,omitempty"`
 	Ext              TransGlobalExt      `json:"-" gorm:"-"`
-	ExtData          string              `json:"ext_data,omitempty"` // storage of ext. a db field to store many values. like Options
+	ExtData          string              `json:"ext_data,omitempty"`      // storage of ext. a db field to store many values. like Options
+	NotifyStatus     string              `json:"notify_status,omitempty"` // status of calling NotifyURL: prepared | succeed, empty if no NotifyURL
 	dtmcli.TransOptions
 }
 
@@ -58,6 +60,11 @@ func (g *TransGlobalStore) IsFinished() bool {
 	return g.Status == dtmcli.StatusFailed || g.Status == dtmcli.StatusSucceed
 }
 
+// IsNotifyPending return true if the trans is finished, but NotifyURL has not been called successfully
+func (g *TransGlobalStore) IsNotifyPending() bool {
+	return g.NotifyStatus == dtmcli.StatusPrepared
+}
+
 // TransBranchStore branch transaction
 type TransBranchStore struct {
 	dtmutil.ModelBase
@@ -70,6 +77,11 @@ type TransBranchStore struct {
 	FinishTime   *time.Time `json:"finish_time,omitempty"`
 	RollbackTime *time.Time `json:"rollback_time,omitempty"`
 	Error        error      `json:"-" gorm:"-"`
+	// the fields below record the calls of the branch url, used to diagnose the stuck transactions
+	Attempts       int64  `json:"attempts,omitempty"`
+	LastError      string `json:"last_error,omitempty"`
+	LastStatusCode int    `json:"last_status_code,omitempty"` // http status code or grpc code of the last call
+	LastResponse   string `json:"last_response,omitempty"`    // truncated response body of the last call
 }
 
 // TableName TableName
@@ -80,3 +92,51 @@ func (b *TransBranchStore) TableName() string {
 func (b *TransBranchStore) String() string {
 	return dtmimp.MustMarshalString(*b)
 }
+
+// UpdateColumns copies the columns of src to b, the columns are named as in the db.
+// it is used by the stores without the sql update
+func (b *TransBranchStore) UpdateColumns(src *TransBranchStore, columns []string) error {
+	for _, column := range columns {
+		switch column {
+		case "status":
+			b.Status = src.Status
+		case "finish_time":
+			b.FinishTime = src.FinishTime
+		case "rollback_time":
+			b.RollbackTime = src.RollbackTime
+		case "update_time":
+			b.UpdateTime = src.UpdateTime
+		case "attempts":
+			b.Attempts = src.Attempts
+		case "last_error":
+			b.LastError = src.LastError
+		case "last_status_code":
+			b.LastStatusCode = src.LastStatusCode
+		case "last_response":
+			b.LastResponse = src.LastResponse
+		default:
+			return fmt.Errorf("column %s can not be updated", column)
+		}
+	}
+	return nil
+}
+
+// TransEventStore records a status transition of a global transaction or a branch
+type TransEventStore struct {
+	ID         uint64     `json:"id,omitempty"`
+	Gid        string     `json:"gid,omitempty"`
+	BranchID   string     `json:"branch_id,omitempty"` // empty for the global transaction
+	Op         string     `json:"op,omitempty"`
+	OldStatus  string     `json:"old_status,omitempty"`
+	NewStatus  string     `json:"new_status,omitempty"`
+	Source     string     `json:"source,omitempty"`   // what triggers the transition, like api, cron, timeout
+	Owner      string     `json:"owner,omitempty"`    // the dtm server that makes the transition
+	Operator   string     `json:"operator,omitempty"` // the operator who makes the transition manually
+	Error      string     `json:"error,omitempty"`
+	CreateTime *time.Time `json:"create_time,omitempty"`
+}
+
+// TableName TableName
+func (e *TransEventStore) TableName() string {
+	return "trans_event"
+}
@@ -10,6 +10,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"time"
 
 	"github.com/dtm-labs/dtm/client/dtmgrpc"
@@ -48,9 +49,10 @@ func StartSvr() *gin.Engine {
 	addRoute(app)
 	addJrpcRouter(app)
 	logger.Infof("dtmsvr http listen at: %d", conf.HTTPPort)
+	httpServer = &http.Server{Addr: fmt.Sprintf(":%d", conf.HTTPPort), Handler: app}
 	go func() {
-		err := app.Run(fmt.Sprintf(":%d", conf.HTTPPort))
-		if err != nil {
+		err := httpServer.ListenAndServe()
+		if err != nil && err != http.ErrServerClosed {
 			logger.Errorf("start server err: %v", err)
 		}
 	}()
@@ -58,15 +60,16 @@ func StartSvr() *gin.Engine {
 	// start grpc server
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", conf.GrpcPort))
 	logger.FatalIfError(err)
-	s := grpc.NewServer(grpc.ChainUnaryInterceptor(grpcRecover, grpcMetrics, dtmgimp.GrpcServerLog))
-	dtmgpb.RegisterDtmServer(s, &dtmServer{})
+	grpcServer = grpc.NewServer(grpc.ChainUnaryInterceptor(grpcRecover, grpcMetrics, dtmgimp.GrpcServerLog))
+	dtmgpb.RegisterDtmServer(grpcServer, &dtmServer{})
 	logger.Infof("grpc listening at %v", lis.Addr())
 	go func() {
-		err := s.Serve(lis)
+		err := grpcServer.Serve(lis)
 		logger.FatalIfError(err)
 	}()
 
 	for i := 0; i < int(conf.UpdateBranchAsyncGoroutineNum); i++ {
+		updaters.Add(1)
 		go updateBranchAsync()
 	}
 
@@ -81,7 +84,7 @@ func StartSvr() *gin.Engine {
 
 // PopulateDB setup mysql data
 func PopulateDB(skipDrop bool) {
-	GetStore().PopulateData(skipDrop)
+	logger.FatalIfError(GetStore().PopulateData(context.Background(), skipDrop))
 }
 
 // UpdateBranchAsyncInterval interval to flush branch
@@ -92,28 +95,38 @@ func updateBranchAsync() {
 	flushBranchs := func() {
 		defer dtmutil.RecoverPanic(nil)
 		updates := []TransBranch{}
-		exists := map[string]bool{}
+		events := []TransEvent{}
+		exists := map[string]int{}
 		started := time.Now()
 		checkInterval := 20 * time.Millisecond
 		for time.Since(started) < UpdateBranchAsyncInterval-checkInterval && len(updates) < 20 {
 			select {
 			case updateBranch := <-updateBranchAsyncChan:
+				events = append(events, updateBranch.event)
+				b := TransBranch{
+					Gid:            updateBranch.gid,
+					BranchID:       updateBranch.branchID,
+					Op:             updateBranch.op,
+					Status:         updateBranch.status,
+					FinishTime:     updateBranch.finishTime,
+					Attempts:       updateBranch.attempts,
+					LastError:      updateBranch.lastError,
+					LastStatusCode: updateBranch.lastStatusCode,
+					LastResponse:   updateBranch.lastResponse,
+				}
 				k := updateBranch.gid + updateBranch.branchID + "-" + updateBranch.op
-				if !exists[k] { // postgres does not allow
-					exists[k] = true
-					updates = append(updates, TransBranch{
-						Gid:        updateBranch.gid,
-						BranchID:   updateBranch.branchID,
-						Op:         updateBranch.op,
-						Status:     updateBranch.status,
-						FinishTime: updateBranch.finishTime,
-					})
+				if i, ok := exists[k]; ok { // postgres does not allow updating a row twice in one statement, keep the latest
+					updates[i] = b
+				} else {
+					exists[k] = len(updates)
+					updates = append(updates, b)
 				}
 			case <-time.After(checkInterval):
 			}
 		}
 		for i := 0; i < 3 && len(updates) > 0; i++ {
-			rowAffected, err := GetStore().UpdateBranches(updates, []string{"status", "finish_time", "update_time"})
+			rowAffected, err := GetStore().UpdateBranches(context.Background(), updates, []string{"status", "finish_time", "update_time",
+				"attempts", "last_error", "last_status_code", "last_response"})
 
 			if err != nil {
 				logger.Errorf("async update branch status error: %v", err)
@@ -123,10 +136,21 @@ func updateBranchAsync() {
 				updates = []TransBranch{}
 			}
 		}
+		if len(updates) == 0 { // the events are saved only if the branch status are flushed
+			saveEvents(context.Background(), events)
+		}
 
 	}
-	for { // flush branches every 200ms
+	defer updaters.Done()
+	for { // flush branches every 200ms, until the queue is flushed after draining
 		flushBranchs()
+		select {
+		case <-draining:
+			if len(updateBranchAsyncChan) == 0 {
+				return
+			}
+		default:
+		}
 	}
 }
 
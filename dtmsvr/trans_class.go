@@ -19,6 +19,16 @@ type TransGlobal struct {
 	Context          context.Context
 	lastTouched      time.Time // record the start time of process
 	updateBranchSync bool
+	eventSource      string // what triggers the processing, default to eventSourceAPI
+	compensateAll    bool   // the submitted saga is aborted, all the actions are compensated, since they may be running on other servers
+}
+
+// getContext returns the context to access the store, the processing is not canceled if no context is set
+func (t *TransGlobal) getContext() context.Context {
+	if t.Context == nil {
+		return context.Background()
+	}
+	return t.Context
 }
 
 func (t *TransGlobal) setupPayloads() {
@@ -76,6 +86,7 @@ func TransFromContext(c *gin.Context) *TransGlobal {
 	logger.Debugf("creating trans in prepare")
 	m.setupPayloads()
 	m.Ext.Headers = map[string]string{}
+	m.Context = c.Request.Context()
 	return &m
 }
 
@@ -94,15 +105,24 @@ func TransFromDtmRequest(ctx context.Context, c *dtmgpb.DtmRequest) *TransGlobal
 		CustomData:     c.CustomedData,
 		RollbackReason: c.RollbackReason,
 		TransOptions: dtmcli.TransOptions{
-			WaitResult:     o.WaitResult,
-			TimeoutToFail:  o.TimeoutToFail,
-			RetryInterval:  o.RetryInterval,
-			BranchHeaders:  o.BranchHeaders,
-			RequestTimeout: o.RequestTimeout,
-			RetryLimit:     o.RetryLimit,
+			WaitResult:       o.WaitResult,
+			TimeoutToFail:    o.TimeoutToFail,
+			RetryInterval:    o.RetryInterval,
+			BranchHeaders:    o.BranchHeaders,
+			RequestTimeout:   o.RequestTimeout,
+			RetryLimit:       o.RetryLimit,
+			NotifyURL:        o.NotifyURL,
+			RetryPolicy:      o.RetryPolicy,
+			MaxRetryInterval: o.MaxRetryInterval,
+			Delay:            o.Delay,
 		},
 	}}
+	if o.ScheduleAt != nil {
+		at := o.ScheduleAt.AsTime()
+		r.ScheduleAt = &at
+	}
 	r.ReqExtra = c.ReqExtra
+	r.Context = ctx
 	if c.Steps != "" {
 		dtmimp.MustUnmarshalString(c.Steps, &r.Steps)
 	}
@@ -0,0 +1,74 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package dtmsvr
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/logger"
+)
+
+// TransEvent is a status transition of a global transaction or a branch
+type TransEvent = storage.TransEventStore
+
+// the sources that trigger status transitions
+const (
+	eventSourceAPI        = "api"
+	eventSourceCron       = "cron"
+	eventSourceTimeout    = "timeout"
+	eventSourceRetryLimit = "retry_limit"
+	eventSourceForceStop  = "force_stop"
+	// the sources below are the manual operations on a branch
+	eventSourceRetryBranch   = "retry_branch"
+	eventSourceResolveBranch = "resolve_branch"
+	eventSourceUpdateBranch  = "update_branch"
+)
+
+// maxEventErrorLen limits the length of error message stored in event
+const maxEventErrorLen = 1024
+
+// serverOwner identifies this dtm server in the events
+var serverOwner = func() string {
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s-%d", host, os.Getpid())
+}()
+
+func (t *TransGlobal) getEventSource() string {
+	return dtmimp.OrString(t.eventSource, eventSourceAPI)
+}
+
+func newEvent(gid, branchID, op, oldStatus, newStatus, source, errMsg string) TransEvent {
+	now := time.Now()
+	errMsg = truncateString(errMsg, maxEventErrorLen)
+	return TransEvent{
+		Gid:        gid,
+		BranchID:   branchID,
+		Op:         op,
+		OldStatus:  oldStatus,
+		NewStatus:  newStatus,
+		Source:     source,
+		Owner:      serverOwner,
+		Error:      errMsg,
+		CreateTime: &now,
+	}
+}
+
+// saveEvents saves the events. the failure of saving events will not break the processing of transaction
+func saveEvents(ctx context.Context, events []TransEvent) {
+	if len(events) == 0 {
+		return
+	}
+	err := GetStore().SaveTransEvents(ctx, events)
+	if err != nil {
+		logger.Errorf("save trans events error: %v events: %v", err, events)
+	}
+}
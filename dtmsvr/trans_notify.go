@@ -0,0 +1,88 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package dtmsvr
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dtm-labs/dtm/client/dtmcli"
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/client/dtmgrpc"
+	"github.com/dtm-labs/dtm/client/dtmgrpc/dtmgimp"
+	"github.com/dtm-labs/dtm/client/dtmgrpc/dtmgpb"
+	"github.com/dtm-labs/dtmdriver"
+	"github.com/dtm-labs/logger"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// opNotify is the op of the event recording a successful notification
+const opNotify = "notify"
+
+// getNotifyOptions returns the TransOptions containing NotifyURL. Options may be not parsed when changing status
+func (t *TransGlobal) getNotifyOptions() dtmcli.TransOptions {
+	opts := t.TransOptions
+	if opts.NotifyURL == "" && t.Options != "" {
+		dtmimp.MustUnmarshalString(t.Options, &opts)
+	}
+	return opts
+}
+
+// notify calls the NotifyURL of the finished global transaction.
+// if the call fails, the trans is kept in the cron index, and the call will be retried by cron
+func (t *TransGlobal) notify() {
+	opts := t.getNotifyOptions()
+	err := t.callNotifyURL(opts.NotifyURL, opts.RequestTimeout)
+	if err != nil {
+		logger.Errorf("notify %s for gid: %s error: %v", opts.NotifyURL, t.Gid, err)
+		err = t.touchCronTime(cronBackoff, 0)
+		if err != nil {
+			logger.Errorf("touch cron time for notify of gid: %s error: %v", t.Gid, err)
+		}
+		return
+	}
+	now := time.Now()
+	t.NotifyStatus = dtmcli.StatusSucceed
+	t.UpdateTime = &now
+	err = GetStore().ChangeGlobalStatus(t.getContext(), &t.TransGlobalStore, t.Status, []string{"notify_status", "update_time"}, true)
+	logger.Infof("notify %s for gid: %s succeed, save result: %v", opts.NotifyURL, t.Gid, err)
+	if err == nil {
+		saveEvents(t.getContext(), []TransEvent{newEvent(t.Gid, "", opNotify, dtmcli.StatusPrepared, dtmcli.StatusSucceed, t.getEventSource(), "")})
+	}
+}
+
+func (t *TransGlobal) callNotifyURL(uri string, requestTimeout int64) error {
+	if strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://") {
+		resp, err := dtmimp.GetRestyClient2(time.Duration(requestTimeout) * time.Second).R().
+			SetBody(map[string]string{
+				"gid":             t.Gid,
+				"trans_type":      t.TransType,
+				"status":          t.Status,
+				"rollback_reason": t.RollbackReason,
+				"result":          t.Result,
+			}).
+			Post(uri)
+		if err != nil {
+			return err
+		}
+		return dtmcli.HTTPResp2DtmError(resp)
+	}
+	server, method, err := dtmdriver.GetDriver().ParseServerMethod(uri)
+	if err != nil {
+		return err
+	}
+	ctx := dtmgimp.RequestTimeoutNewContext(context.Background(), requestTimeout)
+	err = dtmgimp.MustGetGrpcConn(server, false).Invoke(ctx, method, &dtmgpb.DtmTransNotify{
+		Gid:            t.Gid,
+		TransType:      t.TransType,
+		Status:         t.Status,
+		RollbackReason: t.RollbackReason,
+		Result:         t.Result,
+	}, &emptypb.Empty{})
+	return dtmgrpc.GrpcError2DtmError(err)
+}
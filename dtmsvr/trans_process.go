@@ -7,12 +7,15 @@
 package dtmsvr
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/dtm-labs/dtm/client/dtmcli"
 	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr/config"
 	"github.com/dtm-labs/dtm/dtmutil"
 	"github.com/dtm-labs/logger"
 )
@@ -24,16 +27,24 @@ func (t *TransGlobal) Process(branches []TransBranch) error {
 	return r
 }
 
-func (t *TransGlobal) process(branches []TransBranch) error {
-	if t.Options != "" {
-		dtmimp.MustUnmarshalString(t.Options, &t.TransOptions)
-	}
-	if t.ExtData != "" {
-		dtmimp.MustUnmarshalString(t.ExtData, &t.Ext)
+// processStored processes the global transaction with the branches in store
+func (t *TransGlobal) processStored() error {
+	branches, err := GetStore().FindBranches(t.getContext(), t.Gid)
+	if err != nil {
+		return err
 	}
+	return t.Process(branches)
+}
+
+func (t *TransGlobal) process(branches []TransBranch) error {
+	t.parseOptions()
 
-	if !t.WaitResult {
+	if !t.WaitResult || t.isScheduled() { // the scheduled trans is processed by cron later, so the result can not be waited
+		t.Context = context.Background() // the processing should not be canceled with the request
+		// added before the goroutine starts, so that it is always waited by Shutdown
+		processing.Add(1)
 		go func() {
+			defer processing.Done()
 			err := t.processInner(branches)
 			if err != nil && !errors.Is(err, dtmimp.ErrOngoing) {
 				logger.Errorf("processInner err: %v", err)
@@ -42,7 +53,9 @@ func (t *TransGlobal) process(branches []TransBranch) error {
 		return nil
 	}
 	submitting := t.Status == dtmcli.StatusSubmitted
+	processing.Add(1)
 	err := t.processInner(branches)
+	processing.Done()
 	if err != nil {
 		return err
 	}
@@ -56,7 +69,19 @@ func (t *TransGlobal) process(branches []TransBranch) error {
 	return nil
 }
 
+// parseOptions parses the stored Options and ExtData, which are needed to call the branches
+func (t *TransGlobal) parseOptions() {
+	if t.Options != "" {
+		dtmimp.MustUnmarshalString(t.Options, &t.TransOptions)
+	}
+	if t.ExtData != "" {
+		dtmimp.MustUnmarshalString(t.ExtData, &t.Ext)
+	}
+}
+
 func (t *TransGlobal) processInner(branches []TransBranch) (rerr error) {
+	atomic.AddInt64(&processingCount, 1)
+	defer atomic.AddInt64(&processingCount, -1)
 	defer handlePanic(&rerr)
 	defer func() {
 		if rerr != nil && !errors.Is(rerr, dtmcli.ErrOngoing) {
@@ -70,13 +95,37 @@ func (t *TransGlobal) processInner(branches []TransBranch) (rerr error) {
 	}()
 	logger.Debugf("processing: %s status: %s", t.Gid, t.Status)
 	t.lastTouched = time.Now()
+	if t.IsFinished() { // locked by cron only to retry the notification
+		if t.IsNotifyPending() {
+			t.notify()
+		}
+		return
+	}
+	if t.isScheduled() {
+		return t.touchCronTime(cronKeep, t.scheduleDelay())
+	}
 	rerr = t.getProcessor().ProcessOnce(branches)
 	return
 }
 
 func (t *TransGlobal) saveNew() ([]TransBranch, error) {
+	if err := config.CheckRetryPolicy(t.RetryPolicy); err != nil {
+		return nil, fmt.Errorf("%s. %w", err.Error(), dtmcli.ErrFailure)
+	}
+	if (t.ScheduleAt != nil || t.Delay > 0) && (t.TransType == "tcc" || t.TransType == "xa") {
+		return nil, fmt.Errorf("trans type: '%s' can not be scheduled. %w", t.TransType, dtmcli.ErrFailure)
+	}
+	now := time.Now()
+	if t.ScheduleAt == nil && t.Delay > 0 {
+		at := now.Add(time.Duration(t.Delay) * time.Second)
+		t.ScheduleAt = &at
+	}
 	t.NextCronInterval = t.getNextCronInterval(cronReset)
 	t.NextCronTime = dtmutil.GetNextTime(t.NextCronInterval)
+	// the submitted trans is saved in the cron index at its scheduled time, so it is not touched by cron before that
+	if t.Status == dtmcli.StatusSubmitted && t.ScheduleAt != nil && t.ScheduleAt.After(now) {
+		t.NextCronTime = t.ScheduleAt
+	}
 	t.ExtData = dtmimp.MustMarshalString(t.Ext)
 	if t.ExtData == "{}" {
 		t.ExtData = ""
@@ -85,7 +134,6 @@ func (t *TransGlobal) saveNew() ([]TransBranch, error) {
 	if t.Options == "{}" {
 		t.Options = ""
 	}
-	now := time.Now()
 	t.CreateTime = &now
 	t.UpdateTime = &now
 	branches := t.getProcessor().GenBranches()
@@ -93,8 +141,11 @@ func (t *TransGlobal) saveNew() ([]TransBranch, error) {
 		branches[i].CreateTime = &now
 		branches[i].UpdateTime = &now
 	}
-	err := GetStore().MaySaveNewTrans(&t.TransGlobalStore, branches)
+	err := GetStore().MaySaveNewTrans(t.getContext(), &t.TransGlobalStore, branches)
 	logger.Infof("MaySaveNewTrans result: %v, global: %v branches: %v",
 		err, t.TransGlobalStore.String(), dtmimp.MustMarshalString(branches))
+	if err == nil {
+		saveEvents(t.getContext(), []TransEvent{newEvent(t.Gid, "", "", "", t.Status, t.getEventSource(), "")})
+	}
 	return branches, err
 }
@@ -3,6 +3,7 @@ package dtmsvr
 import (
 	"errors"
 	"fmt"
+	"math/rand"
 	"net/url"
 	"strings"
 	"time"
@@ -16,29 +17,37 @@ import (
 	"github.com/dtm-labs/logger"
 	"github.com/lithammer/shortuuid/v3"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 // touchCronTime Based on ctype or delay set nextCronTime
 // delay = 0 ,use ctype set nextCronTime and nextCronInterval
 // delay > 0 ,use delay set nextCronTime ，use ctype set nextCronInterval
-func (t *TransGlobal) touchCronTime(ctype cronType, delay uint64) {
+func (t *TransGlobal) touchCronTime(ctype cronType, delay uint64) error {
 	t.lastTouched = time.Now()
 	nextCronInterval := t.getNextCronInterval(ctype)
 
 	var nextCronTime *time.Time
 	if delay > 0 {
 		nextCronTime = dtmutil.GetNextTime(int64(delay))
+	} else if ctype == cronBackoff && t.getRetryPolicy() == dtmimp.RetryPolicyExponentialJitter {
+		nextCronTime = dtmutil.GetNextTime(jitterInterval(nextCronInterval))
 	} else {
 		nextCronTime = dtmutil.GetNextTime(nextCronInterval)
 	}
 
-	GetStore().TouchCronTime(&t.TransGlobalStore, nextCronInterval, nextCronTime)
+	err := GetStore().TouchCronTime(t.getContext(), &t.TransGlobalStore, nextCronInterval, nextCronTime)
+	if err != nil {
+		return err
+	}
 	logger.Infof("TouchCronTime for: %s", t.TransGlobalStore.String())
+	return nil
 }
 
 type changeStatusParams struct {
 	rollbackReason string
 	result         string
+	source         string
 }
 
 type changeStatusOption func(c *changeStatusParams)
@@ -55,7 +64,13 @@ func withResult(result string) changeStatusOption {
 	}
 }
 
-func (t *TransGlobal) changeStatus(status string, opts ...changeStatusOption) {
+func withSource(source string) changeStatusOption {
+	return func(c *changeStatusParams) {
+		c.source = source
+	}
+}
+
+func (t *TransGlobal) changeStatus(status string, opts ...changeStatusOption) error {
 	statusParams := &changeStatusParams{}
 	for _, opt := range opts {
 		opt(statusParams)
@@ -78,23 +93,68 @@ func (t *TransGlobal) changeStatus(status string, opts ...changeStatusOption) {
 		updates = append(updates, "result")
 	}
 	t.UpdateTime = &now
-	GetStore().ChangeGlobalStatus(&t.TransGlobalStore, status, updates, status == dtmcli.StatusSucceed || status == dtmcli.StatusFailed)
+	finished := status == dtmcli.StatusSucceed || status == dtmcli.StatusFailed
+	if finished && t.getNotifyOptions().NotifyURL != "" {
+		t.NotifyStatus = dtmcli.StatusPrepared
+		updates = append(updates, "notify_status")
+	}
+	oldStatus := t.Status
+	// a finished trans with pending notification is kept in the cron index, so that the notification can be retried
+	err := GetStore().ChangeGlobalStatus(t.getContext(), &t.TransGlobalStore, status, updates, finished && !t.IsNotifyPending())
+	if err != nil {
+		return err
+	}
 	logger.Infof("ChangeGlobalStatus to %s ok for %s", status, t.TransGlobalStore.String())
 	t.Status = status
+	event := newEvent(t.Gid, "", "", oldStatus, status, dtmimp.OrString(statusParams.source, t.getEventSource()), statusParams.rollbackReason)
+	saveEvents(t.getContext(), []TransEvent{event})
+	publishEvent(t.TransType, event)
+	if finished && t.IsNotifyPending() {
+		t.notify()
+	}
+	return nil
 }
 
-func (t *TransGlobal) changeBranchStatus(b *TransBranch, status string, branchPos int) {
+func (t *TransGlobal) changeBranchStatus(b *TransBranch, status string, branchPos int) error {
 	now := time.Now()
+	errMsg := ""
+	if b.Error != nil {
+		errMsg = b.Error.Error()
+	}
+	event := newEvent(t.Gid, b.BranchID, b.Op, b.Status, status, t.getEventSource(), errMsg)
 	b.Status = status
 	b.FinishTime = &now
 	b.UpdateTime = &now
-	if conf.Store.Driver != dtmimp.DBTypeMysql && conf.Store.Driver != dtmimp.DBTypePostgres || conf.UpdateBranchSync > 0 || t.updateBranchSync {
-		GetStore().LockGlobalSaveBranches(t.Gid, t.Status, []TransBranch{*b}, branchPos)
+	if t.needUpdateBranchSync() {
+		err := GetStore().LockGlobalSaveBranches(t.getContext(), t.Gid, t.Status, []TransBranch{*b}, branchPos)
+		if err != nil {
+			return err
+		}
 		logger.Infof("LockGlobalSaveBranches ok: gid: %s old status: %s branches: %s",
 			b.Gid, dtmcli.StatusPrepared, b.String())
+		saveEvents(t.getContext(), []TransEvent{event})
 	} else { // for better performance, batch the updates of branch status
-		updateBranchAsyncChan <- branchStatus{gid: t.Gid, branchID: b.BranchID, op: b.Op, status: status, finishTime: &now}
+		updateBranchAsyncChan <- newBranchStatus(b, event)
 	}
+	publishEvent(t.TransType, event)
+	return nil
+}
+
+// saveBranchAttempt saves the attempt info of a branch whose status is not changed.
+// it is saved synchronously, so that a later status change of the branch is never overwritten by the prepared status
+func (t *TransGlobal) saveBranchAttempt(b *TransBranch, branchPos int) {
+	now := time.Now()
+	b.UpdateTime = &now
+	err := GetStore().LockGlobalSaveBranches(t.getContext(), t.Gid, t.Status, []TransBranch{*b}, branchPos)
+	if err != nil {
+		logger.Errorf("save branch attempt error: %v branch: %s", err, b.String())
+	}
+}
+
+// needUpdateBranchSync returns true if the branch status should be saved with the status of the global trans checked.
+// only the sql stores update the branches asynchronously, UpdateBranches of the other stores does not check the global trans yet
+func (t *TransGlobal) needUpdateBranchSync() bool {
+	return conf.Store.Driver != dtmimp.DBTypeMysql && conf.Store.Driver != dtmimp.DBTypePostgres || conf.UpdateBranchSync > 0 || t.updateBranchSync
 }
 
 func (t *TransGlobal) isTimeout() bool {
@@ -105,20 +165,53 @@ func (t *TransGlobal) isTimeout() bool {
 	if timeout == 0 {
 		return false
 	}
-	return time.Since(*t.CreateTime)+NowForwardDuration >= time.Duration(timeout)*time.Second
+	// the timeout of a scheduled trans is counted from the time when it is scheduled
+	start := *t.CreateTime
+	if t.ScheduleAt != nil && t.ScheduleAt.After(start) {
+		start = *t.ScheduleAt
+	}
+	return time.Since(start)+NowForwardDuration >= time.Duration(timeout)*time.Second
 }
 
 func (t *TransGlobal) needDelay(delay uint64) bool {
 	return time.Since(*t.CreateTime)+CronForwardDuration < time.Duration(delay)*time.Second
 }
 
+// scheduleDelay returns the seconds until the ScheduleAt of the trans, 0 if it is due
+func (t *TransGlobal) scheduleDelay() uint64 {
+	if t.ScheduleAt == nil {
+		return 0
+	}
+	d := time.Until(*t.ScheduleAt) - CronForwardDuration
+	if d <= 0 {
+		return 0
+	}
+	return uint64((d + time.Second - 1) / time.Second)
+}
+
+// isScheduled returns true if the trans is submitted, but should not be processed until ScheduleAt
+func (t *TransGlobal) isScheduled() bool {
+	return t.Status == dtmcli.StatusSubmitted && t.scheduleDelay() > 0
+}
+
 func (t *TransGlobal) needProcess() bool {
 	return t.Status == dtmcli.StatusSubmitted || t.Status == dtmcli.StatusAborting || t.Status == dtmcli.StatusPrepared && t.isTimeout()
 }
 
+// branchResponse is the raw response of calling a branch url
+type branchResponse struct {
+	statusCode int // http status code or grpc code
+	body       string
+}
+
 func (t *TransGlobal) getURLResult(uri string, branchID, op string, branchPayload []byte) error {
+	_, err := t.getURLResponse(uri, branchID, op, branchPayload)
+	return err
+}
+
+func (t *TransGlobal) getURLResponse(uri string, branchID, op string, branchPayload []byte) (branchResponse, error) {
 	if uri == "" { // empty url is success
-		return nil
+		return branchResponse{}, nil
 	}
 	if t.Protocol == dtmimp.ProtocolHTTP || strings.HasPrefix(uri, "http://") || strings.HasPrefix(uri, "https://") {
 		if t.Protocol == "json-rpc" && strings.Contains(uri, "method") {
@@ -129,7 +222,7 @@ func (t *TransGlobal) getURLResult(uri string, branchID, op string, branchPayloa
 	return t.getGrpcResult(uri, branchID, op, branchPayload)
 }
 
-func (t *TransGlobal) getHTTPResult(uri string, branchID, op string, branchPayload []byte) error {
+func (t *TransGlobal) getHTTPResult(uri string, branchID, op string, branchPayload []byte) (branchResponse, error) {
 	rc := dtmimp.GetRestyClient2(time.Duration(t.RequestTimeout) * time.Second)
 	resp, err := rc.R().SetBody(string(branchPayload)).
 		SetQueryParams(map[string]string{
@@ -143,12 +236,12 @@ func (t *TransGlobal) getHTTPResult(uri string, branchID, op string, branchPaylo
 		SetHeaders(t.TransOptions.BranchHeaders).
 		Execute(dtmimp.If(branchPayload != nil || t.TransType == "xa", "POST", "GET").(string), uri)
 	if err != nil {
-		return err
+		return branchResponse{}, err
 	}
-	return dtmcli.HTTPResp2DtmError(resp)
+	return branchResponse{statusCode: resp.StatusCode(), body: resp.String()}, dtmcli.HTTPResp2DtmError(resp)
 }
 
-func (t *TransGlobal) getJSONRPCResult(uri string, branchID, op string, branchPayload []byte) error {
+func (t *TransGlobal) getJSONRPCResult(uri string, branchID, op string, branchPayload []byte) (branchResponse, error) {
 	var params map[string]interface{}
 	dtmimp.MustUnmarshal(branchPayload, &params)
 	u, err := url.Parse(uri)
@@ -168,20 +261,22 @@ func (t *TransGlobal) getJSONRPCResult(uri string, branchID, op string, branchPa
 		SetHeaders(t.Ext.Headers).
 		SetHeaders(t.TransOptions.BranchHeaders).
 		Post(uri)
-	if err == nil {
-		err = dtmcli.HTTPResp2DtmError(resp)
+	if err != nil {
+		return branchResponse{}, err
 	}
+	r := branchResponse{statusCode: resp.StatusCode(), body: resp.String()}
+	err = dtmcli.HTTPResp2DtmError(resp)
 	if err == nil {
 		err = dtmimp.RespAsErrorByJSONRPC(resp)
 	}
-	return err
+	return r, err
 }
 
-func (t *TransGlobal) getGrpcResult(uri string, branchID, op string, branchPayload []byte) error {
+func (t *TransGlobal) getGrpcResult(uri string, branchID, op string, branchPayload []byte) (branchResponse, error) {
 	// grpc handler
 	server, method, err := dtmdriver.GetDriver().ParseServerMethod(uri)
 	if err != nil {
-		return err
+		return branchResponse{}, err
 	}
 
 	conn := dtmgimp.MustGetGrpcConn(server, true)
@@ -191,14 +286,23 @@ func (t *TransGlobal) getGrpcResult(uri string, branchID, op string, branchPaylo
 	ctx = metadata.AppendToOutgoingContext(ctx, kvs...)
 	ctx = dtmgimp.RequestTimeoutNewContext(ctx, t.RequestTimeout)
 	err = conn.Invoke(ctx, method, branchPayload, &[]byte{})
+	r := branchResponse{statusCode: int(status.Code(err))}
 	if err == nil {
-		return nil
+		return r, nil
 	}
-	return dtmgrpc.GrpcError2DtmError(err)
+	return r, dtmgrpc.GrpcError2DtmError(err)
 }
 
+// getBranchResult calls the branch url, and records the attempt in the branch
 func (t *TransGlobal) getBranchResult(branch *TransBranch) (string, error) {
-	err := t.getURLResult(branch.URL, branch.BranchID, branch.Op, branch.BinData)
+	resp, err := t.getURLResponse(branch.URL, branch.BranchID, branch.Op, branch.BinData)
+	branch.Attempts++
+	branch.LastStatusCode = resp.statusCode
+	branch.LastResponse = truncateString(resp.body, maxBranchResponseLen)
+	branch.LastError = ""
+	if err != nil {
+		branch.LastError = truncateString(err.Error(), maxBranchResponseLen)
+	}
 	if err == nil {
 		return dtmcli.StatusSucceed, nil
 	} else if t.TransType == "saga" && branch.Op == dtmimp.OpAction && errors.Is(err, dtmcli.ErrFailure) {
@@ -213,24 +317,32 @@ func (t *TransGlobal) getBranchResult(branch *TransBranch) (string, error) {
 func (t *TransGlobal) execBranch(branch *TransBranch, branchPos int) error {
 	status, err := t.getBranchResult(branch)
 	if status != "" {
-		t.changeBranchStatus(branch, status, branchPos)
+		if serr := t.changeBranchStatus(branch, status, branchPos); serr != nil {
+			return serr
+		}
+	} else {
+		t.saveBranchAttempt(branch, branchPos)
 	}
 	branchMetrics(t, branch, status == dtmcli.StatusSucceed)
 	// if time pass 1500ms and NextCronInterval is not default, then reset NextCronInterval
+	var terr error
 	if err == nil && time.Since(t.lastTouched)+NowForwardDuration >= 1500*time.Millisecond ||
 		t.NextCronInterval > conf.RetryInterval && t.NextCronInterval > t.RetryInterval {
-		t.touchCronTime(cronReset, 0)
+		terr = t.touchCronTime(cronReset, 0)
 	} else if err == dtmimp.ErrOngoing {
-		t.touchCronTime(cronKeep, 0)
+		terr = t.touchCronTime(cronKeep, 0)
 	} else if err != nil {
-		t.touchCronTime(cronBackoff, 0)
+		terr = t.touchCronTime(cronBackoff, 0)
+	}
+	if terr != nil {
+		return terr
 	}
 	return err
 }
 
 func (t *TransGlobal) getNextCronInterval(ctype cronType) int64 {
 	if ctype == cronBackoff {
-		return t.NextCronInterval * 2
+		return t.getBackoffInterval()
 	} else if ctype == cronKeep {
 		return t.NextCronInterval
 	} else if t.RetryInterval != 0 {
@@ -241,3 +353,33 @@ func (t *TransGlobal) getNextCronInterval(ctype cronType) int64 {
 		return conf.RetryInterval
 	}
 }
+
+func (t *TransGlobal) getRetryPolicy() string {
+	return dtmimp.OrString(t.RetryPolicy, conf.RetryPolicy, dtmimp.RetryPolicyExponential)
+}
+
+// getBackoffInterval returns the retry interval after a failure according to the retry policy.
+// the interval of the exponential policies is doubled, up to the max retry interval, but not less than the retry interval
+func (t *TransGlobal) getBackoffInterval() int64 {
+	interval := t.getNextCronInterval(cronReset)
+	if t.getRetryPolicy() == dtmimp.RetryPolicyFixed {
+		return interval
+	}
+	next := t.NextCronInterval * 2
+	maxInterval := t.MaxRetryInterval
+	if maxInterval == 0 {
+		maxInterval = conf.MaxRetryInterval
+	}
+	if maxInterval > 0 && next > maxInterval {
+		next = maxInterval
+	}
+	if next < interval {
+		next = interval
+	}
+	return next
+}
+
+// jitterInterval returns a random interval in [interval/2, interval], so that the retries of the trans failed together are spread
+func jitterInterval(interval int64) int64 {
+	return interval - rand.Int63n(interval/2+1)
+}
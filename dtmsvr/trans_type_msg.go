@@ -33,29 +33,31 @@ func (t *transMsgProcessor) GenBranches() []TransBranch {
 	return branches
 }
 
+// cMsgCustom is the delay set by the old clients, the new clients set TransOptions.Delay instead
 type cMsgCustom struct {
 	Delay uint64 //delay call branch, unit second
 }
 
-func (t *TransGlobal) mayQueryPrepared() {
+func (t *TransGlobal) mayQueryPrepared() error {
 	if !t.needProcess() || t.Status == dtmcli.StatusSubmitted {
-		return
+		return nil
 	}
 	err := t.getURLResult(t.QueryPrepared, "00", "msg", nil)
 	if err == nil {
-		t.changeStatus(dtmcli.StatusSubmitted)
+		return t.changeStatus(dtmcli.StatusSubmitted)
 	} else if errors.Is(err, dtmcli.ErrFailure) {
-		t.changeStatus(dtmcli.StatusFailed)
+		return t.changeStatus(dtmcli.StatusFailed)
 	} else if errors.Is(err, dtmcli.ErrOngoing) {
-		t.touchCronTime(cronReset, 0)
-	} else {
-		logger.Errorf("getting result failed for %s. error: %v", t.QueryPrepared, err)
-		t.touchCronTime(cronBackoff, 0)
+		return t.touchCronTime(cronReset, 0)
 	}
+	logger.Errorf("getting result failed for %s. error: %v", t.QueryPrepared, err)
+	return t.touchCronTime(cronBackoff, 0)
 }
 
 func (t *transMsgProcessor) ProcessOnce(branches []TransBranch) error {
-	t.mayQueryPrepared()
+	if err := t.mayQueryPrepared(); err != nil {
+		return err
+	}
 	if !t.needProcess() || t.Status == dtmcli.StatusPrepared {
 		return nil
 	}
@@ -65,8 +67,7 @@ func (t *transMsgProcessor) ProcessOnce(branches []TransBranch) error {
 	}
 
 	if cmc.Delay > 0 && t.needDelay(cmc.Delay) {
-		t.touchCronTime(cronKeep, cmc.Delay)
-		return nil
+		return t.touchCronTime(cronKeep, cmc.Delay)
 	}
 	var started int
 	resultsChan := make(chan error, len(branches))
@@ -96,6 +97,5 @@ func (t *transMsgProcessor) ProcessOnce(branches []TransBranch) error {
 	} else if err != nil {
 		return err
 	}
-	t.changeStatus(dtmcli.StatusSucceed)
-	return nil
+	return t.changeStatus(dtmcli.StatusSucceed)
 }
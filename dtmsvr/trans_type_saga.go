@@ -7,6 +7,7 @@ import (
 
 	"github.com/dtm-labs/dtm/client/dtmcli"
 	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
 	"github.com/dtm-labs/logger"
 )
 
@@ -50,13 +51,17 @@ type branchResult struct {
 	started bool
 	op      string
 	err     error
+	changed bool // the status of the global trans is changed by others, so the branch result is not saved
 }
 
 func (t *transSagaProcessor) ProcessOnce(branches []TransBranch) error {
 	// when saga tasks is fetched, it always need to process
 	logger.Debugf("status: %s timeout: %t", t.Status, t.isTimeout())
 	if t.Status == dtmcli.StatusSubmitted && t.isTimeout() {
-		t.changeStatus(dtmcli.StatusAborting, withRollbackReason(fmt.Sprintf("Timeout after %d seconds", t.TimeoutToFail)))
+		err := t.changeStatus(dtmcli.StatusAborting, withRollbackReason(fmt.Sprintf("Timeout after %d seconds", t.TimeoutToFail)), withSource(eventSourceTimeout))
+		if err != nil {
+			return err
+		}
 	}
 	n := len(branches)
 
@@ -127,7 +132,7 @@ func (t *transSagaProcessor) ProcessOnce(branches []TransBranch) error {
 			if x := recover(); x != nil {
 				err = dtmimp.AsError(x)
 			}
-			resultChan <- branchResult{index: i, status: branches[i].Status, op: branches[i].Op, err: branches[i].Error}
+			resultChan <- branchResult{index: i, status: branches[i].Status, op: branches[i].Op, err: branches[i].Error, changed: errors.Is(err, storage.ErrNotFound)}
 			if err != nil && !errors.Is(err, dtmcli.ErrOngoing) {
 				logger.Errorf("exec branch %s %s %s error: %v", branches[i].BranchID, branches[i].Op, branches[i].URL, err)
 			}
@@ -165,9 +170,12 @@ func (t *transSagaProcessor) ProcessOnce(branches []TransBranch) error {
 			go asyncExecBranch(b)
 		}
 	}
-	waitDoneOnce := func() {
+	waitDoneOnce := func() error {
 		select {
 		case r := <-resultChan:
+			if r.changed { // aborted by others, no more branch should be started
+				return fmt.Errorf("the status of global trans %s is changed by others: %w", t.Gid, storage.ErrNotFound)
+			}
 			br := &branchResults[r.index]
 			br.status = r.status
 			if r.op == dtmimp.OpAction {
@@ -182,8 +190,7 @@ func (t *transSagaProcessor) ProcessOnce(branches []TransBranch) error {
 						break
 					}
 					// if t.RetryCount = t.RetryLimit, trans will be aborted
-					t.changeStatus(dtmcli.StatusAborting, withRollbackReason(fmt.Sprintf("RetryCount is greater than RetryLimit, RetryLimit: %v", t.RetryLimit)))
-					break
+					return t.changeStatus(dtmcli.StatusAborting, withRollbackReason(fmt.Sprintf("RetryCount is greater than RetryLimit, RetryLimit: %v", t.RetryLimit)), withSource(eventSourceRetryLimit))
 				}
 				rsADone++
 				if r.status == dtmcli.StatusFailed {
@@ -202,6 +209,7 @@ func (t *transSagaProcessor) ProcessOnce(branches []TransBranch) error {
 		case <-time.After(time.Second * 3):
 			logger.Debugf("wait once for done")
 		}
+		return nil
 	}
 	prepareToCompensate := func() {
 		toRun := pickToRunActions()
@@ -209,9 +217,10 @@ func (t *transSagaProcessor) ProcessOnce(branches []TransBranch) error {
 			branchResults[b].started = true
 		}
 		for i := 1; i < len(branchResults); i += 2 {
-			// these branches may have run. so flag them to status succeed, then run the corresponding
-			// compensate
-			if branchResults[i].started && branchResults[i].status == dtmcli.StatusPrepared {
+			// these branches may have run, or their compensate has been tried. so flag them to status succeed,
+			// then run the corresponding compensate
+			if branchResults[i].status == dtmcli.StatusPrepared &&
+				(branchResults[i].started || t.compensateAll || branches[i-1].Attempts > 0) {
 				branchResults[i].status = dtmcli.StatusSucceed
 			}
 		}
@@ -230,21 +239,27 @@ func (t *transSagaProcessor) ProcessOnce(branches []TransBranch) error {
 		if rsADone == rsAStarted { // no branch is running, so break
 			break
 		}
-		waitDoneOnce()
+		if err := waitDoneOnce(); err != nil {
+			return err
+		}
 	}
 	if t.Status == dtmcli.StatusSubmitted && rsAFailed == 0 && rsAToStart == rsASucceed {
-		t.changeStatus(dtmcli.StatusSucceed)
-		return nil
+		return t.changeStatus(dtmcli.StatusSucceed)
 	}
 	if t.Status == dtmcli.StatusSubmitted && rsAFailed > 0 {
 		msg := "fail message lost"
 		if failureError != nil { // handle the case if branch failed and saved, and then crash
 			msg = failureError.Error()
 		}
-		t.changeStatus(dtmcli.StatusAborting, withRollbackReason(msg))
+		if err := t.changeStatus(dtmcli.StatusAborting, withRollbackReason(msg)); err != nil {
+			return err
+		}
 	}
 	if t.Status == dtmcli.StatusSubmitted && t.isTimeout() {
-		t.changeStatus(dtmcli.StatusAborting, withRollbackReason(fmt.Sprintf("Timeout after %d seconds", t.TimeoutToFail)))
+		err := t.changeStatus(dtmcli.StatusAborting, withRollbackReason(fmt.Sprintf("Timeout after %d seconds", t.TimeoutToFail)), withSource(eventSourceTimeout))
+		if err != nil {
+			return err
+		}
 	}
 	if t.Status == dtmcli.StatusAborting {
 		prepareToCompensate()
@@ -256,10 +271,12 @@ func (t *transSagaProcessor) ProcessOnce(branches []TransBranch) error {
 			break
 		}
 		logger.Debugf("rsCDone: %d rsCToStart: %d", rsCDone, rsCToStart)
-		waitDoneOnce()
+		if err := waitDoneOnce(); err != nil {
+			return err
+		}
 	}
 	if t.Status == dtmcli.StatusAborting && rsCToStart == rsCSucceed {
-		t.changeStatus(dtmcli.StatusFailed)
+		return t.changeStatus(dtmcli.StatusFailed)
 	}
 	return nil
 }
@@ -25,7 +25,10 @@ func (t *transTccProcessor) ProcessOnce(branches []TransBranch) error {
 		return nil
 	}
 	if t.Status == dtmcli.StatusPrepared && t.isTimeout() {
-		t.changeStatus(dtmcli.StatusAborting, withRollbackReason(fmt.Sprintf("Timeout after %d seconds", t.TimeoutToFail)))
+		err := t.changeStatus(dtmcli.StatusAborting, withRollbackReason(fmt.Sprintf("Timeout after %d seconds", t.TimeoutToFail)), withSource(eventSourceTimeout))
+		if err != nil {
+			return err
+		}
 	}
 	op := dtmimp.If(t.Status == dtmcli.StatusSubmitted, dtmimp.OpConfirm, dtmimp.OpCancel).(string)
 	for current := len(branches) - 1; current >= 0; current-- {
@@ -37,6 +40,5 @@ func (t *transTccProcessor) ProcessOnce(branches []TransBranch) error {
 			}
 		}
 	}
-	t.changeStatus(dtmimp.If(t.Status == dtmcli.StatusSubmitted, dtmcli.StatusSucceed, dtmcli.StatusFailed).(string))
-	return nil
+	return t.changeStatus(dtmimp.If(t.Status == dtmcli.StatusSubmitted, dtmcli.StatusSucceed, dtmcli.StatusFailed).(string))
 }
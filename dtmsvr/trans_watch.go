@@ -0,0 +1,217 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package dtmsvr
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dtm-labs/dtm/client/dtmcli"
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/logger"
+)
+
+// eventSourcePoll is the source of the changes found by polling the store, which may be made by other dtm servers
+const eventSourcePoll = "poll"
+
+// watchChanBuffer is the buffer size of a watcher. events are dropped if the watcher is too slow, and will be found by polling
+const watchChanBuffer = 100
+
+// WatchCondition selects the transactions to watch
+type WatchCondition struct {
+	Gid       string `json:"gid,omitempty"` // if specified, the watch ends when the transaction is finished
+	GidPrefix string `json:"gid_prefix,omitempty"`
+	TransType string `json:"trans_type,omitempty"`
+}
+
+func (c *WatchCondition) match(gid string, transType string) bool {
+	return (c.Gid == "" || c.Gid == gid) &&
+		strings.HasPrefix(gid, c.GidPrefix) &&
+		(c.TransType == "" || c.TransType == transType)
+}
+
+type watcher struct {
+	condition WatchCondition
+	events    chan TransEvent
+}
+
+var watchers = struct {
+	sync.Mutex
+	all map[*watcher]struct{}
+}{all: map[*watcher]struct{}{}}
+
+func addWatcher(condition WatchCondition) *watcher {
+	w := &watcher{condition: condition, events: make(chan TransEvent, watchChanBuffer)}
+	watchers.Lock()
+	defer watchers.Unlock()
+	watchers.all[w] = struct{}{}
+	return w
+}
+
+func removeWatcher(w *watcher) {
+	watchers.Lock()
+	defer watchers.Unlock()
+	delete(watchers.all, w)
+}
+
+// publishEvent pushes the status change made by this dtm server to the matched watchers
+func publishEvent(transType string, event TransEvent) {
+	watchers.Lock()
+	defer watchers.Unlock()
+	for w := range watchers.all {
+		if !w.condition.match(event.Gid, transType) {
+			continue
+		}
+		select {
+		case w.events <- event:
+		default:
+			logger.Debugf("watcher is too slow, event dropped: %v", event)
+		}
+	}
+}
+
+// watchTrans sends the status changes matching the condition, until ctx is done or the watched gid is finished.
+// the changes made by this dtm server are pushed immediately, while the changes made by other dtm servers are found by polling
+func watchTrans(ctx context.Context, condition WatchCondition, send func(event *TransEvent) error) (rerr error) {
+	defer dtmimp.P2E(&rerr)
+	if condition.Gid != "" {
+		_, err := GetStore().FindTransGlobalStore(ctx, condition.Gid)
+		if err == storage.ErrNotFound {
+			return dtmcli.ErrorMessage2Error("no trans with gid: "+condition.Gid+" found", dtmcli.ErrFailure)
+		} else if err != nil {
+			return err
+		}
+	}
+	w := addWatcher(condition)
+	defer removeWatcher(w)
+
+	poller := &watchPoller{condition: condition, since: time.Now(), known: map[string]string{}, finished: map[string]time.Time{}}
+	// emit sends the event if the status is changed, returns true if the watched gid is finished
+	emit := func(event TransEvent) (bool, error) {
+		key := event.Gid + "|" + event.BranchID + "|" + event.Op
+		if event.Op == opNotify || poller.known[key] == event.NewStatus {
+			return false, nil
+		}
+		event.OldStatus = poller.known[key]
+		poller.known[key] = event.NewStatus
+		finished := event.BranchID == "" && (event.NewStatus == dtmcli.StatusSucceed || event.NewStatus == dtmcli.StatusFailed)
+		if finished {
+			poller.finished[event.Gid] = time.Now()
+			if event.CreateTime != nil {
+				poller.finished[event.Gid] = *event.CreateTime
+			}
+		}
+		return finished && condition.Gid != "", send(&event)
+	}
+	ticker := time.NewTicker(time.Duration(conf.WatchPollInterval) * time.Second)
+	defer ticker.Stop()
+	events, err := poller.poll(ctx)
+	if err != nil {
+		return err
+	}
+	for {
+		for _, event := range events {
+			finished, err := emit(event)
+			if err != nil || finished {
+				return err
+			}
+		}
+		events = nil
+		select {
+		case <-stopping:
+			return nil
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.Canceled) {
+				return nil
+			}
+			return ctx.Err()
+		case event := <-w.events:
+			events = append(events, event)
+		case <-ticker.C:
+			events, err = poller.poll(ctx)
+			if err != nil && ctx.Err() == nil {
+				return err
+			}
+		}
+	}
+}
+
+// watchPoller polls the store to find the current status of the watched transactions
+type watchPoller struct {
+	condition WatchCondition
+	since     time.Time            // only the transactions updated since this time are polled, if gid is not specified
+	known     map[string]string    // gid|branch_id|op => the last sent status
+	finished  map[string]time.Time // gid => the time when the finished status is sent
+}
+
+func (p *watchPoller) poll(ctx context.Context) ([]TransEvent, error) {
+	events := []TransEvent{}
+	if p.condition.Gid != "" {
+		global, err := GetStore().FindTransGlobalStore(ctx, p.condition.Gid)
+		if err == storage.ErrNotFound {
+			return events, nil
+		} else if err != nil {
+			return nil, err
+		}
+		branches, err := GetStore().FindBranches(ctx, p.condition.Gid)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range branches {
+			events = append(events, TransEvent{Gid: b.Gid, BranchID: b.BranchID, Op: b.Op, NewStatus: b.Status, Source: eventSourcePoll, CreateTime: b.UpdateTime})
+		}
+		// the global status is the last, so that the branches are sent before the watch ends
+		return append(events, p.globalEvent(global)), nil
+	}
+	p.forgetFinished()
+	condition := storage.TransGlobalScanCondition{
+		TransType:       p.condition.TransType,
+		GidPrefix:       p.condition.GidPrefix,
+		UpdateTimeStart: p.since,
+		SortBy:          storage.SortByUpdateTime,
+		SortAsc:         true,
+	}
+	for position := ""; ; {
+		globals, err := GetStore().ScanTransGlobalStores(ctx, &position, 100, condition)
+		if err != nil {
+			return nil, err
+		}
+		for i := range globals {
+			events = append(events, p.globalEvent(&globals[i]))
+			if globals[i].UpdateTime != nil && globals[i].UpdateTime.After(p.since) {
+				p.since = *globals[i].UpdateTime
+			}
+		}
+		if position == "" {
+			break
+		}
+	}
+	return events, nil
+}
+
+func (p *watchPoller) globalEvent(g *storage.TransGlobalStore) TransEvent {
+	return TransEvent{Gid: g.Gid, NewStatus: g.Status, Source: eventSourcePoll, CreateTime: g.UpdateTime}
+}
+
+// forgetFinished removes the known status of the finished transactions, which will not be polled any more
+func (p *watchPoller) forgetFinished() {
+	for gid, finishTime := range p.finished {
+		if !finishTime.Before(p.since) {
+			continue
+		}
+		delete(p.finished, gid)
+		for key := range p.known {
+			if strings.HasPrefix(key, gid+"|") {
+				delete(p.known, key)
+			}
+		}
+	}
+}
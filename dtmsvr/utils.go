@@ -7,8 +7,11 @@
 package dtmsvr
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
 	"github.com/dtm-labs/dtm/dtmsvr/config"
@@ -18,11 +21,46 @@ import (
 )
 
 type branchStatus struct {
-	gid        string
-	branchID   string
-	op         string
-	status     string
-	finishTime *time.Time
+	gid            string
+	branchID       string
+	op             string
+	status         string
+	finishTime     *time.Time
+	attempts       int64
+	lastError      string
+	lastStatusCode int
+	lastResponse   string
+	event          TransEvent
+}
+
+func newBranchStatus(b *TransBranch, event TransEvent) branchStatus {
+	return branchStatus{
+		gid:            b.Gid,
+		branchID:       b.BranchID,
+		op:             b.Op,
+		status:         b.Status,
+		finishTime:     b.FinishTime,
+		attempts:       b.Attempts,
+		lastError:      b.LastError,
+		lastStatusCode: b.LastStatusCode,
+		lastResponse:   b.LastResponse,
+		event:          event,
+	}
+}
+
+// maxBranchResponseLen limits the length of the last response and last error stored in branch
+const maxBranchResponseLen = 1024
+
+// truncateString truncates s to at most n bytes, without breaking a multi-byte character
+func truncateString(s string, n int) string {
+	s = strings.ToValidUTF8(s, "?")
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
 }
 
 var e2p = dtmimp.E2P
@@ -43,10 +81,12 @@ func GenGid() string {
 }
 
 // GetTransGlobal construct trans from db
-func GetTransGlobal(gid string) *TransGlobal {
-	trans := GetStore().FindTransGlobalStore(gid)
-	//nolint:staticcheck
-	dtmimp.PanicIf(trans == nil, fmt.Errorf("no TransGlobal with gid: %s found", gid))
-	//nolint:staticcheck
-	return &TransGlobal{TransGlobalStore: *trans}
+func GetTransGlobal(ctx context.Context, gid string) (*TransGlobal, error) {
+	trans, err := GetStore().FindTransGlobalStore(ctx, gid)
+	if err == storage.ErrNotFound {
+		return nil, fmt.Errorf("no TransGlobal with gid: %s found", gid)
+	} else if err != nil {
+		return nil, err
+	}
+	return &TransGlobal{TransGlobalStore: *trans, Context: ctx}, nil
 }
@@ -7,8 +7,13 @@
 package dtmsvr
 
 import (
+	"context"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/dtm-labs/dtm/client/dtmcli"
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -29,3 +34,97 @@ func TestSetNextCron(t *testing.T) {
 	tg.TimeoutToFail = 3
 	assert.Equal(t, int64(3), tg.getNextCronInterval(cronReset))
 }
+
+func TestRetryPolicy(t *testing.T) {
+	conf.RetryInterval = 10
+	conf.MaxRetryInterval = 60
+	defer func() { conf.MaxRetryInterval = 0 }()
+	tg := TransGlobal{}
+	tg.NextCronInterval = 40
+	assert.Equal(t, int64(60), tg.getNextCronInterval(cronBackoff)) // capped by the max retry interval
+	tg.MaxRetryInterval = 100
+	assert.Equal(t, int64(80), tg.getNextCronInterval(cronBackoff))
+	tg.MaxRetryInterval = 5
+	assert.Equal(t, int64(10), tg.getNextCronInterval(cronBackoff)) // not less than the retry interval
+
+	tg.RetryPolicy = dtmimp.RetryPolicyFixed
+	assert.Equal(t, int64(10), tg.getNextCronInterval(cronBackoff))
+	tg.RetryInterval = 15
+	assert.Equal(t, int64(15), tg.getNextCronInterval(cronBackoff))
+
+	for i := 0; i < 100; i++ {
+		interval := jitterInterval(80)
+		assert.True(t, interval >= 40 && interval <= 80)
+	}
+	assert.Equal(t, int64(1), jitterInterval(1))
+}
+
+func TestScheduleDelay(t *testing.T) {
+	tg := TransGlobal{}
+	tg.Status = dtmcli.StatusSubmitted
+	assert.Equal(t, uint64(0), tg.scheduleDelay())
+	at := time.Now().Add(10 * time.Second)
+	tg.ScheduleAt = &at
+	assert.Equal(t, uint64(10), tg.scheduleDelay()) // rounded up
+	assert.True(t, tg.isScheduled())
+	tg.Status = dtmcli.StatusAborting
+	assert.False(t, tg.isScheduled())
+
+	at = time.Now().Add(-time.Second)
+	assert.Equal(t, uint64(0), tg.scheduleDelay())
+}
+
+func TestIsTimeoutScheduled(t *testing.T) {
+	tg := TransGlobal{}
+	tg.TransType = "saga"
+	tg.TimeoutToFail = 10
+	created := time.Now().Add(-20 * time.Second)
+	tg.CreateTime = &created
+	assert.True(t, tg.isTimeout())
+	at := time.Now().Add(-5 * time.Second)
+	tg.ScheduleAt = &at
+	assert.False(t, tg.isTimeout())
+	at = time.Now().Add(-15 * time.Second)
+	assert.True(t, tg.isTimeout())
+}
+
+func TestTruncateString(t *testing.T) {
+	assert.Equal(t, "abc", truncateString("abc", 5))
+	assert.Equal(t, "ab", truncateString("abc", 2))
+	assert.Equal(t, "a", truncateString("a中文", 3)) // "中" is 3 bytes, can not be cut in half
+	assert.Equal(t, "a中", truncateString("a中文", 4))
+	assert.Equal(t, "a?", truncateString("a\xff", 5))
+}
+
+func TestCronPool(t *testing.T) {
+	p := newCronPool(3)
+	assert.Equal(t, 3, p.acquire())
+	p.release(2)
+	assert.Equal(t, 2, p.acquire()) // only the released workers are idle
+	p.release(3)
+	assert.Equal(t, 1, newCronPool(0).acquire())
+}
+
+func TestWaitUntil(t *testing.T) {
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.False(t, waitUntil(ctx, &wg))
+	wg.Done()
+	assert.True(t, waitUntil(context.Background(), &wg))
+	assert.True(t, sleepUnlessStopping(time.Millisecond))
+}
+
+func TestGoJob(t *testing.T) {
+	release := make(chan struct{})
+	goJob(func(num int) {
+		assert.Equal(t, -1, num)
+		<-release
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.False(t, waitUntil(ctx, &jobs)) // the job is waited as soon as goJob returns
+	close(release)
+	assert.True(t, waitUntil(context.Background(), &jobs))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr/config"
+	"github.com/dtm-labs/dtm/dtmsvr/storage/migrate"
+	"github.com/dtm-labs/dtm/dtmsvr/storage/registry"
+	"github.com/dtm-labs/logger"
+)
+
+var usage = `migrate copies the global transactions and their branches from a store to another.
+the transactions already in the target are overwritten if they are updated later in the source, so it can be rerun.
+usage:
+    go run ./helper/migrate -c from.yml -to to.yml [-verify] [-batch 100]
+options:
+`
+
+var fromFile = flag.String("c", "", "Path to the config file of the source store. the store configured by env is used if empty.")
+var toFile = flag.String("to", "", "Path to the config file of the target store.")
+var isVerify = flag.Bool("verify", false, "Only compare the source and the target, nothing is copied.")
+var batchSize = flag.Int64("batch", 100, "The number of global transactions scanned from the source in a batch.")
+var isDebug = flag.Bool("d", false, "Set log level to debug.")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *toFile == "" || *batchSize <= 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+	config.MustLoadConfig(*fromFile)
+	conf := &config.Config
+	if *isDebug {
+		conf.LogLevel = "debug"
+	}
+	logger.InitLog(conf.LogLevel)
+	toConf, err := config.LoadStoreConfig(*toFile)
+	logger.FatalIfError(err)
+	logger.FatalfIf(toConf.Driver == config.BoltDb && conf.Store.Driver == config.BoltDb, "can not migrate between boltdb stores")
+
+	src := registry.NewStore(&conf.Store)
+	dst := registry.NewStore(toConf)
+	ctx := context.Background()
+	logger.FatalIfError(src.Ping(ctx))
+	logger.FatalIfError(dst.Ping(ctx))
+	var r *migrate.Result
+	if *isVerify {
+		r, err = migrate.Verify(ctx, src, dst, *batchSize)
+	} else {
+		logger.FatalIfError(dst.PopulateData(ctx, true)) // create the tables or indexes of the target if not exist
+		r, err = migrate.Migrate(ctx, src, dst, *batchSize)
+	}
+	logger.FatalIfError(err)
+	fmt.Println(dtmimp.MustMarshalString(r))
+	if len(r.Missing) > 0 || len(r.Mismatched) > 0 {
+		os.Exit(2)
+	}
+}
@@ -18,7 +18,9 @@ import (
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
+	"github.com/dtm-labs/dtm/dtmsvr"
 	"github.com/dtm-labs/dtm/dtmsvr/config"
 	"github.com/dtm-labs/dtm/dtmsvr/entry"
 	_ "github.com/dtm-labs/dtm/dtmsvr/microservices"
@@ -37,6 +39,7 @@ func main() {
 		signal.Notify(q, syscall.SIGINT, syscall.SIGTERM)
 		<-q
 		logger.Infof("Shutdown dtm server...")
+		dtmsvr.Shutdown(time.Duration(conf.ShutdownTimeout) * time.Second)
 	}
 }
 
@@ -20,11 +20,15 @@ CREATE TABLE if not EXISTS trans_global (
   ext_data text,
   result varchar(1024) DEFAULT '',
   rollback_reason varchar(1024) DEFAULT '',
+  notify_status varchar(12) NOT NULL DEFAULT '',
   PRIMARY KEY (id),
   CONSTRAINT gid UNIQUE (gid)
 );
 create index if not EXISTS owner on trans_global(owner);
 create index if not EXISTS status_next_cron_time on trans_global (status, next_cron_time);
+create index if not EXISTS notify_status_next_cron_time on trans_global (notify_status, next_cron_time);
+create index if not EXISTS create_time on trans_global (create_time);
+create index if not EXISTS update_time on trans_global (update_time);
 drop table IF EXISTS trans_branch_op;
 
 CREATE SEQUENCE if not EXISTS trans_branch_op_seq;
@@ -41,6 +45,87 @@ CREATE TABLE IF NOT EXISTS trans_branch_op (
   rollback_time timestamp(0) with time zone DEFAULT NULL,
   create_time timestamp(0) with time zone DEFAULT NULL,
   update_time timestamp(0) with time zone DEFAULT NULL,
+  attempts int NOT NULL DEFAULT 0,
+  last_error varchar(1024) DEFAULT '',
+  last_status_code int NOT NULL DEFAULT 0,
+  last_response varchar(1024) DEFAULT '',
   PRIMARY KEY (id),
   CONSTRAINT gid_branch_uniq UNIQUE (gid, branch_id, op)
 );
+drop table IF EXISTS trans_event;
+
+CREATE SEQUENCE if not EXISTS trans_event_seq;
+CREATE TABLE IF NOT EXISTS trans_event (
+  id bigint NOT NULL DEFAULT NEXTVAL ('trans_event_seq'),
+  gid varchar(128) NOT NULL,
+  branch_id VARCHAR(128) NOT NULL DEFAULT '',
+  op varchar(45) NOT NULL DEFAULT '',
+  old_status varchar(45) NOT NULL DEFAULT '',
+  new_status varchar(45) NOT NULL,
+  source varchar(45) NOT NULL DEFAULT '',
+  owner varchar(128) NOT NULL DEFAULT '',
+  operator varchar(128) NOT NULL DEFAULT '',
+  error varchar(1024) DEFAULT '',
+  create_time timestamp(0) with time zone DEFAULT NULL,
+  PRIMARY KEY (id)
+);
+create index if not EXISTS trans_event_gid on trans_event(gid);
+-- the history tables keep the finished transactions archived by dtm. the columns should be the same as the original tables
+drop table IF EXISTS trans_global_history;
+CREATE TABLE IF NOT EXISTS trans_global_history (
+  id bigint NOT NULL,
+  gid varchar(128) NOT NULL,
+  trans_type varchar(45) not null,
+  status varchar(45) NOT NULL,
+  query_prepared varchar(1024) NOT NULL,
+  protocol varchar(45) not null,
+  create_time timestamp(0) with time zone DEFAULT NULL,
+  update_time timestamp(0) with time zone DEFAULT NULL,
+  finish_time timestamp(0) with time zone DEFAULT NULL,
+  rollback_time timestamp(0) with time zone DEFAULT NULL,
+  options varchar(1024) DEFAULT '',
+  custom_data varchar(1024) DEFAULT '',
+  next_cron_interval int default null,
+  next_cron_time timestamp(0) with time zone default null,
+  owner varchar(128) not null default '',
+  ext_data text,
+  result varchar(1024) DEFAULT '',
+  rollback_reason varchar(1024) DEFAULT '',
+  notify_status varchar(12) NOT NULL DEFAULT '',
+  PRIMARY KEY (id)
+);
+create index if not EXISTS trans_global_history_gid on trans_global_history(gid);
+drop table IF EXISTS trans_branch_op_history;
+CREATE TABLE IF NOT EXISTS trans_branch_op_history (
+  id bigint NOT NULL,
+  gid varchar(128) NOT NULL,
+  url varchar(1024) NOT NULL,
+  data TEXT,
+  bin_data bytea,
+  branch_id VARCHAR(128) NOT NULL,
+  op varchar(45) NOT NULL,
+  status varchar(45) NOT NULL,
+  finish_time timestamp(0) with time zone DEFAULT NULL,
+  rollback_time timestamp(0) with time zone DEFAULT NULL,
+  create_time timestamp(0) with time zone DEFAULT NULL,
+  update_time timestamp(0) with time zone DEFAULT NULL,
+  attempts int NOT NULL DEFAULT 0,
+  last_error varchar(1024) DEFAULT '',
+  last_status_code int NOT NULL DEFAULT 0,
+  last_response varchar(1024) DEFAULT '',
+  PRIMARY KEY (id)
+);
+create index if not EXISTS trans_branch_op_history_gid on trans_branch_op_history(gid);
+drop table IF EXISTS kv;
+CREATE SEQUENCE if not EXISTS kv_seq;
+CREATE TABLE IF NOT EXISTS kv (
+  id bigint NOT NULL DEFAULT NEXTVAL ('kv_seq'),
+  cat varchar(45) NOT NULL,
+  k varchar(128) NOT NULL,
+  v TEXT,
+  version bigint default 1,
+  create_time timestamp(0) with time zone DEFAULT NULL,
+  update_time timestamp(0) with time zone DEFAULT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT uniq_k UNIQUE (cat, k)
+);
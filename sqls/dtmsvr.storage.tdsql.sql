@@ -20,11 +20,15 @@ CREATE TABLE if not EXISTS dtm.trans_global (
   `owner` varchar(128) not null default '' comment 'who is locking this trans',
   `ext_data` TEXT comment 'extended data for this trans',
   `rollback_reason` varchar(1024) DEFAULT '' COMMENT 'rollback reason for transaction',
+  `notify_status` varchar(12) NOT NULL DEFAULT '' COMMENT 'status of calling notify url: prepared | succeed, empty if no notify url',
   PRIMARY KEY (`id`,`gid`),
   UNIQUE KEY `id` (`id`,`gid`),
   UNIQUE KEY `gid` (`gid`),
   key `owner`(`owner`),
-  key `status_next_cron_time` (`status`, `next_cron_time`) comment 'cron job will use this index to query trans'
+  key `status_next_cron_time` (`status`, `next_cron_time`) comment 'cron job will use this index to query trans',
+  key `notify_status_next_cron_time` (`notify_status`, `next_cron_time`) comment 'cron job will use this index to query finished trans to notify',
+  key `create_time` (`create_time`) comment 'used by the transaction search api',
+  key `update_time` (`update_time`) comment 'used by the transaction search api'
 ) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 shardkey=gid;
 drop table IF EXISTS dtm.trans_branch_op;
 CREATE TABLE IF NOT EXISTS dtm.trans_branch_op (
@@ -40,7 +44,72 @@ CREATE TABLE IF NOT EXISTS dtm.trans_branch_op (
   `rollback_time` datetime DEFAULT NULL,
   `create_time` datetime DEFAULT NULL,
   `update_time` datetime DEFAULT NULL,
+  `attempts` int(11) NOT NULL DEFAULT 0 COMMENT 'how many times the url of this op is called',
+  `last_error` varchar(1024) DEFAULT '' COMMENT 'error of the last call',
+  `last_status_code` int(11) NOT NULL DEFAULT 0 COMMENT 'http status code or grpc code of the last call',
+  `last_response` varchar(1024) DEFAULT '' COMMENT 'truncated response body of the last call',
   PRIMARY KEY (`id`,`gid`),
   UNIQUE KEY `id` (`id`,`gid`),
   UNIQUE KEY `gid_uniq` (`gid`, `branch_id`, `op`)
 ) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 shardkey=gid;
+drop table IF EXISTS dtm.trans_event;
+CREATE TABLE IF NOT EXISTS dtm.trans_event (
+  `id` bigint(22) NOT NULL AUTO_INCREMENT,
+  `gid` varchar(128) NOT NULL COMMENT 'global transaction id',
+  `branch_id` VARCHAR(128) NOT NULL DEFAULT '' COMMENT 'transaction branch ID, empty for global transaction',
+  `op` varchar(45) NOT NULL DEFAULT '' COMMENT 'transaction operation type of the branch',
+  `old_status` varchar(45) NOT NULL DEFAULT '' COMMENT 'status before the transition',
+  `new_status` varchar(45) NOT NULL COMMENT 'status after the transition',
+  `source` varchar(45) NOT NULL DEFAULT '' COMMENT 'what triggers the transition: api | cron | timeout | retry_limit | force_stop | retry_branch | resolve_branch | update_branch',
+  `owner` varchar(128) NOT NULL DEFAULT '' COMMENT 'the dtm server that makes the transition',
+  `operator` varchar(128) NOT NULL DEFAULT '' COMMENT 'the operator who makes the transition manually',
+  `error` varchar(1024) DEFAULT '' COMMENT 'error message of the transition',
+  `create_time` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`,`gid`),
+  key `gid`(`gid`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 shardkey=gid;
+-- the history tables keep the finished transactions archived by dtm. the columns should be the same as the original tables
+drop table IF EXISTS dtm.trans_global_history;
+CREATE TABLE IF NOT EXISTS dtm.trans_global_history (
+  `id` bigint(22) NOT NULL,
+  `gid` varchar(128) NOT NULL COMMENT 'global transaction id',
+  `trans_type` varchar(45) not null COMMENT 'transaction type: saga | xa | tcc | msg',
+  `status` varchar(12) NOT NULL COMMENT 'tranaction status: prepared | submitted | aborting | finished | rollbacked',
+  `query_prepared` varchar(1024) NOT NULL COMMENT 'url to check for 2-phase message',
+  `protocol` varchar(45) not null comment 'protocol: http | grpc | json-rpc',
+  `create_time` datetime DEFAULT NULL,
+  `update_time` datetime DEFAULT NULL,
+  `finish_time` datetime DEFAULT NULL,
+  `rollback_time` datetime DEFAULT NULL,
+  `options` varchar(1024) DEFAULT 'options for transaction like: TimeoutToFail, RequestTimeout',
+  `custom_data` varchar(1024) DEFAULT '' COMMENT 'custom data for transaction',
+  `next_cron_interval` int(11) default null comment 'next cron interval. for use of cron job',
+  `next_cron_time` datetime default null comment 'next time to process this trans. for use of cron job',
+  `owner` varchar(128) not null default '' comment 'who is locking this trans',
+  `ext_data` TEXT comment 'extended data for this trans',
+  `rollback_reason` varchar(1024) DEFAULT '' COMMENT 'rollback reason for transaction',
+  `notify_status` varchar(12) NOT NULL DEFAULT '' COMMENT 'status of calling notify url: prepared | succeed, empty if no notify url',
+  PRIMARY KEY (`id`,`gid`),
+  key `gid`(`gid`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 shardkey=gid;
+drop table IF EXISTS dtm.trans_branch_op_history;
+CREATE TABLE IF NOT EXISTS dtm.trans_branch_op_history (
+  `id` bigint(22) NOT NULL,
+  `gid` varchar(128) NOT NULL COMMENT 'global transaction id',
+  `url` varchar(1024) NOT NULL COMMENT 'the url of this op',
+  `data` TEXT COMMENT 'request body, depreceated',
+  `bin_data` BLOB COMMENT 'request body',
+  `branch_id` VARCHAR(128) NOT NULL COMMENT 'transaction branch ID',
+  `op` varchar(45) NOT NULL COMMENT 'transaction operation type like: action | compensate | try | confirm | cancel',
+  `status` varchar(45) NOT NULL COMMENT 'transaction op status: prepared | succeed | failed',
+  `finish_time` datetime DEFAULT NULL,
+  `rollback_time` datetime DEFAULT NULL,
+  `create_time` datetime DEFAULT NULL,
+  `update_time` datetime DEFAULT NULL,
+  `attempts` int(11) NOT NULL DEFAULT 0 COMMENT 'how many times the url of this op is called',
+  `last_error` varchar(1024) DEFAULT '' COMMENT 'error of the last call',
+  `last_status_code` int(11) NOT NULL DEFAULT 0 COMMENT 'http status code or grpc code of the last call',
+  `last_response` varchar(1024) DEFAULT '' COMMENT 'truncated response body of the last call',
+  PRIMARY KEY (`id`,`gid`),
+  key `gid`(`gid`)
+) ENGINE = InnoDB DEFAULT CHARSET = utf8mb4 shardkey=gid;
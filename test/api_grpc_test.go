@@ -0,0 +1,146 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/dtm-labs/dtm/client/dtmcli"
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/client/dtmgrpc"
+	"github.com/dtm-labs/dtm/client/dtmgrpc/dtmgpb"
+	"github.com/dtm-labs/dtm/dtmutil"
+	"github.com/dtm-labs/dtm/test/busi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIGrpcVersion(t *testing.T) {
+	version, err := dtmgrpc.GetVersion(dtmutil.DefaultGrpcServer)
+	assert.Nil(t, err)
+	assert.NotEqual(t, "", version)
+}
+
+func TestAPIGrpcQuery(t *testing.T) {
+	gid := dtmimp.GetFuncName()
+	err := genMsg(gid).Submit()
+	assert.Nil(t, err)
+	waitTransProcessed(gid)
+	r, err := dtmgrpc.QueryTrans(dtmutil.DefaultGrpcServer, gid)
+	assert.Nil(t, err)
+	assert.Equal(t, gid, r.Transaction.Gid)
+	assert.Equal(t, StatusSucceed, r.Transaction.Status)
+	assert.NotNil(t, r.Transaction.CreateTime)
+	assert.Equal(t, 2, len(r.Branches))
+
+	_, err = dtmgrpc.QueryTrans(dtmutil.DefaultGrpcServer, "")
+	assert.Error(t, err)
+
+	r, err = dtmgrpc.QueryTrans(dtmutil.DefaultGrpcServer, "1")
+	assert.Nil(t, err)
+	assert.Nil(t, r.Transaction)
+	assert.Equal(t, 0, len(r.Branches))
+}
+
+func TestAPIGrpcAll(t *testing.T) {
+	for i := 0; i < 3; i++ { // add three
+		gid := dtmimp.GetFuncName() + fmt.Sprintf("%d", i)
+		err := genMsg(gid).Submit()
+		assert.Nil(t, err)
+		waitTransProcessed(gid)
+	}
+	r, err := dtmgrpc.QueryAllTrans(dtmutil.DefaultGrpcServer, "", 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(r.Transactions))
+	nextPos := r.NextPosition
+	assert.NotEqual(t, "", nextPos)
+
+	r, err = dtmgrpc.QueryAllTrans(dtmutil.DefaultGrpcServer, nextPos, 1)
+	assert.Nil(t, err)
+	assert.NotEqual(t, "", r.NextPosition)
+	assert.NotEqual(t, nextPos, r.NextPosition)
+
+	r, err = dtmgrpc.QueryAllTrans(dtmutil.DefaultGrpcServer, nextPos, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, "", r.NextPosition)
+}
+
+func TestAPIGrpcResetCronTime(t *testing.T) {
+	testStoreResetCronTime(t, dtmimp.GetFuncName(), func(timeout int64, limit int64) (int64, bool, error) {
+		r, err := dtmgrpc.ResetCronTime(dtmutil.DefaultGrpcServer, timeout, limit)
+		if err != nil {
+			return 0, false, err
+		}
+		return r.SucceedCount, r.HasRemaining, nil
+	})
+}
+
+func TestAPIGrpcForceStopped(t *testing.T) {
+	saga := genSaga(dtmimp.GetFuncName(), false, false)
+	busi.MainSwitch.TransOutResult.SetOnce("ONGOING")
+	saga.Submit()
+	waitTransProcessed(saga.Gid)
+	assert.Equal(t, StatusSubmitted, getTransStatus(saga.Gid))
+
+	err := dtmgrpc.ForceStop(dtmutil.DefaultGrpcServer, saga.Gid)
+	assert.Nil(t, err)
+	assert.Equal(t, StatusFailed, getTransStatus(saga.Gid))
+
+	err = dtmgrpc.ForceStop(dtmutil.DefaultGrpcServer, saga.Gid)
+	assert.ErrorIs(t, err, dtmcli.ErrFailure)
+}
+
+func TestAPIGrpcStats(t *testing.T) {
+	gid := dtmimp.GetFuncName()
+	err := genMsg(gid).Submit()
+	assert.Nil(t, err)
+	waitTransProcessed(gid)
+	r, err := dtmgrpc.GetStats(dtmutil.DefaultGrpcServer, 0)
+	assert.Nil(t, err)
+	total := int64(0)
+	for _, c := range r.Counts {
+		if c.Status == StatusSucceed && c.TransType == "msg" {
+			total = c.Count
+		}
+	}
+	assert.True(t, total >= 1)
+	assert.True(t, len(r.FailingURLs) <= 10)
+
+	_, err = dtmgrpc.GetStats(dtmutil.DefaultGrpcServer, -1)
+	assert.Error(t, err)
+}
+
+func TestAPIGrpcWatch(t *testing.T) {
+	gid := dtmimp.GetFuncName()
+	saga := genSaga(gid, false, false)
+	busi.MainSwitch.TransInResult.SetOnce(dtmcli.ResultOngoing)
+	saga.Submit()
+	waitTransProcessed(gid)
+	assert.Equal(t, StatusSubmitted, getTransStatus(gid))
+
+	stream, err := dtmgrpc.WatchTrans(context.Background(), dtmutil.DefaultGrpcServer, &dtmgpb.DtmWatchRequest{Gid: gid})
+	assert.Nil(t, err)
+	e, err := stream.Recv() // the current status of the branches are sent first
+	assert.Nil(t, err)
+	assert.Equal(t, "01", e.BranchID)
+
+	cronTransOnce(t, gid)
+	var last *dtmgpb.DtmTransEvent
+	for e, err = stream.Recv(); err == nil; e, err = stream.Recv() {
+		last = e
+	}
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, "", last.BranchID)
+	assert.Equal(t, StatusSucceed, last.NewStatus)
+
+	stream, err = dtmgrpc.WatchTrans(context.Background(), dtmutil.DefaultGrpcServer, &dtmgpb.DtmWatchRequest{Gid: "not-exists"})
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+	assert.ErrorIs(t, dtmgrpc.GrpcError2DtmError(err), dtmcli.ErrFailure)
+}
@@ -11,11 +11,14 @@ import (
 	"net/http"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/dtm-labs/dtm/client/dtmcli"
 	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr"
 	"github.com/dtm-labs/dtm/dtmutil"
 	"github.com/dtm-labs/dtm/test/busi"
+	"github.com/go-resty/resty/v2"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -50,6 +53,34 @@ func TestAPIQuery(t *testing.T) {
 	assert.Equal(t, 0, len(m["branches"].([]interface{})))
 }
 
+func TestAPIQueryBranchAttempts(t *testing.T) {
+	gid := dtmimp.GetFuncName()
+	saga := genSaga1(gid, false, false)
+	saga.RetryInterval = 150 // CronForwardDuration is larger than RetryInterval
+	busi.MainSwitch.TransOutResult.SetOnce(dtmcli.ResultOngoing)
+	err := saga.Submit()
+	assert.Nil(t, err)
+	waitTransProcessed(gid)
+	queryAction := func() map[string]interface{} {
+		resp, err := dtmcli.GetRestyClient().R().SetQueryParam("gid", gid).Get(dtmutil.DefaultHTTPServer + "/query")
+		assert.Nil(t, err)
+		m := map[string]interface{}{}
+		dtmimp.MustUnmarshalString(resp.String(), &m)
+		return m["branches"].([]interface{})[1].(map[string]interface{})
+	}
+	action := queryAction()
+	assert.Equal(t, float64(1), action["attempts"])
+	assert.Contains(t, action["last_error"], dtmcli.ResultOngoing)
+	assert.NotEqual(t, nil, action["last_status_code"])
+
+	cronTransOnce(t, gid)
+	assert.Equal(t, StatusSucceed, getTransStatus(gid))
+	action = queryAction()
+	assert.Equal(t, float64(2), action["attempts"])
+	assert.Equal(t, nil, action["last_error"])
+	assert.Equal(t, float64(200), action["last_status_code"])
+}
+
 func TestAPIAll(t *testing.T) {
 	for i := 0; i < 3; i++ { // add three
 		gid := dtmimp.GetFuncName() + fmt.Sprintf("%d", i)
@@ -84,6 +115,250 @@ func TestAPIAll(t *testing.T) {
 	assert.Equal(t, "", nextPos3)
 }
 
+func TestAPIAllFilter(t *testing.T) {
+	prefix := dtmimp.GetFuncName()
+	for i := 0; i < 3; i++ {
+		gid := prefix + fmt.Sprintf("%d", i)
+		err := genMsg(gid).Submit()
+		assert.Nil(t, err)
+		waitTransProcessed(gid)
+	}
+	query := func(params map[string]string) ([]interface{}, string) {
+		resp, err := dtmcli.GetRestyClient().R().SetQueryParams(params).Get(dtmutil.DefaultHTTPServer + "/all")
+		assert.Nil(t, err)
+		assert.Equal(t, 200, resp.StatusCode())
+		m := map[string]interface{}{}
+		dtmimp.MustUnmarshalString(resp.String(), &m)
+		return m["transactions"].([]interface{}), m["next_position"].(string)
+	}
+	trans, nextPos := query(map[string]string{"gid_prefix": prefix, "sort_order": "asc", "limit": "2"})
+	assert.Equal(t, 2, len(trans))
+	assert.NotEqual(t, "", nextPos)
+	trans, nextPos = query(map[string]string{"gid_prefix": prefix, "sort_order": "asc", "limit": "2", "position": nextPos})
+	assert.Equal(t, 1, len(trans))
+	assert.Equal(t, "", nextPos)
+
+	trans, _ = query(map[string]string{"gid_prefix": prefix, "status": StatusSucceed, "trans_type": "msg"})
+	assert.Equal(t, 3, len(trans))
+	trans, _ = query(map[string]string{"gid_prefix": prefix, "status": StatusFailed})
+	assert.Equal(t, 0, len(trans))
+	trans, _ = query(map[string]string{"gid_prefix": prefix, "stuck_seconds": "1"})
+	assert.Equal(t, 0, len(trans))
+	trans, _ = query(map[string]string{"gid_prefix": prefix, "create_time_start": time.Now().Add(time.Hour).Format(time.RFC3339)})
+	assert.Equal(t, 0, len(trans))
+
+	resp, err := dtmcli.GetRestyClient().R().SetQueryParam("sort_by", "gid").Get(dtmutil.DefaultHTTPServer + "/all")
+	assert.Nil(t, err)
+	assert.Equal(t, 500, resp.StatusCode())
+}
+
+func TestAPIHistory(t *testing.T) {
+	saga := genSaga(dtmimp.GetFuncName(), false, true)
+	saga.Submit()
+	waitTransProcessed(saga.Gid)
+	assert.Equal(t, StatusFailed, getTransStatus(saga.Gid))
+
+	resp, err := dtmcli.GetRestyClient().R().SetQueryParam("gid", saga.Gid).Get(dtmutil.DefaultHTTPServer + "/history")
+	assert.Nil(t, err)
+	assert.Equal(t, 200, resp.StatusCode())
+	m := map[string][]map[string]interface{}{}
+	dtmimp.MustUnmarshalString(resp.String(), &m)
+	transitions := []string{}
+	for _, e := range m["events"] {
+		if e["branch_id"] == nil {
+			transitions = append(transitions, fmt.Sprintf("%v->%v", e["old_status"], e["new_status"]))
+		}
+		assert.Equal(t, "api", e["source"])
+		assert.NotEqual(t, nil, e["owner"])
+	}
+	assert.Equal(t, []string{"<nil>->submitted", "submitted->aborting", "aborting->failed"}, transitions)
+	assert.Equal(t, 7, len(m["events"])) // 3 global events + 4 branch events
+
+	resp, err = dtmcli.GetRestyClient().R().SetQueryParam("gid", "").Get(dtmutil.DefaultHTTPServer + "/history")
+	assert.Nil(t, err)
+	assert.Equal(t, 500, resp.StatusCode())
+}
+
+func TestAPIRetryBranch(t *testing.T) {
+	gid := dtmimp.GetFuncName()
+	saga := genSaga1(gid, false, false)
+	busi.MainSwitch.TransOutResult.SetOnce(dtmcli.ResultOngoing)
+	saga.Submit()
+	waitTransProcessed(gid)
+	assert.Equal(t, StatusSubmitted, getTransStatus(gid))
+
+	op := map[string]string{"gid": gid, "branch_id": "01", "op": dtmimp.OpAction}
+	assert.Equal(t, http.StatusConflict, postBranchOperation("retryBranch", op).StatusCode()) // no operator
+	op["operator"] = "tester"
+	assert.Equal(t, http.StatusOK, postBranchOperation("retryBranch", op).StatusCode())
+	waitTransProcessed(gid)
+	assert.Equal(t, StatusSucceed, getTransStatus(gid))
+	assert.Equal(t, []string{StatusPrepared, StatusSucceed}, getBranchesStatus(gid))
+	assert.Equal(t, http.StatusConflict, postBranchOperation("retryBranch", op).StatusCode()) // finished
+}
+
+func TestAPIResolveBranch(t *testing.T) {
+	gid := dtmimp.GetFuncName()
+	saga := genSaga1(gid, false, false)
+	busi.MainSwitch.TransOutResult.SetOnce(dtmcli.ResultOngoing)
+	saga.Submit()
+	waitTransProcessed(gid)
+
+	op := map[string]string{"gid": gid, "branch_id": "01", "op": dtmimp.OpCompensate, "operator": "tester", "status": StatusSucceed}
+	assert.Equal(t, http.StatusConflict, postBranchOperation("resolveBranch", op).StatusCode()) // compensate is not called in submitted
+	op["op"] = dtmimp.OpAction
+	op["status"] = StatusFailed
+	assert.Equal(t, http.StatusOK, postBranchOperation("resolveBranch", op).StatusCode())
+	waitTransProcessed(gid)
+	assert.Equal(t, StatusFailed, getTransStatus(gid))
+	assert.Equal(t, []string{StatusSucceed, StatusFailed}, getBranchesStatus(gid))
+
+	resp, err := dtmcli.GetRestyClient().R().SetQueryParam("gid", gid).Get(dtmutil.DefaultHTTPServer + "/history")
+	assert.Nil(t, err)
+	m := map[string][]map[string]interface{}{}
+	dtmimp.MustUnmarshalString(resp.String(), &m)
+	resolved := m["events"][1]
+	assert.Equal(t, "resolve_branch", resolved["source"])
+	assert.Equal(t, "tester", resolved["operator"])
+	assert.Equal(t, StatusFailed, resolved["new_status"])
+}
+
+func TestAPIUpdateBranch(t *testing.T) {
+	gid := dtmimp.GetFuncName()
+	saga := dtmcli.NewSaga(dtmutil.DefaultHTTPServer, gid)
+	req := busi.GenReqHTTP(30, false, false)
+	saga.Add(busi.Busi+"/NotExists", busi.Busi+"/TransOutRevert", &req)
+	saga.Submit()
+	waitTransProcessed(gid)
+	assert.Equal(t, StatusSubmitted, getTransStatus(gid))
+
+	op := map[string]string{"gid": gid, "branch_id": "01", "op": dtmimp.OpAction, "operator": "tester"}
+	assert.Equal(t, http.StatusConflict, postBranchOperation("updateBranch", op).StatusCode()) // nothing to update
+	op["url"] = busi.Busi + "/TransOut"
+	assert.Equal(t, http.StatusOK, postBranchOperation("updateBranch", op).StatusCode())
+	assert.Equal(t, StatusSubmitted, getTransStatus(gid))
+
+	cronTransOnce(t, gid)
+	assert.Equal(t, StatusSucceed, getTransStatus(gid))
+}
+
+func TestAPIWatch(t *testing.T) {
+	gid := dtmimp.GetFuncName()
+	err := genMsg(gid).Submit()
+	assert.Nil(t, err)
+	waitTransProcessed(gid)
+	// the transaction is finished, so the current status is sent, and the stream ends
+	resp, err := dtmcli.GetRestyClient().R().SetQueryParam("gid", gid).Get(dtmutil.DefaultHTTPServer + "/watch")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	assert.Contains(t, resp.Header().Get("Content-Type"), "text/event-stream")
+	assert.Contains(t, resp.String(), "event:change")
+	assert.Contains(t, resp.String(), `"new_status":"succeed"`)
+
+	resp, err = dtmcli.GetRestyClient().R().SetQueryParam("gid", "not-exists").Get(dtmutil.DefaultHTTPServer + "/watch")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusConflict, resp.StatusCode())
+}
+
+func TestAPIBulk(t *testing.T) {
+	prefix := dtmimp.GetFuncName()
+	for i := 0; i < 2; i++ {
+		err := genMsg(fmt.Sprintf("%s-%d", prefix, i)).Prepare("")
+		assert.Nil(t, err)
+	}
+	bulk := func(operation string, params map[string]string) map[string]interface{} {
+		params["gid_prefix"] = prefix
+		params["status"] = StatusPrepared
+		params["trans_type"] = "msg"
+		resp, err := dtmcli.GetRestyClient().R().SetQueryParams(params).Post(dtmutil.DefaultHTTPServer + "/" + operation)
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		m := map[string]interface{}{}
+		dtmimp.MustUnmarshalString(resp.String(), &m)
+		return m
+	}
+	m := bulk("bulkForceStop", map[string]string{"dry_run": "true"})
+	assert.Equal(t, []interface{}{prefix + "-0", prefix + "-1"}, m["gids"])
+	assert.Equal(t, StatusPrepared, getTransStatus(prefix+"-0"))
+
+	m = bulk("bulkForceStop", map[string]string{"url_contains": "NotExists", "dry_run": "true"})
+	assert.Equal(t, 0, len(m["gids"].([]interface{})))
+
+	m = bulk("bulkRetryNow", map[string]string{"limit": "1", "batch_size": "1"})
+	assert.Equal(t, []interface{}{prefix + "-0"}, m["gids"])
+	assert.Equal(t, true, m["has_remaining"])
+	assert.False(t, getTrans(prefix+"-0").NextCronTime.After(time.Now()))
+	assert.True(t, getTrans(prefix+"-1").NextCronTime.After(time.Now()))
+
+	m = bulk("bulkForceStop", map[string]string{"url_contains": "/TransIn"})
+	assert.Equal(t, 2, len(m["gids"].([]interface{})))
+	assert.Equal(t, nil, m["errors"])
+	assert.Equal(t, StatusFailed, getTransStatus(prefix+"-0"))
+	assert.Equal(t, StatusFailed, getTransStatus(prefix+"-1"))
+
+	resp, err := dtmcli.GetRestyClient().R().SetQueryParam("batch_size", "0").Post(dtmutil.DefaultHTTPServer + "/bulkResume")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode())
+}
+
+func TestAPIStats(t *testing.T) {
+	gid := dtmimp.GetFuncName()
+	saga := genSaga1(gid, false, false)
+	busi.MainSwitch.TransOutResult.SetOnce(dtmcli.ResultOngoing)
+	saga.Submit()
+	waitTransProcessed(gid)
+	assert.Equal(t, StatusSubmitted, getTransStatus(gid))
+
+	stats := getStats(t)
+	failing := stats.failingCount(busi.Busi + "/TransOut")
+	assert.True(t, failing >= 1)
+	assert.True(t, stats.transCount(StatusSubmitted, "saga") >= 1)
+	assert.NotNil(t, stats.OldestUnfinishedTime)
+
+	cronTransOnce(t, gid)
+	assert.Equal(t, StatusSucceed, getTransStatus(gid))
+	assert.Equal(t, failing-1, getStats(t).failingCount(busi.Busi+"/TransOut"))
+
+	resp, err := dtmcli.GetRestyClient().R().SetQueryParam("top_urls", "0").Get(dtmutil.DefaultHTTPServer + "/stats")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode())
+}
+
+type statsResult dtmsvr.StatsResult
+
+func getStats(t *testing.T) *statsResult {
+	resp, err := dtmcli.GetRestyClient().R().SetQueryParam("top_urls", "1000").Get(dtmutil.DefaultHTTPServer + "/stats")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	stats := &statsResult{}
+	dtmimp.MustUnmarshal(resp.Body(), stats)
+	return stats
+}
+
+func (s *statsResult) failingCount(url string) int64 {
+	for _, u := range s.FailingURLs {
+		if u.URL == url {
+			return u.Count
+		}
+	}
+	return 0
+}
+
+func (s *statsResult) transCount(status string, transType string) int64 {
+	for _, c := range s.Counts {
+		if c.Status == status && c.TransType == transType {
+			return c.Count
+		}
+	}
+	return 0
+}
+
+func postBranchOperation(api string, op map[string]string) *resty.Response {
+	resp, err := dtmcli.GetRestyClient().R().SetBody(op).Post(dtmutil.DefaultHTTPServer + "/" + api)
+	e2p(err)
+	return resp
+}
+
 func TestDtmMetrics(t *testing.T) {
 	rest, err := dtmcli.GetRestyClient().R().Get("http://localhost:36789/api/metrics")
 	assert.Nil(t, err)
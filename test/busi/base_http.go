@@ -153,6 +153,11 @@ func BaseAddRoute(app *gin.Engine) {
 			return SagaAdjustBalance(db, TransOutUID, -reqFrom(c).Amount, reqFrom(c).TransOutResult)
 		})
 	}))
+	app.POST(BusiAPI+"/TransInBlocked", dtmutil.WrapHandler(func(c *gin.Context) interface{} {
+		TransInBlocked <- c.Query("gid")
+		<-TransInReleased
+		return handleGeneralBusiness(c, MainSwitch.TransInResult.Fetch(), reqFrom(c).TransInResult, "transIn")
+	}))
 	app.POST(BusiAPI+"/TransOutTimeout", dtmutil.WrapHandler(func(c *gin.Context) interface{} {
 		return handleGeneralBusiness(c, MainSwitch.TransOutResult.Fetch(), reqFrom(c).TransOutResult, "TransOut")
 	}))
@@ -186,6 +191,17 @@ func BaseAddRoute(app *gin.Engine) {
 		})
 	}))
 
+	app.POST(BusiAPI+"/Notify", dtmutil.WrapHandler(func(c *gin.Context) interface{} {
+		n := map[string]string{}
+		err := c.BindJSON(&n)
+		logger.FatalIfError(err)
+		logger.Debugf("%s Notify: %v", n["gid"], n)
+		res := MainSwitch.NotifyResult.Fetch()
+		if res == "" {
+			Notifications.Store(n["gid"], n)
+		}
+		return string2DtmError(res)
+	}))
 	app.POST(BusiAPI+"/TestPanic", dtmutil.WrapHandler2(func(c *gin.Context) interface{} {
 		if c.Query("panic_error") != "" {
 			panic(errors.New("panic_error"))
@@ -9,6 +9,7 @@ package busi
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/dtm-labs/dtm/client/dtmcli"
 	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
@@ -143,11 +144,21 @@ type mainSwitchType struct {
 	NextResult            AutoEmptyString
 	JrpcResult            AutoEmptyString
 	FailureReason         AutoEmptyString
+	NotifyResult          AutoEmptyString
 }
 
 // MainSwitch controls busi success or fail
 var MainSwitch mainSwitchType
 
+// TransInBlocked receives the gid when /TransInBlocked is called, and the call is blocked until TransInReleased is sent
+var TransInBlocked = make(chan string, 1)
+
+// TransInReleased releases the call of /TransInBlocked
+var TransInReleased = make(chan struct{})
+
+// Notifications records the successful notifications received by /Notify, gid => notification
+var Notifications sync.Map
+
 // GetRedisAccountKey return redis key for uid
 func GetRedisAccountKey(uid int) string {
 	return fmt.Sprintf("{a}-redis-account-key-%d", uid)
@@ -0,0 +1,45 @@
+package test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/dtm-labs/dtm/client/dtmcli"
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/dtm/dtmutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCluster(t *testing.T) {
+	registry, ok := dtmsvr.GetStore().(storage.NodeRegistry)
+	if ok {
+		last := time.Now().Add(-time.Hour)
+		assert.Nil(t, registry.SaveNode(ctx, &storage.NodeStore{ID: "dead-node", StartTime: &last, HeartbeatTime: &last}))
+		dtmsvr.CronHeartbeat(1) // the heartbeat of this node is saved, and the dead node is removed
+	}
+	nodes := getCluster(t)
+	self := 0
+	for _, n := range nodes {
+		assert.NotEqual(t, "dead-node", n.ID)
+		if n.Self {
+			self++
+			assert.True(t, n.Alive)
+			assert.Equal(t, dtmsvr.Version, n.Version)
+		}
+	}
+	assert.Equal(t, 1, self)
+}
+
+func getCluster(t *testing.T) []dtmsvr.ClusterNode {
+	resp, err := dtmcli.GetRestyClient().R().Get(dtmutil.DefaultHTTPServer + "/cluster")
+	assert.Nil(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode())
+	result := struct {
+		Nodes []dtmsvr.ClusterNode `json:"nodes"`
+	}{}
+	dtmimp.MustUnmarshal(resp.Body(), &result)
+	return result.Nodes
+}
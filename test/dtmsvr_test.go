@@ -26,16 +26,21 @@ var DtmServer = dtmutil.DefaultHTTPServer
 var DtmGrpcServer = dtmutil.DefaultGrpcServer
 var Busi = busi.Busi
 
+var ctx = context.Background()
+
 func getTransStatus(gid string) string {
-	return dtmsvr.GetTransGlobal(gid).Status
+	return getTrans(gid).Status
 }
 
 func getTrans(gid string) *dtmsvr.TransGlobal {
-	return dtmsvr.GetTransGlobal(gid)
+	trans, err := dtmsvr.GetTransGlobal(ctx, gid)
+	dtmimp.E2P(err)
+	return trans
 }
 
 func getBranchesStatus(gid string) []string {
-	branches := dtmsvr.GetStore().FindBranches(gid)
+	branches, err := dtmsvr.GetStore().FindBranches(ctx, gid)
+	dtmimp.E2P(err)
 	status := []string{}
 	for _, branch := range branches {
 		status = append(status, branch.Status)
@@ -47,9 +52,6 @@ func isSqlStore() bool {
 	return conf.Store.Driver == config.Mysql || conf.Store.Driver == config.Postgres
 }
 func TestUpdateBranchAsync(t *testing.T) {
-	if !isSqlStore() {
-		return
-	}
 	conf.UpdateBranchSync = 0
 	saga := genSaga1(dtmimp.GetFuncName(), false, false)
 	saga.WaitResult = true
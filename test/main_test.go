@@ -37,7 +37,7 @@ func TestMain(m *testing.M) {
 	tenv := dtmimp.OrString(os.Getenv("TEST_STORE"), config.Redis)
 	conf.Store.Host = "localhost"
 	conf.Store.Driver = tenv
-	if tenv == "boltdb" {
+	if tenv == "boltdb" || tenv == config.Memory {
 	} else if tenv == config.Mysql {
 		conf.Store.Port = 3306
 		conf.Store.User = "root"
@@ -50,6 +50,10 @@ func TestMain(m *testing.M) {
 		conf.Store.User = ""
 		conf.Store.Password = ""
 		conf.Store.Port = 6379
+	} else if tenv == config.Mongo {
+		conf.Store.User = ""
+		conf.Store.Password = ""
+		conf.Store.Port = 27017
 	}
 	conf.Store.Db = ""
 	registry.WaitStoreUp()
@@ -0,0 +1,41 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/dtm/dtmsvr/storage/memory"
+	"github.com/dtm-labs/dtm/dtmsvr/storage/migrate"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrateToStoreWithRows(t *testing.T) {
+	gid := dtmimp.GetFuncName()
+	existing, dst := initTransGlobal(gid + "-existing")
+	branches, err := dst.FindBranches(ctx, existing.Gid)
+	assert.Nil(t, err)
+
+	src := memory.NewStore(100, 10, 10)
+	now := time.Now()
+	g := &storage.TransGlobalStore{Gid: gid, TransType: "saga", Status: "submitted", NextCronTime: &now}
+	g.ID = existing.ID // the ids of the source collide with the rows of the target
+	g.CreateTime = &now
+	bs := []storage.TransBranchStore{{Gid: gid, BranchID: "01", Op: "action", URL: "url1", Status: "prepared"}}
+	if len(branches) > 0 {
+		bs[0].ID = branches[0].ID
+	}
+	assert.Nil(t, src.MaySaveNewTrans(ctx, g, bs))
+
+	r, err := migrate.Migrate(ctx, src, dst, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), r.Copied)
+	assert.Empty(t, r.Mismatched)
+	r, err = migrate.Verify(ctx, src, dst, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(1), r.Same)
+	assert.Empty(t, r.Missing)
+
+	initTransGlobal(gid + "-after") // the ids assigned by the target are not taken by the migrated rows
+}
@@ -37,3 +37,19 @@ func TestMsgDelayNormal(t *testing.T) {
 	assert.Equal(t, []string{StatusSucceed, StatusSucceed}, getBranchesStatus(msg.Gid))
 	assert.Equal(t, StatusSucceed, getTransStatus(msg.Gid))
 }
+
+func TestMsgGrpcDelay(t *testing.T) {
+	gid := dtmimp.GetFuncName()
+	msg := genGrpcMsg(gid).SetDelay(10)
+	submitForwardCron(0, func() {
+		err := msg.Submit()
+		assert.Nil(t, err)
+		waitTransProcessed(msg.Gid)
+	})
+
+	assert.Equal(t, StatusSubmitted, getTransStatus(msg.Gid))
+	cronTransOnceForwardCron(t, "", 8)
+	cronTransOnceForwardCron(t, gid, 12)
+	assert.Equal(t, []string{StatusSucceed, StatusSucceed}, getBranchesStatus(msg.Gid))
+	assert.Equal(t, StatusSucceed, getTransStatus(msg.Gid))
+}
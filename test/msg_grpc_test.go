@@ -58,6 +58,21 @@ func TestMsgGrpcTimeoutFailed(t *testing.T) {
 	assert.Equal(t, []string{StatusPrepared, StatusPrepared}, getBranchesStatus(msg.Gid))
 }
 
+func TestMsgGrpcAbortSubmitted(t *testing.T) {
+	msg := genGrpcMsg(dtmimp.GetFuncName())
+	busi.MainSwitch.TransInResult.SetOnce(dtmcli.ResultOngoing)
+	msg.Submit()
+	waitTransProcessed(msg.Gid)
+	assert.Equal(t, StatusSubmitted, getTransStatus(msg.Gid))
+
+	err := dtmgrpc.NewMsgGrpc(dtmutil.DefaultGrpcServer, msg.Gid).Abort("cancelled by operator")
+	assert.ErrorIs(t, err, dtmcli.ErrFailure) // the submitted msg can only be force stopped
+	assert.Equal(t, StatusSubmitted, getTransStatus(msg.Gid))
+	cronTransOnce(t, msg.Gid)
+	assert.Equal(t, []string{StatusSucceed, StatusSucceed}, getBranchesStatus(msg.Gid))
+	assert.Equal(t, StatusSucceed, getTransStatus(msg.Gid))
+}
+
 func genGrpcMsg(gid string) *dtmgrpc.MsgGrpc {
 	req := &busi.ReqGrpc{Amount: 30}
 	msg := dtmgrpc.NewMsgGrpc(dtmutil.DefaultGrpcServer, gid).
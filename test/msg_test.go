@@ -67,6 +67,21 @@ func TestMsgAbnormal(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestMsgAbortSubmitted(t *testing.T) {
+	msg := genMsg(dtmimp.GetFuncName())
+	busi.MainSwitch.TransInResult.SetOnce(dtmcli.ResultOngoing)
+	msg.Submit()
+	waitTransProcessed(msg.Gid)
+	assert.Equal(t, StatusSubmitted, getTransStatus(msg.Gid))
+
+	err := dtmcli.NewMsg(dtmutil.DefaultHTTPServer, msg.Gid).Abort("cancelled by operator")
+	assert.ErrorIs(t, err, dtmcli.ErrFailure) // the submitted msg can only be force stopped
+	assert.Equal(t, StatusSubmitted, getTransStatus(msg.Gid))
+	cronTransOnce(t, msg.Gid)
+	assert.Equal(t, []string{StatusSucceed, StatusSucceed}, getBranchesStatus(msg.Gid))
+	assert.Equal(t, StatusSucceed, getTransStatus(msg.Gid))
+}
+
 func genMsg(gid string) *dtmcli.Msg {
 	req := busi.GenReqHTTP(30, false, false)
 	msg := dtmcli.NewMsg(dtmutil.DefaultHTTPServer, gid).
@@ -0,0 +1,62 @@
+/*
+ * Copyright (c) 2021 yedf. All rights reserved.
+ * Use of this source code is governed by a BSD-style
+ * license that can be found in the LICENSE file.
+ */
+
+package test
+
+import (
+	"testing"
+
+	"github.com/dtm-labs/dtm/client/dtmcli"
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/test/busi"
+	"github.com/stretchr/testify/assert"
+)
+
+func getNotification(gid string) map[string]string {
+	n, ok := busi.Notifications.Load(gid)
+	if !ok {
+		return nil
+	}
+	return n.(map[string]string)
+}
+
+func TestNotifySucceed(t *testing.T) {
+	saga := genSaga(dtmimp.GetFuncName(), false, false)
+	saga.WithNotifyURL(busi.Busi + "/Notify")
+	saga.Submit()
+	waitTransProcessed(saga.Gid)
+	assert.Equal(t, StatusSucceed, getTransStatus(saga.Gid))
+	assert.Equal(t, StatusSucceed, getTrans(saga.Gid).NotifyStatus)
+	n := getNotification(saga.Gid)
+	assert.Equal(t, StatusSucceed, n["status"])
+	assert.Equal(t, "saga", n["trans_type"])
+}
+
+func TestNotifyRetry(t *testing.T) {
+	saga := genSaga(dtmimp.GetFuncName(), true, false)
+	saga.WithNotifyURL(busi.Busi + "/Notify")
+	busi.MainSwitch.NotifyResult.SetOnce(dtmcli.ResultOngoing)
+	saga.Submit()
+	waitTransProcessed(saga.Gid)
+	assert.Equal(t, StatusFailed, getTransStatus(saga.Gid))
+	assert.Equal(t, StatusPrepared, getTrans(saga.Gid).NotifyStatus)
+	assert.Nil(t, getNotification(saga.Gid))
+
+	cronTransOnce(t, saga.Gid)
+	assert.Equal(t, StatusSucceed, getTrans(saga.Gid).NotifyStatus)
+	n := getNotification(saga.Gid)
+	assert.Equal(t, StatusFailed, n["status"])
+	assert.NotEqual(t, "", n["rollback_reason"])
+}
+
+func TestNotifyWithoutURL(t *testing.T) {
+	msg := genMsg(dtmimp.GetFuncName())
+	msg.Submit()
+	waitTransProcessed(msg.Gid)
+	assert.Equal(t, StatusSucceed, getTransStatus(msg.Gid))
+	assert.Equal(t, "", getTrans(msg.Gid).NotifyStatus)
+	assert.Nil(t, getNotification(msg.Gid))
+}
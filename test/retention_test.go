@@ -0,0 +1,57 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/dtm-labs/dtm/client/dtmcli"
+	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr"
+	"github.com/dtm-labs/dtm/dtmsvr/config"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/dtm/dtmutil"
+	"github.com/dtm-labs/dtm/test/busi"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetention(t *testing.T) {
+	conf.Retention = config.Retention{Mode: config.RetentionArchive, Age: -10, BatchSize: 1, Interval: 1}
+	defer func() { conf.Retention = config.Retention{} }()
+	if !isSqlStore() {
+		dtmsvr.CronPurgeFinished(1) // ignored by the stores expiring the data by themselves
+		return
+	}
+	gid := dtmimp.GetFuncName()
+	ongoingGid := gid + "-ongoing"
+	assert.Nil(t, genSaga1(gid, false, false).Submit())
+	waitTransProcessed(gid)
+	busi.MainSwitch.TransOutResult.SetOnce(dtmcli.ResultOngoing)
+	assert.Nil(t, genSaga1(ongoingGid, false, false).Submit())
+	waitTransProcessed(ongoingGid)
+
+	dtmsvr.CronPurgeFinished(1)
+	_, err := dtmsvr.GetStore().FindTransGlobalStore(ctx, gid)
+	assert.Equal(t, storage.ErrNotFound, err)
+	branches, err := dtmsvr.GetStore().FindBranches(ctx, gid)
+	assert.Nil(t, err)
+	assert.Empty(t, branches)
+	assert.Equal(t, int64(1), countHistory("trans_global_history", gid))
+	assert.Equal(t, int64(2), countHistory("trans_branch_op_history", gid))
+	_, err = dtmsvr.GetStore().FindTransGlobalStore(ctx, ongoingGid)
+	assert.Nil(t, err) // unfinished trans is kept
+
+	cronTransOnce(t, ongoingGid)
+	assert.Equal(t, StatusSucceed, getTransStatus(ongoingGid))
+	conf.Retention.Mode = config.RetentionDelete
+	purged, err := dtmsvr.PurgeFinishedOnce()
+	assert.Nil(t, err)
+	assert.True(t, purged >= 1)
+	_, err = dtmsvr.GetStore().FindTransGlobalStore(ctx, ongoingGid)
+	assert.Equal(t, storage.ErrNotFound, err)
+	assert.Equal(t, int64(0), countHistory("trans_global_history", ongoingGid))
+}
+
+func countHistory(table string, gid string) int64 {
+	count := int64(0)
+	dtmutil.DbGet(conf.Store.GetDBConf()).Must().Table(table).Where("gid = ?", gid).Count(&count)
+	return count
+}
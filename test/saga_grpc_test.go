@@ -92,6 +92,21 @@ func TestSagaGrpcEmptyUrl(t *testing.T) {
 	assert.Equal(t, StatusSucceed, getTransStatus(saga.Gid))
 }
 
+func TestSagaGrpcAbortSubmitted(t *testing.T) {
+	saga := genSagaGrpc(dtmimp.GetFuncName(), false, false)
+	busi.MainSwitch.TransInResult.SetOnce(dtmcli.ResultOngoing)
+	saga.Submit()
+	waitTransProcessed(saga.Gid)
+	assert.Equal(t, StatusSubmitted, getTransStatus(saga.Gid))
+
+	err := dtmgrpc.NewSagaGrpc(dtmutil.DefaultGrpcServer, saga.Gid).Abort("cancelled by operator")
+	assert.Nil(t, err)
+	waitTransProcessed(saga.Gid)
+	assert.Equal(t, []string{StatusSucceed, StatusSucceed, StatusSucceed, StatusPrepared}, getBranchesStatus(saga.Gid))
+	assert.Equal(t, StatusFailed, getTransStatus(saga.Gid))
+	assert.Equal(t, "cancelled by operator", getTrans(saga.Gid).RollbackReason)
+}
+
 //nolint: unparam
 func genSagaGrpc(gid string, outFailed bool, inFailed bool) *dtmgrpc.SagaGrpc {
 	saga := dtmgrpc.NewSagaGrpc(dtmutil.DefaultGrpcServer, gid)
@@ -8,6 +8,7 @@ package test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/dtm-labs/dtm/client/dtmcli"
 	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
@@ -45,6 +46,25 @@ func TestSagaOptionsRetryError(t *testing.T) {
 	assert.Equal(t, []string{StatusPrepared, StatusSucceed}, getBranchesStatus(saga.Gid))
 }
 
+func TestSagaOptionsRetryPolicy(t *testing.T) {
+	gid := dtmimp.GetFuncName()
+	saga := genSaga1(gid, false, false)
+	saga.WithRetryPolicy(dtmcli.RetryPolicyFixed, 0)
+	saga.RetryInterval = 150 // CronForwardDuration is larger than RetryInterval, which is not doubled by the fixed policy
+	busi.MainSwitch.TransOutResult.SetOnce("ERROR")
+	err := saga.Submit()
+	assert.Nil(t, err)
+	waitTransProcessed(saga.Gid)
+	assert.Equal(t, StatusSubmitted, getTransStatus(saga.Gid))
+	assert.Equal(t, int64(150), getTrans(gid).NextCronInterval)
+	cronTransOnce(t, gid)
+	assert.Equal(t, StatusSucceed, getTransStatus(saga.Gid))
+
+	saga = genSaga1(gid+"-bad", false, false)
+	saga.RetryPolicy = "linear"
+	assert.Error(t, saga.Submit())
+}
+
 func TestSagaOptionsTimeout(t *testing.T) {
 	gid := dtmimp.GetFuncName()
 	saga := genSaga(dtmimp.GetFuncName(), false, false)
@@ -168,3 +188,24 @@ func TestSagaGlobalTransWithRetryLimitNo(t *testing.T) {
 	assert.Equal(t, []string{StatusSucceed, StatusSucceed, StatusSucceed, StatusPrepared}, getBranchesStatus(saga.Gid))
 	assert.Equal(t, `RetryCount is greater than RetryLimit, RetryLimit: 1`, getTrans(gid).RollbackReason)
 }
+
+func TestSagaOptionsScheduleAt(t *testing.T) {
+	gid := dtmimp.GetFuncName()
+	saga := genSaga(gid, false, false)
+	saga.WaitResult = true
+	at := time.Now().Add(10 * time.Second)
+	saga.WithScheduleAt(at)
+	submitForwardCron(0, func() {
+		err := saga.Submit()
+		assert.Nil(t, err) // the scheduled saga is not waited
+		waitTransProcessed(saga.Gid)
+	})
+	assert.WithinDuration(t, at, *getTrans(saga.Gid).NextCronTime, 2*time.Second)
+
+	assert.Equal(t, StatusSubmitted, getTransStatus(saga.Gid))
+	assert.Equal(t, []string{StatusPrepared, StatusPrepared, StatusPrepared, StatusPrepared}, getBranchesStatus(saga.Gid))
+	cronTransOnceForwardCron(t, "", 8)
+	cronTransOnceForwardCron(t, gid, 12)
+	assert.Equal(t, StatusSucceed, getTransStatus(saga.Gid))
+	assert.Equal(t, []string{StatusPrepared, StatusSucceed, StatusPrepared, StatusSucceed}, getBranchesStatus(saga.Gid))
+}
@@ -87,6 +87,46 @@ func TestSagaEmptyUrl(t *testing.T) {
 	assert.Equal(t, StatusSucceed, getTransStatus(saga.Gid))
 }
 
+func TestSagaAbortSubmitted(t *testing.T) {
+	saga := genSaga(dtmimp.GetFuncName(), false, false)
+	busi.MainSwitch.TransInResult.SetOnce(dtmcli.ResultOngoing)
+	saga.Submit()
+	waitTransProcessed(saga.Gid)
+	assert.Equal(t, StatusSubmitted, getTransStatus(saga.Gid))
+
+	err := dtmcli.NewSaga(dtmutil.DefaultHTTPServer, saga.Gid).Abort("cancelled by operator")
+	assert.Nil(t, err)
+	waitTransProcessed(saga.Gid)
+	// the started TransIn is compensated, while its action is still prepared
+	assert.Equal(t, []string{StatusSucceed, StatusSucceed, StatusSucceed, StatusPrepared}, getBranchesStatus(saga.Gid))
+	assert.Equal(t, StatusFailed, getTransStatus(saga.Gid))
+	assert.Equal(t, "cancelled by operator", getTrans(saga.Gid).RollbackReason)
+
+	err = dtmcli.NewSaga(dtmutil.DefaultHTTPServer, saga.Gid).Abort("abort again")
+	assert.ErrorIs(t, err, dtmcli.ErrFailure)
+}
+
+func TestSagaAbortInFlight(t *testing.T) {
+	gid := dtmimp.GetFuncName()
+	saga := dtmcli.NewSaga(dtmutil.DefaultHTTPServer, gid)
+	req := busi.GenReqHTTP(30, false, false)
+	saga.Add(busi.Busi+"/TransOut", busi.Busi+"/TransOutRevert", &req)
+	saga.Add(busi.Busi+"/TransInBlocked", busi.Busi+"/TransInRevert", &req)
+	saga.Submit()
+	assert.Equal(t, gid, <-busi.TransInBlocked) // TransIn is in flight
+
+	err := dtmcli.NewSaga(dtmutil.DefaultHTTPServer, gid).Abort("cancelled by operator")
+	assert.Nil(t, err)
+	waitTransProcessed(gid)
+	assert.Equal(t, StatusFailed, getTransStatus(gid))
+
+	busi.TransInReleased <- struct{}{}
+	waitTransProcessed(gid) // the result of TransIn is not saved, since the saga is aborted
+	assert.Equal(t, StatusFailed, getTransStatus(gid))
+	// the in flight TransIn is compensated, though its action is still prepared
+	assert.Equal(t, []string{StatusSucceed, StatusSucceed, StatusSucceed, StatusPrepared}, getBranchesStatus(gid))
+}
+
 func genSaga(gid string, outFailed bool, inFailed bool) *dtmcli.Saga {
 	saga := dtmcli.NewSaga(dtmutil.DefaultHTTPServer, gid)
 	req := busi.GenReqHTTP(30, outFailed, inFailed)
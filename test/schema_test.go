@@ -0,0 +1,40 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/dtm-labs/dtm/dtmsvr"
+	"github.com/dtm-labs/dtm/dtmsvr/storage"
+	"github.com/dtm-labs/dtm/dtmutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaMigrate(t *testing.T) {
+	migrator, ok := dtmsvr.GetStore().(storage.SchemaMigrator)
+	if !ok {
+		assert.Nil(t, dtmsvr.CheckSchema(true)) // ignored by the stores without schema
+		return
+	}
+	current, latest, err := migrator.SchemaVersion(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, latest, current) // the schema is migrated by PopulateDB
+	assert.Nil(t, dtmsvr.CheckSchema(false))
+
+	setSchemaVersion(latest + 1)
+	assert.Error(t, dtmsvr.CheckSchema(true)) // the schema is newer than dtm
+	assert.Nil(t, migrator.MigrateSchema(ctx))
+	current, _, err = migrator.SchemaVersion(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, latest+1, current) // the newer schema is not touched
+
+	setSchemaVersion(0)                        // the migrations can be rerun on the existing tables
+	assert.Error(t, dtmsvr.CheckSchema(false)) // the older schema should be migrated
+	assert.Nil(t, dtmsvr.CheckSchema(true))
+	current, _, err = migrator.SchemaVersion(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, latest, current)
+}
+
+func setSchemaVersion(version int) {
+	dtmutil.DbGet(conf.Store.GetDBConf()).Must().Exec("update kv set v = ? where cat = 'dtm' and k = 'schema_version'", version)
+}
@@ -1,14 +1,19 @@
 package test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/dtm-labs/dtm/client/dtmcli/dtmimp"
+	"github.com/dtm-labs/dtm/dtmsvr/config"
 	"github.com/dtm-labs/dtm/dtmsvr/storage"
 	"github.com/dtm-labs/dtm/dtmsvr/storage/registry"
 	"github.com/dtm-labs/dtm/dtmutil"
+	"github.com/dtm-labs/dtm/test/busi"
+	"github.com/go-redis/redis/v8"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -23,7 +28,7 @@ func initTransGlobalByNextCronTime(gid string, next time.Time) (*storage.TransGl
 		{Gid: gid, BranchID: "01"},
 	}
 	s := registry.GetStore()
-	err := s.MaySaveNewTrans(g, bs)
+	err := s.MaySaveNewTrans(ctx, g, bs)
 	dtmimp.E2P(err)
 	return g, s
 }
@@ -35,39 +40,47 @@ func TestStoreSave(t *testing.T) {
 		{Gid: gid, BranchID: "02"},
 	}
 	g, s := initTransGlobal(gid)
-	g2 := s.FindTransGlobalStore(gid)
-	assert.NotNil(t, g2)
+	g2, err := s.FindTransGlobalStore(ctx, gid)
+	assert.Nil(t, err)
 	assert.Equal(t, gid, g2.Gid)
+	_, err = s.FindTransGlobalStore(ctx, gid+"-none")
+	assert.Equal(t, storage.ErrNotFound, err)
 
-	bs2 := s.FindBranches(gid)
+	bs2, err := s.FindBranches(ctx, gid)
+	assert.Nil(t, err)
 	assert.Equal(t, len(bs2), int(1))
 	assert.Equal(t, "01", bs2[0].BranchID)
 
-	s.LockGlobalSaveBranches(gid, g.Status, []storage.TransBranchStore{bs[1]}, -1)
-	bs3 := s.FindBranches(gid)
+	err = s.LockGlobalSaveBranches(ctx, gid, g.Status, []storage.TransBranchStore{bs[1]}, -1)
+	assert.Nil(t, err)
+	bs3, err := s.FindBranches(ctx, gid)
+	assert.Nil(t, err)
 	assert.Equal(t, 2, len(bs3))
 	assert.Equal(t, "02", bs3[1].BranchID)
 	assert.Equal(t, "01", bs3[0].BranchID)
 
-	err := dtmimp.CatchP(func() {
-		s.LockGlobalSaveBranches(g.Gid, "submitted", []storage.TransBranchStore{bs[1]}, 1)
-	})
+	err = s.LockGlobalSaveBranches(ctx, g.Gid, "submitted", []storage.TransBranchStore{bs[1]}, 1)
 	assert.Equal(t, storage.ErrNotFound, err)
 
-	s.ChangeGlobalStatus(g, "succeed", []string{}, true)
+	assert.Nil(t, s.ChangeGlobalStatus(ctx, g, "succeed", []string{}, true))
+}
+
+func TestStoreCanceled(t *testing.T) {
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err := registry.GetStore().FindTransGlobalStore(canceled, dtmimp.GetFuncName())
+	assert.True(t, errors.Is(err, context.Canceled))
 }
 
 func TestStoreChangeStatus(t *testing.T) {
 	gid := dtmimp.GetFuncName()
 	g, s := initTransGlobal(gid)
 	g.Status = "no"
-	err := dtmimp.CatchP(func() {
-		s.ChangeGlobalStatus(g, "submitted", []string{}, false)
-	})
+	err := s.ChangeGlobalStatus(ctx, g, "submitted", []string{}, false)
 	assert.Equal(t, storage.ErrNotFound, err)
 	g.Status = "prepared"
-	s.ChangeGlobalStatus(g, "submitted", []string{}, false)
-	s.ChangeGlobalStatus(g, "succeed", []string{}, true)
+	assert.Nil(t, s.ChangeGlobalStatus(ctx, g, "submitted", []string{}, false))
+	assert.Nil(t, s.ChangeGlobalStatus(ctx, g, "succeed", []string{}, true))
 }
 
 func TestStoreLockTrans(t *testing.T) {
@@ -75,28 +88,34 @@ func TestStoreLockTrans(t *testing.T) {
 	gid := dtmimp.GetFuncName()
 	g, s := initTransGlobal(gid)
 
-	g2 := s.LockOneGlobalTrans(2 * time.Duration(conf.RetryInterval) * time.Second)
+	g2 := lockOneGlobalTrans(t, s, 2*time.Duration(conf.RetryInterval)*time.Second)
 	assert.NotNil(t, g2)
 	assert.Equal(t, gid, g2.Gid)
 
-	s.TouchCronTime(g, 3*conf.RetryInterval, dtmutil.GetNextTime(3*conf.RetryInterval))
-	g2 = s.LockOneGlobalTrans(2 * time.Duration(conf.RetryInterval) * time.Second)
+	assert.Nil(t, s.TouchCronTime(ctx, g, 3*conf.RetryInterval, dtmutil.GetNextTime(3*conf.RetryInterval)))
+	g2 = lockOneGlobalTrans(t, s, 2*time.Duration(conf.RetryInterval)*time.Second)
 	assert.Nil(t, g2)
 
-	s.TouchCronTime(g, 1*conf.RetryInterval, dtmutil.GetNextTime(1*conf.RetryInterval))
-	g2 = s.LockOneGlobalTrans(2 * time.Duration(conf.RetryInterval) * time.Second)
+	assert.Nil(t, s.TouchCronTime(ctx, g, 1*conf.RetryInterval, dtmutil.GetNextTime(1*conf.RetryInterval)))
+	g2 = lockOneGlobalTrans(t, s, 2*time.Duration(conf.RetryInterval)*time.Second)
 	assert.NotNil(t, g2)
 	assert.Equal(t, gid, g2.Gid)
 
-	s.ChangeGlobalStatus(g, "succeed", []string{}, true)
-	g2 = s.LockOneGlobalTrans(2 * time.Duration(conf.RetryInterval) * time.Second)
+	assert.Nil(t, s.ChangeGlobalStatus(ctx, g, "succeed", []string{}, true))
+	g2 = lockOneGlobalTrans(t, s, 2*time.Duration(conf.RetryInterval)*time.Second)
 	assert.Nil(t, g2)
 }
 
+func lockOneGlobalTrans(t *testing.T, s storage.Store, expireIn time.Duration) *storage.TransGlobalStore {
+	g, err := s.LockOneGlobalTrans(ctx, expireIn)
+	assert.Nil(t, err)
+	return g
+}
+
 func TestStoreResetCronTime(t *testing.T) {
 	s := registry.GetStore()
 	testStoreResetCronTime(t, dtmimp.GetFuncName(), func(timeout int64, limit int64) (int64, bool, error) {
-		return s.ResetCronTime(time.Duration(timeout)*time.Second, limit)
+		return s.ResetCronTime(ctx, time.Duration(timeout)*time.Second, limit)
 	})
 }
 
@@ -118,7 +137,7 @@ func testStoreResetCronTime(t *testing.T, funcName string, resetCronHandler func
 	_, _ = initTransGlobalByNextCronTime(gid, time.Now().Add(time.Duration(afterSeconds-10)*time.Second))
 
 	// Not Found
-	g := s.LockOneGlobalTrans(time.Duration(lockExpireIn) * time.Second)
+	g := lockOneGlobalTrans(t, s, time.Duration(lockExpireIn)*time.Second)
 	assert.Nil(t, g)
 
 	// Reset limit-1 count
@@ -128,13 +147,13 @@ func testStoreResetCronTime(t *testing.T, funcName string, resetCronHandler func
 	assert.Nil(t, err)
 	// Found limit-1 count
 	for i = 0; i < limit-1; i++ {
-		g = s.LockOneGlobalTrans(time.Duration(lockExpireIn) * time.Second)
+		g = lockOneGlobalTrans(t, s, time.Duration(lockExpireIn)*time.Second)
 		assert.NotNil(t, g)
-		s.ChangeGlobalStatus(g, "succeed", []string{}, true)
+		assert.Nil(t, s.ChangeGlobalStatus(ctx, g, "succeed", []string{}, true))
 	}
 
 	// Not Found
-	g = s.LockOneGlobalTrans(time.Duration(lockExpireIn) * time.Second)
+	g = lockOneGlobalTrans(t, s, time.Duration(lockExpireIn)*time.Second)
 	assert.Nil(t, g)
 
 	// Reset 1 count
@@ -143,12 +162,12 @@ func testStoreResetCronTime(t *testing.T, funcName string, resetCronHandler func
 	assert.Equal(t, succeedCount, int64(1))
 	assert.Nil(t, err)
 	// Found 1 count
-	g = s.LockOneGlobalTrans(time.Duration(lockExpireIn) * time.Second)
+	g = lockOneGlobalTrans(t, s, time.Duration(lockExpireIn)*time.Second)
 	assert.NotNil(t, g)
-	s.ChangeGlobalStatus(g, "succeed", []string{}, true)
+	assert.Nil(t, s.ChangeGlobalStatus(ctx, g, "succeed", []string{}, true))
 
 	// Not Found
-	g = s.LockOneGlobalTrans(time.Duration(lockExpireIn) * time.Second)
+	g = lockOneGlobalTrans(t, s, time.Duration(lockExpireIn)*time.Second)
 	assert.Nil(t, g)
 
 	// reduce the resetTimeTimeout, Reset 1 count
@@ -157,12 +176,12 @@ func testStoreResetCronTime(t *testing.T, funcName string, resetCronHandler func
 	assert.Equal(t, succeedCount, int64(1))
 	assert.Nil(t, err)
 	// Found 1 count
-	g = s.LockOneGlobalTrans(time.Duration(lockExpireIn) * time.Second)
+	g = lockOneGlobalTrans(t, s, time.Duration(lockExpireIn)*time.Second)
 	assert.NotNil(t, g)
-	s.ChangeGlobalStatus(g, "succeed", []string{}, true)
+	assert.Nil(t, s.ChangeGlobalStatus(ctx, g, "succeed", []string{}, true))
 
 	// Not Found
-	g = s.LockOneGlobalTrans(time.Duration(lockExpireIn) * time.Second)
+	g = lockOneGlobalTrans(t, s, time.Duration(lockExpireIn)*time.Second)
 	assert.Nil(t, g)
 
 	// Not Found
@@ -174,7 +193,23 @@ func testStoreResetCronTime(t *testing.T, funcName string, resetCronHandler func
 
 func TestUpdateBranches(t *testing.T) {
 	if !conf.Store.IsDB() {
-		_, err := registry.GetStore().UpdateBranches(nil, nil)
+		_, err := registry.GetStore().UpdateBranches(ctx, nil, nil)
 		assert.Nil(t, err)
 	}
 }
+
+func TestStoreRedisStatsExpired(t *testing.T) {
+	if conf.Store.Driver != config.Redis {
+		return
+	}
+	gid := dtmimp.GetFuncName()
+	_, s := initTransGlobal(gid)
+	prefix := conf.Store.RedisPrefix
+	assert.Nil(t, busi.RedisGet().Del(ctx, prefix+"_g_"+gid, prefix+"_b_"+gid, prefix+"_s_"+gid).Err()) // expired by redis
+
+	_, err := s.GetTransStats(ctx, 10)
+	assert.Nil(t, err)
+	_, err = busi.RedisGet().ZScore(ctx, prefix+"_so", gid).Result()
+	assert.Equal(t, redis.Nil, err) // pruned when read
+	assert.Nil(t, busi.RedisGet().ZRem(ctx, prefix+"_u", gid).Err())
+}
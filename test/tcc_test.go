@@ -102,3 +102,13 @@ func TestTccHeaders(t *testing.T) {
 	assert.Equal(t, StatusSucceed, getTransStatus(gid))
 	assert.Equal(t, []string{StatusPrepared, StatusSucceed}, getBranchesStatus(gid))
 }
+
+func TestTccScheduleRejected(t *testing.T) {
+	gid := dtmimp.GetFuncName()
+	err := dtmcli.TccGlobalTransaction2(dtmutil.DefaultHTTPServer, gid, func(t *dtmcli.Tcc) {
+		t.WithDelay(10)
+	}, func(tcc *dtmcli.Tcc) (*resty.Response, error) {
+		return nil, nil
+	})
+	assert.Error(t, err)
+}
@@ -26,18 +26,16 @@ func TestWorkflowBranchConflict(t *testing.T) {
 		Status:       dtmcli.StatusPrepared,
 		NextCronTime: &now,
 	}
-	err := store.MaySaveNewTrans(g, []storage.TransBranchStore{
+	err := store.MaySaveNewTrans(ctx, g, []storage.TransBranchStore{
 		{
 			BranchID: "00",
 			Op:       dtmimp.OpAction,
 		},
 	})
 	assert.Nil(t, err)
-	err = dtmimp.CatchP(func() {
-		store.LockGlobalSaveBranches(gid, dtmcli.StatusPrepared, []storage.TransBranchStore{
-			{BranchID: "00", Op: dtmimp.OpAction},
-		}, -1)
-	})
+	err = store.LockGlobalSaveBranches(ctx, gid, dtmcli.StatusPrepared, []storage.TransBranchStore{
+		{BranchID: "00", Op: dtmimp.OpAction},
+	}, -1)
 	assert.Error(t, err)
-	store.ChangeGlobalStatus(g, StatusSucceed, []string{}, true)
+	assert.Nil(t, store.ChangeGlobalStatus(ctx, g, StatusSucceed, []string{}, true))
 }